@@ -39,6 +39,12 @@ func TestParseFile(t *testing.T) {
 		// frem constant expression.
 		{path: "testdata/expr_frem.ll"},
 
+		// LLVM 10+ types and constants.
+		{path: "testdata/poison.ll"},
+		{path: "testdata/bfloat.ll"},
+		{path: "testdata/x86_amx.ll"},
+		{path: "testdata/vscale.ll"},
+
 		// LLVM IR compatability.
 		{path: "../testdata/llvm/test/Bitcode/compatibility.ll"},
 
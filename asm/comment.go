@@ -0,0 +1,260 @@
+package asm
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/llir/ll/ast"
+	"github.com/llir/llvm/ir"
+)
+
+// === [ Comments ] ============================================================
+
+// comment is a ';' comment of the input.
+type comment struct {
+	// Start offset of the comment in the input.
+	start int
+	// Comment text, including the ';' prefix and excluding the line break.
+	text string
+	// Comment occurs on the same line as preceding code.
+	trailing bool
+	// Comment follows the label comment of an unnamed basic block on the same
+	// line (e.g. "; preds = %0").
+	label bool
+}
+
+// anchor is an IR entity with which comments may be associated, located by the
+// start offset of its AST node.
+type anchor struct {
+	// Start offset of the AST node in the input.
+	start int
+	// End offset of the AST node in the input; only set for function
+	// definitions.
+	end int
+	// IR entity.
+	entity interface{}
+}
+
+// reLabelComment matches the basic block label comments generated for unnamed
+// basic blocks (e.g. "; <label>:0"), which are omitted as they are output for
+// unnamed basic blocks. The first submatch is the comment following the label
+// comment on the same line, if any (e.g. "; <label>:1:    ; preds = %0").
+var reLabelComment = regexp.MustCompile(`^; <label>:[0-9]+:?[ \t]*(;.*)?$`)
+
+// translateComments associates the comments of the given input with the IR
+// entities translated from the given AST module.
+//
+// Comments on lines of their own are associated with the innermost subsequent
+// entity, or as final comments with the enclosing function body or module if
+// no such entity is present. Comments following code on the same line are
+// associated as trailing comments with the innermost entity starting before the
+// comment.
+func (gen *generator) translateComments(old *ast.Module, content string) {
+	comments := scanComments(content)
+	if len(comments) == 0 {
+		return
+	}
+	anchors := gen.anchors(old)
+	// Sort anchors by start offset, keeping outer entities before inner entities
+	// with equal start offset (e.g. unnamed basic blocks before their first
+	// instruction).
+	sort.SliceStable(anchors, func(i, j int) bool {
+		return anchors[i].start < anchors[j].start
+	})
+	get := func(entity interface{}) *ir.Comments {
+		if gen.m.Comments == nil {
+			gen.m.Comments = make(ir.CommentMap)
+		}
+		c, ok := gen.m.Comments[entity]
+		if !ok {
+			c = &ir.Comments{}
+			gen.m.Comments[entity] = c
+		}
+		return c
+	}
+	// enclosing returns the function definition preceding the anchor at index i
+	// if its body contains the given offset, and nil otherwise.
+	enclosing := func(i, offset int) *anchor {
+		for j := i - 1; j >= 0; j-- {
+			if _, ok := anchors[j].entity.(*ir.Function); ok {
+				if offset < anchors[j].end {
+					return &anchors[j]
+				}
+				return nil
+			}
+		}
+		return nil
+	}
+	for _, c := range comments {
+		// Index of the first anchor starting after the comment.
+		i := sort.Search(len(anchors), func(i int) bool {
+			return anchors[i].start > c.start
+		})
+		switch {
+		case c.label:
+			// Trailing comment of the subsequent unnamed basic block.
+			if i < len(anchors) {
+				if block, ok := anchors[i].entity.(*ir.BasicBlock); ok {
+					addTrailing(get(block), c.text)
+					continue
+				}
+			}
+		case c.trailing && i > 0:
+			// Innermost entity starting before the comment.
+			entity := anchors[i-1].entity
+			if _, ok := entity.(*ir.Function); !ok {
+				// Comment following the end of a function body.
+				if a := enclosing(i, anchors[i-1].start); a != nil && c.start >= a.end {
+					entity = a.entity
+				}
+			}
+			addTrailing(get(entity), c.text)
+			continue
+		}
+		// Final comments of the enclosing function body.
+		if a := enclosing(i, c.start); a != nil && (i == len(anchors) || anchors[i].start >= a.end) {
+			get(a.entity).Final = append(get(a.entity).Final, c.text)
+			continue
+		}
+		// Final comments of the module.
+		if i == len(anchors) {
+			get(gen.m).Final = append(get(gen.m).Final, c.text)
+			continue
+		}
+		// Innermost entity starting after the comment; e.g. the first instruction
+		// of an unnamed basic block rather than the basic block.
+		for i+1 < len(anchors) && anchors[i+1].start == anchors[i].start {
+			i++
+		}
+		get(anchors[i].entity).Leading = append(get(anchors[i].entity).Leading, c.text)
+	}
+}
+
+// addTrailing sets the trailing comment of the given comments, or appends the
+// comment to the leading comments if a trailing comment is already present
+// (e.g. for entities spanning multiple lines).
+func addTrailing(c *ir.Comments, text string) {
+	if len(c.Trailing) > 0 {
+		c.Leading = append(c.Leading, text)
+		return
+	}
+	c.Trailing = text
+}
+
+// anchors returns the IR entities, with which comments may be associated,
+// translated from the given AST module.
+func (gen *generator) anchors(old *ast.Module) []anchor {
+	var anchors []anchor
+	for _, def := range old.TargetDefs() {
+		var v interface{}
+		switch def.(type) {
+		case *ast.SourceFilename:
+			v = &gen.m.SourceFilename
+		case *ast.TargetDataLayout:
+			v = &gen.m.DataLayout
+		case *ast.TargetTriple:
+			v = &gen.m.TargetTriple
+		default:
+			panic(fmt.Errorf("support for AST target definition %T not yet implemented", def))
+		}
+		anchors = append(anchors, anchor{start: def.LlvmNode().Offset(), entity: v})
+	}
+	// Index of the next module-level inline assembly.
+	asmIndex := 0
+	for _, entity := range old.TopLevelEntities() {
+		start := entity.LlvmNode().Offset()
+		var v interface{}
+		switch entity := entity.(type) {
+		case *ast.ModuleAsm:
+			v = &gen.m.ModuleAsms[asmIndex]
+			asmIndex++
+		case *ast.TypeDef:
+			v = gen.new.typeDefs[getTypeName(localIdent(entity.Name()))]
+		case *ast.ComdatDef:
+			v = gen.new.comdatDefs[comdatName(entity.Name())]
+		case *ast.GlobalDecl:
+			v = gen.new.globals[globalIdent(entity.Name())]
+		case *ast.IndirectSymbolDef:
+			v = gen.new.globals[globalIdent(entity.Name())]
+		case *ast.FuncDecl:
+			v = gen.new.globals[globalIdent(entity.Header().Name())]
+		case *ast.FuncDef:
+			f := gen.new.globals[globalIdent(entity.Header().Name())]
+			v = f
+			anchors = append(anchors, anchor{start: start, end: entity.LlvmNode().Endoffset(), entity: v})
+			anchors = append(anchors, bodyAnchors(f.(*ir.Function), entity.Body())...)
+			continue
+		case *ast.AttrGroupDef:
+			v = gen.new.attrGroupDefs[attrGroupID(entity.ID())]
+		case *ast.NamedMetadataDef:
+			v = gen.new.namedMetadataDefs[metadataName(entity.Name())]
+		case *ast.MetadataDef:
+			v = gen.new.metadataDefs[metadataID(entity.ID())]
+		case *ast.UseListOrder, *ast.UseListOrderBB:
+			// Comments are associated with the subsequent entity.
+			continue
+		default:
+			panic(fmt.Errorf("support for AST top-level entity %T not yet implemented", entity))
+		}
+		anchors = append(anchors, anchor{start: start, entity: v})
+	}
+	return anchors
+}
+
+// bodyAnchors returns the basic blocks, instructions and terminators of the
+// given IR function, located by the AST nodes of the given function body.
+func bodyAnchors(f *ir.Function, old ast.FuncBody) []anchor {
+	var anchors []anchor
+	for i, oldBlock := range old.Blocks() {
+		block := f.Blocks[i]
+		anchors = append(anchors, anchor{start: oldBlock.LlvmNode().Offset(), entity: block})
+		for j, oldInst := range oldBlock.Insts() {
+			anchors = append(anchors, anchor{start: oldInst.LlvmNode().Offset(), entity: block.Insts[j]})
+		}
+		anchors = append(anchors, anchor{start: oldBlock.Term().LlvmNode().Offset(), entity: block.Term})
+	}
+	return anchors
+}
+
+// scanComments returns the ';' comments of the given LLVM IR assembly input, in
+// order of occurrence. Basic block label comments generated for unnamed basic
+// blocks are omitted, apart from comments following them on the same line.
+func scanComments(content string) []comment {
+	var comments []comment
+	// Code precedes the current offset on the same line.
+	code := false
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '\n':
+			code = false
+		case ' ', '\t', '\r':
+			// skip whitespace.
+		case '"':
+			// Skip string literals and quoted identifiers; which contain no
+			// escaped quotes, as '"' is escaped as "\22".
+			if end := strings.IndexByte(content[i+1:], '"'); end != -1 {
+				i += 1 + end
+			}
+			code = true
+		case ';':
+			end := strings.IndexByte(content[i:], '\n')
+			if end == -1 {
+				end = len(content) - i
+			}
+			text := strings.TrimRight(content[i:i+end], " \t\r")
+			if loc := reLabelComment.FindStringSubmatchIndex(text); loc != nil {
+				if loc[2] != -1 {
+					comments = append(comments, comment{start: i + loc[2], text: text[loc[2]:], label: true})
+				}
+			} else {
+				comments = append(comments, comment{start: i, text: text, trailing: code})
+			}
+			i += end - 1
+		default:
+			code = true
+		}
+	}
+	return comments
+}
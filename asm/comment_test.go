@@ -0,0 +1,96 @@
+package asm
+
+import (
+	"testing"
+)
+
+func TestComments(t *testing.T) {
+	const input = `; ModuleID = 'foo.c'
+source_filename = "foo.c" ; source
+; Data layout.
+target datalayout = "e-m:e-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu" ; triple
+
+module asm "nop" ; asm
+
+; Global variable containing ';'.
+@s = global [3 x i8] c";x\00" ; trailing
+
+; RUN: opt -S %s | FileCheck %s
+
+; CHECK-LABEL: @f
+define i32 @f(i32 %x) { ; function
+	; CHECK: add
+	%y = add i32 %x, 1 ; increment
+	br label %exit
+
+; Exit block.
+exit: ; preds = %0
+	; CHECK-NEXT: ret
+	ret i32 %y ; return
+	; end of body
+}
+
+define void @g() {
+	br label %1
+
+; <label>:1:                                      ; preds = %0
+	ret void
+}
+
+; end of file
+`
+	const want = `; ModuleID = 'foo.c'
+source_filename = "foo.c" ; source
+; Data layout.
+target datalayout = "e-m:e-i64:64-f80:128-n8:16:32:64-S128"
+target triple = "x86_64-unknown-linux-gnu" ; triple
+
+module asm "nop" ; asm
+
+; Global variable containing ';'.
+@s = global [3 x i8] c";x\00" ; trailing
+
+; RUN: opt -S %s | FileCheck %s
+; CHECK-LABEL: @f
+define i32 @f(i32 %x) { ; function
+; <label>:0
+	; CHECK: add
+	%y = add i32 %x, 1 ; increment
+	br label %exit
+
+; Exit block.
+exit: ; preds = %0
+	; CHECK-NEXT: ret
+	ret i32 %y ; return
+	; end of body
+}
+
+define void @g() {
+; <label>:0
+	br label %1
+
+; <label>:1
+	; preds = %0
+	ret void
+}
+
+; end of file
+`
+	m, err := ParseString("comments.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	got := m.String()
+	if got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	// Check that formatting is idempotent.
+	m, err = ParseString("comments.ll", got)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch after round-trip; expected:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -36,6 +36,8 @@ func (gen *generator) irConstant(t types.Type, old ast.Constant) (constant.Const
 		return gen.irZeroInitializerConst(t, old)
 	case *ast.UndefConst:
 		return gen.irUndefConst(t, old)
+	case *ast.PoisonConst:
+		return gen.irPoisonConst(t, old)
 	case *ast.BlockAddressConst:
 		return gen.irBlockAddressConst(t, old)
 	case *ast.GlobalIdent:
@@ -209,6 +211,12 @@ func (gen *generator) irUndefConst(t types.Type, old *ast.UndefConst) (*constant
 	return constant.NewUndef(t), nil
 }
 
+// --- [ Poison Values ] -------------------------------------------------------
+
+func (gen *generator) irPoisonConst(t types.Type, old *ast.PoisonConst) (*constant.Poison, error) {
+	return constant.NewPoison(t), nil
+}
+
 // --- [ Addresses of Basic Blocks ] -------------------------------------------
 
 func (gen *generator) irBlockAddressConst(t types.Type, old *ast.BlockAddressConst) (*constant.BlockAddress, error) {
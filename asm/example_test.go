@@ -226,5 +226,6 @@ func Example() {
 	//     MetadataDefs:      nil,
 	//     UseListOrders:     nil,
 	//     UseListOrderBBs:   nil,
+	//     Comments:          {},
 	// }
 }
@@ -0,0 +1,240 @@
+package asm
+
+import (
+	"github.com/llir/ll/ast"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Fragments ] ===========================================================
+
+// Scope specifies the identifiers which may be referenced from LLVM IR
+// fragments (see ParseConstant and ParseInstruction).
+type Scope struct {
+	// (optional) Module whose type definitions, Comdat definitions, global
+	// variables, functions, aliases, IFuncs, attribute group definitions and
+	// metadata definitions may be referenced.
+	Module *ir.Module
+	// (optional) Function whose parameters, basic blocks and local variables may
+	// be referenced. Unnamed local variables of the function are assigned IDs
+	// if not already assigned.
+	Func *ir.Function
+}
+
+// fragmentName is the name of the top-level entity wrapping the LLVM IR
+// fragment being parsed.
+const fragmentName = `"llir.fragment"`
+
+// ParseType parses the given LLVM IR type (e.g. "{ i32, [4 x i8]* }"). Named
+// types may not be referenced; use ParseConstant or ParseInstruction to refer
+// to the type definitions of a module.
+func ParseType(s string) (types.Type, error) {
+	content := "%" + fragmentName + " = type " + s
+	old, amx, err := parseFragment(content)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	def, ok := old.TopLevelEntities()[0].(*ast.TypeDef)
+	if !ok {
+		return nil, errors.Errorf("invalid type %q", s)
+	}
+	if _, ok := def.Typ().(*ast.OpaqueType); ok {
+		return nil, errors.Errorf("invalid type %q; opaque types are only allowed in type definitions", s)
+	}
+	gen := newScopeGenerator(nil)
+	gen.amx = amx
+	t, err := gen.irType(def.Typ())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return t, nil
+}
+
+// ParseConstant parses the given typed LLVM IR constant (e.g. "i32 42" or
+// "i8* bitcast (i32* @x to i8*)"), resolving identifiers against the module of
+// the given scope. The scope may be nil.
+func ParseConstant(s string, scope *Scope) (constant.Constant, error) {
+	content := "@" + fragmentName + " = global " + s
+	old, amx, err := parseFragment(content)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	def, ok := old.TopLevelEntities()[0].(*ast.GlobalDecl)
+	if !ok {
+		return nil, errors.Errorf("invalid constant %q", s)
+	}
+	init, ok := def.Init()
+	if !ok {
+		return nil, errors.Errorf("invalid constant %q", s)
+	}
+	gen := newScopeGenerator(scope)
+	gen.amx = amx
+	t, err := gen.irType(def.ContentType())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	c, err := gen.irConstant(t, init)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := gen.fixBlockAddresses(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return c, nil
+}
+
+// ParseInstruction parses the given LLVM IR instruction (e.g.
+// "%x = add i32 %a, 1"), resolving identifiers against the module and function
+// of the given scope. The scope may be nil.
+//
+// The parsed instruction is not added to the function of the scope. A local
+// variable of the scope with the same name as the result of the instruction is
+// shadowed by the instruction.
+func ParseInstruction(s string, scope *Scope) (ir.Instruction, error) {
+	content := "define void @" + fragmentName + "() {\n" + s + "\nunreachable\n}"
+	old, amx, err := parseFragment(content)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	def, ok := old.TopLevelEntities()[0].(*ast.FuncDef)
+	if !ok {
+		return nil, errors.Errorf("invalid instruction %q", s)
+	}
+	blocks := def.Body().Blocks()
+	if len(blocks) != 1 || len(blocks[0].Insts()) != 1 {
+		return nil, errors.Errorf("invalid instruction %q; expected a single instruction", s)
+	}
+	oldInst := blocks[0].Insts()[0]
+	gen := newScopeGenerator(scope)
+	gen.amx = amx
+	fgen := newFuncGen(gen, &ir.Function{})
+	if scope != nil && scope.Func != nil {
+		if err := fgen.addScopeLocals(scope.Func); err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	inst, err := fgen.newIRInst(oldInst)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if def, ok := oldInst.(*ast.LocalDefInst); ok {
+		fgen.ls[localIdent(def.Name())] = inst.(value.Value)
+	}
+	if _, err := fgen.astToIRInst(inst, oldInst); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if err := gen.fixBlockAddresses(); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return inst, nil
+}
+
+// parseFragment parses the given LLVM IR assembly, wrapping an LLVM IR fragment
+// in a top-level entity, into an AST module. The offsets of AST MMX types lexed
+// from x86_amx keywords are returned as well (see replaceAMX).
+func parseFragment(content string) (*ast.Module, map[int]bool, error) {
+	input, amx := replaceAMX(content)
+	tree, err := ast.Parse("<fragment>", input)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to parse %q into AST", content)
+	}
+	old := ast.ToLlvmNode(tree.Root()).(*ast.Module)
+	if len(old.TopLevelEntities()) != 1 {
+		return nil, nil, errors.Errorf("invalid fragment %q", content)
+	}
+	return old, amx, nil
+}
+
+// newScopeGenerator returns a new generator for translating LLVM IR fragments
+// from AST to IR representation, with the top-level entities of the module of
+// the given scope indexed. The scope may be nil.
+func newScopeGenerator(scope *Scope) *generator {
+	gen := newGenerator()
+	if scope == nil || scope.Module == nil {
+		return gen
+	}
+	m := scope.Module
+	for _, t := range m.TypeDefs {
+		gen.new.typeDefs[t.Name()] = t
+	}
+	for _, def := range m.ComdatDefs {
+		gen.new.comdatDefs[def.Name] = def
+	}
+	for _, g := range m.Globals {
+		gen.new.globals[g.GlobalIdent] = g
+	}
+	for _, f := range m.Funcs {
+		gen.new.globals[f.GlobalIdent] = f
+	}
+	for _, alias := range m.Aliases {
+		gen.new.globals[alias.GlobalIdent] = alias
+	}
+	for _, ifunc := range m.IFuncs {
+		gen.new.globals[ifunc.GlobalIdent] = ifunc
+	}
+	for _, def := range m.AttrGroupDefs {
+		gen.new.attrGroupDefs[def.ID] = def
+	}
+	for _, def := range m.NamedMetadataDefs {
+		gen.new.namedMetadataDefs[def.Name] = def
+	}
+	for _, def := range m.MetadataDefs {
+		gen.new.metadataDefs[def.ID] = def
+	}
+	return gen
+}
+
+// addScopeLocals adds the function parameters, basic blocks and local
+// variables of the given function to the local variables of the function
+// generator.
+func (fgen *funcGen) addScopeLocals(f *ir.Function) error {
+	if len(f.Blocks) > 0 {
+		if err := f.AssignIDs(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	add := func(v interface{}) {
+		n, ok := v.(value.Named)
+		if !ok || isVoidValue(n) {
+			return
+		}
+		l, ok := v.(interface {
+			ID() int64
+			IsUnnamed() bool
+		})
+		if !ok {
+			return
+		}
+		ident := ir.LocalIdent{LocalName: n.Name()}
+		if l.IsUnnamed() {
+			ident = ir.LocalIdent{LocalID: l.ID()}
+		}
+		fgen.ls[ident] = n
+	}
+	for _, param := range f.Params {
+		add(param)
+	}
+	for _, block := range f.Blocks {
+		add(block)
+		for _, inst := range block.Insts {
+			add(inst)
+		}
+		add(block.Term)
+	}
+	return nil
+}
+
+// fixBlockAddresses resolves the basic blocks referenced by the blockaddress
+// constants translated by the generator.
+func (gen *generator) fixBlockAddresses() error {
+	for _, c := range gen.todo {
+		if err := fixBlockAddressConst(c); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	gen.todo = nil
+	return nil
+}
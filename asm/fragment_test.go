@@ -0,0 +1,148 @@
+package asm
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir"
+)
+
+func TestParseType(t *testing.T) {
+	golden := []struct {
+		in   string
+		want string
+	}{
+		{in: "i32", want: "i32"},
+		{in: "{ i32, [4 x i8]* }", want: "{ i32, [4 x i8]* }"},
+		{in: "void (i8*, ...)", want: "void (i8*, ...)"},
+		{in: "<4 x float>", want: "<4 x float>"},
+		{in: "<vscale x 2 x i64>", want: "<vscale x 2 x i64>"},
+		{in: "bfloat", want: "bfloat"},
+		{in: "x86_amx", want: "x86_amx"},
+		{in: "x86_mmx", want: "x86_mmx"},
+	}
+	for _, g := range golden {
+		typ, err := ParseType(g.in)
+		if err != nil {
+			t.Errorf("unable to parse type %q; %v", g.in, err)
+			continue
+		}
+		if got := typ.String(); got != g.want {
+			t.Errorf("type mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+	// Check that invalid types are rejected.
+	for _, in := range []string{"opaque", "%T", "i32 42"} {
+		if _, err := ParseType(in); err == nil {
+			t.Errorf("expected error when parsing type %q", in)
+		}
+	}
+}
+
+func TestParseConstant(t *testing.T) {
+	const input = `
+%T = type { i32, i8* }
+
+@x = global i32 0
+
+define void @f() {
+exit:
+	ret void
+}
+`
+	m, err := ParseString("<input>", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	scope := &Scope{Module: m}
+	golden := []struct {
+		in   string
+		want string
+	}{
+		{in: "i32 42", want: "i32 42"},
+		{in: "i32 poison", want: "i32 poison"},
+		{in: "i8* bitcast (i32* @x to i8*)", want: "i8* bitcast (i32* @x to i8*)"},
+		{in: "%T { i32 1, i8* null }", want: "%T { i32 1, i8* null }"},
+		{in: "i8* blockaddress(@f, %exit)", want: "i8* blockaddress(@f, %exit)"},
+	}
+	for _, g := range golden {
+		c, err := ParseConstant(g.in, scope)
+		if err != nil {
+			t.Errorf("unable to parse constant %q; %v", g.in, err)
+			continue
+		}
+		if got := c.String(); got != g.want {
+			t.Errorf("constant mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+	// Check that global identifiers are resolved against the module.
+	c, err := ParseConstant("i32* @x", scope)
+	if err != nil {
+		t.Fatalf("unable to parse constant; %v", err)
+	}
+	if c != m.Globals[0] {
+		t.Errorf("global identifier mismatch; expected %v, got %v", m.Globals[0], c)
+	}
+	// Check that unresolved identifiers are rejected.
+	if _, err := ParseConstant("i32* @x", nil); err == nil {
+		t.Errorf("expected error when parsing constant with unresolved global identifier")
+	}
+}
+
+func TestParseInstruction(t *testing.T) {
+	const input = `
+declare i32 @g(i32)
+
+define i32 @f(i32 %a, i32) {
+entry:
+	%b = add i32 %a, %0
+	br label %exit
+
+exit:
+	ret i32 %b
+}
+`
+	m, err := ParseString("<input>", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	f := m.Funcs[1]
+	scope := &Scope{Module: m, Func: f}
+	golden := []struct {
+		in   string
+		want string
+	}{
+		{in: "%x = add i32 %a, 1", want: "%x = add i32 %a, 1"},
+		{in: "%y = mul i32 %b, %0", want: "%y = mul i32 %b, %0"},
+		{in: "%z = call i32 @g(i32 %a)", want: "%z = call i32 @g(i32 %a)"},
+		{in: "%p = phi i32 [ %a, %entry ], [ %b, %exit ]", want: "%p = phi i32 [ %a, %entry ], [ %b, %exit ]"},
+		{in: "store i32 %b, i32* null", want: "store i32 %b, i32* null"},
+	}
+	for _, g := range golden {
+		inst, err := ParseInstruction(g.in, scope)
+		if err != nil {
+			t.Errorf("unable to parse instruction %q; %v", g.in, err)
+			continue
+		}
+		if got := inst.Def(); got != g.want {
+			t.Errorf("instruction mismatch; expected %q, got %q", g.want, got)
+		}
+	}
+	// Check that local identifiers are resolved against the function.
+	inst, err := ParseInstruction("%x = add i32 %a, %b", scope)
+	if err != nil {
+		t.Fatalf("unable to parse instruction; %v", err)
+	}
+	add, ok := inst.(*ir.InstAdd)
+	if !ok {
+		t.Fatalf("invalid instruction type; expected *ir.InstAdd, got %T", inst)
+	}
+	if add.X != f.Params[0] || add.Y != f.Blocks[0].Insts[0].(*ir.InstAdd) {
+		t.Errorf("operand mismatch; expected %%a and %%b of function %s", f.Ident())
+	}
+	// Check that invalid fragments are rejected.
+	for _, in := range []string{"%x = add i32 %c, 1", "ret void", "%x = add i32 1, 2\n%y = add i32 3, 4"} {
+		if _, err := ParseInstruction(in, scope); err == nil {
+			t.Errorf("expected error when parsing instruction %q", in)
+		}
+	}
+}
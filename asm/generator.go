@@ -17,6 +17,8 @@ type generator struct {
 	old oldIndex
 	// index of IR top-level entities.
 	new newIndex
+	// Offsets of AST MMX types lexed from x86_amx keywords (see replaceAMX).
+	amx map[int]bool
 
 	// TOOD: add rw mutex to gen.todo for access to blockaddress constant.
 
@@ -63,7 +65,6 @@ type oldIndex struct {
 	//
 	// The value has one of the following types.
 	//    *ast.GlobalDecl
-	//    *ast.GlobalDef
 	//    *ast.AliasDef
 	//    *ast.IFuncDef
 	//    *ast.FuncDecl
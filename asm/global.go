@@ -50,21 +50,6 @@ func (gen *generator) newGlobal(ident ir.GlobalIdent, old ast.LlvmNode) (constan
 			new.Typ.AddrSpace = irAddrSpace(n)
 		}
 		return new, nil
-	case *ast.GlobalDef:
-		new := &ir.Global{}
-		setGlobalIdent(new, ident)
-		// Content type.
-		contentType, err := gen.irType(old.ContentType())
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		new.ContentType = contentType
-		new.Typ = types.NewPointer(new.ContentType)
-		// (optional) Address space.
-		if n, ok := old.AddrSpace(); ok {
-			new.Typ.AddrSpace = irAddrSpace(n)
-		}
-		return new, nil
 	case *ast.IndirectSymbolDef:
 		// Content type.
 		contentType, err := gen.irType(old.ContentType())
@@ -141,14 +126,6 @@ func (gen *generator) translateGlobals() error {
 			if err := gen.translateGlobalDecl(new, old); err != nil {
 				return errors.WithStack(err)
 			}
-		case *ast.GlobalDef:
-			new, ok := v.(*ir.Global)
-			if !ok {
-				panic(fmt.Errorf("invalid global definition type; expected *ir.Global, got %T", v))
-			}
-			if err := gen.translateGlobalDef(new, old); err != nil {
-				return errors.WithStack(err)
-			}
 		case *ast.IndirectSymbolDef:
 			kind := old.IndirectSymbolKind().Text()
 			switch kind {
@@ -194,82 +171,14 @@ func (gen *generator) translateGlobals() error {
 	return nil
 }
 
-// --- [ Global declarations ] -------------------------------------------------
+// --- [ Global declarations and definitions ] ---------------------------------
 
-// translateGlobalDecl translates the given AST global declarations to IR.
+// translateGlobalDecl translates the given AST global declaration or definition
+// to IR.
 func (gen *generator) translateGlobalDecl(new *ir.Global, old *ast.GlobalDecl) error {
-	// (optional) Linkage.
-	new.Linkage = asmenum.LinkageFromString(old.ExternLinkage().Text())
-	// (optional) Preemption.
-	if n, ok := old.Preemption(); ok {
-		new.Preemption = asmenum.PreemptionFromString(n.Text())
-	}
-	// (optional) Visibility.
-	if n, ok := old.Visibility(); ok {
-		new.Visibility = asmenum.VisibilityFromString(n.Text())
-	}
-	// (optional) DLL storage class.
-	if n, ok := old.DLLStorageClass(); ok {
-		new.DLLStorageClass = asmenum.DLLStorageClassFromString(n.Text())
-	}
-	// (optional) Thread local storage model.
-	if n, ok := old.ThreadLocal(); ok {
-		new.TLSModel = irTLSModelFromThreadLocal(n)
-	}
-	// (optional) Unnamed address.
-	if n, ok := old.UnnamedAddr(); ok {
-		new.UnnamedAddr = asmenum.UnnamedAddrFromString(n.Text())
-	}
-	// (optional) Address space: handled in newGlobal.
-	// (optional) Externally initialized.
-	_, externallyInitialized := old.ExternallyInitialized()
-	new.ExternallyInitialized = externallyInitialized
-	// Immutability of global variable (constant or global).
-	new.Immutable = irImmutable(old.Immutable())
-	// Content type: handled in newGlobal.
-	// (optional) Section name.
-	if n, ok := old.Section(); ok {
-		new.Section = stringLit(n.Name())
-	}
-	// (optional) Comdat.
-	if n, ok := old.Comdat(); ok {
-		// When comdat name is omitted, the global name is used as an implicit
-		// comdat name.
-		name := new.Name()
-		if n, ok := n.Name(); ok {
-			name = comdatName(n)
-		}
-		def, ok := gen.new.comdatDefs[name]
-		if !ok {
-			return errors.Errorf("unable to locate comdat identifier %q used in global declaration of %q", enc.Comdat(name), new.Ident())
-		}
-		new.Comdat = def
-	}
-	// (optional) Alignment.
-	if n, ok := old.Align(); ok {
-		new.Align = irAlign(n)
-	}
-	// (optional) Metadata.
-	md, err := gen.irMetadataAttachments(old.Metadata())
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	new.Metadata = md
-	// (optional) Function attributes.
-	for _, oldFuncAttr := range old.FuncAttrs() {
-		funcAttr := gen.irFuncAttribute(oldFuncAttr)
-		new.FuncAttrs = append(new.FuncAttrs, funcAttr)
-	}
-	return nil
-}
-
-// --- [ Global definitions ] --------------------------------------------------
-
-// translateGlobalDef translates the given AST global definition to IR.
-func (gen *generator) translateGlobalDef(new *ir.Global, old *ast.GlobalDef) error {
 	// (optional) Linkage.
 	if n, ok := old.Linkage(); ok {
-		new.Linkage = asmenum.LinkageFromString(n.Text())
+		new.Linkage = asmenum.LinkageFromString(n.LlvmNode().Text())
 	}
 	// (optional) Preemption.
 	if n, ok := old.Preemption(); ok {
@@ -298,33 +207,38 @@ func (gen *generator) translateGlobalDef(new *ir.Global, old *ast.GlobalDef) err
 	// Immutability of global variable (constant or global).
 	new.Immutable = irImmutable(old.Immutable())
 	// Content type: handled in newGlobal.
-	// Initial value.
-	init, err := gen.irConstant(new.ContentType, old.Init())
-	if err != nil {
-		return errors.WithStack(err)
-	}
-	new.Init = init
-	// (optional) Section name.
-	if n, ok := old.Section(); ok {
-		new.Section = stringLit(n.Name())
-	}
-	// (optional) Comdat.
-	if n, ok := old.Comdat(); ok {
-		// When comdat name is omitted, the global name is used as an implicit
-		// comdat name.
-		name := new.Name()
-		if n, ok := n.Name(); ok {
-			name = comdatName(n)
+	// (optional) Initial value; only present in global variable definitions.
+	if n, ok := old.Init(); ok {
+		init, err := gen.irConstant(new.ContentType, n)
+		if err != nil {
+			return errors.WithStack(err)
 		}
-		def, ok := gen.new.comdatDefs[name]
-		if !ok {
-			return errors.Errorf("unable to locate comdat identifier %q used in global declaration of %q", enc.Comdat(name), new.Ident())
+		new.Init = init
+	}
+	for _, oldField := range old.GlobalFields() {
+		switch oldField := oldField.(type) {
+		// (optional) Section name.
+		case *ast.Section:
+			new.Section = stringLit(oldField.Name())
+		// (optional) Comdat.
+		case *ast.Comdat:
+			// When comdat name is omitted, the global name is used as an implicit
+			// comdat name.
+			name := new.Name()
+			if n, ok := oldField.Name(); ok {
+				name = comdatName(n)
+			}
+			def, ok := gen.new.comdatDefs[name]
+			if !ok {
+				return errors.Errorf("unable to locate comdat identifier %q used in global declaration of %q", enc.Comdat(name), new.Ident())
+			}
+			new.Comdat = def
+		// (optional) Alignment.
+		case *ast.Align:
+			new.Align = irAlign(*oldField)
+		default:
+			return errors.Errorf("support for global field %q not yet implemented", text(oldField))
 		}
-		new.Comdat = def
-	}
-	// (optional) Alignment.
-	if n, ok := old.Align(); ok {
-		new.Align = irAlign(n)
 	}
 	// (optional) Metadata.
 	md, err := gen.irMetadataAttachments(old.Metadata())
@@ -497,7 +411,7 @@ func (gen *generator) translateFuncHeader(new *ir.Function, old ast.FuncHeader)
 		new.DLLStorageClass = asmenum.DLLStorageClassFromString(n.Text())
 	}
 	// (optional) Calling convention.
-	if n := old.CallingConv(); n.LlvmNode().IsValid() {
+	if n, ok := old.CallingConv(); ok {
 		new.CallingConv = irCallingConv(n)
 	}
 	// (optional) Return attributes.
@@ -532,57 +446,58 @@ func (gen *generator) translateFuncHeader(new *ir.Function, old ast.FuncHeader)
 		new.UnnamedAddr = asmenum.UnnamedAddrFromString(n.Text())
 	}
 	// (optional) Address space: handled in newGlobal.
-	// (optional) Function attributes.
-	for _, oldFuncAttr := range old.FuncAttrs() {
-		funcAttr := gen.irFuncAttribute(oldFuncAttr)
-		new.FuncAttrs = append(new.FuncAttrs, funcAttr)
-	}
-
-	// (optional) Section name.
-	if n, ok := old.Section(); ok {
-		new.Section = stringLit(n.Name())
-	}
-	// (optional) Comdat.
-	if n, ok := old.Comdat(); ok {
-		// When comdat name is omitted, the function name is used as an implicit
-		// comdat name.
-		name := new.Name()
-		if n, ok := n.Name(); ok {
-			name = comdatName(n)
-		}
-		def, ok := gen.new.comdatDefs[name]
-		if !ok {
-			return errors.Errorf("unable to locate comdat identifier %q used in function header of %q", enc.Comdat(name), new.Ident())
-		}
-		new.Comdat = def
-	}
-	// (optional) Garbage collection.
-	if n, ok := old.GCNode(); ok {
-		new.GC = stringLit(n.Name())
-	}
-	// (optional) Prefix.
-	if n, ok := old.Prefix(); ok {
-		prefix, err := gen.irTypeConst(n.TypeConst())
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		new.Prefix = prefix
-	}
-	// (optional) Prologue.
-	if n, ok := old.Prologue(); ok {
-		prologue, err := gen.irTypeConst(n.TypeConst())
-		if err != nil {
-			return errors.WithStack(err)
-		}
-		new.Prologue = prologue
-	}
-	// (optional) Personality.
-	if n, ok := old.Personality(); ok {
-		personality, err := gen.irTypeConst(n.TypeConst())
-		if err != nil {
-			return errors.WithStack(err)
+	for _, oldField := range old.FuncHdrFields() {
+		switch oldField := oldField.(type) {
+		// (optional) Function attributes.
+		case ast.FuncAttribute:
+			funcAttr := gen.irFuncAttribute(oldField)
+			new.FuncAttrs = append(new.FuncAttrs, funcAttr)
+		// (optional) Alignment.
+		case *ast.Align:
+			new.FuncAttrs = append(new.FuncAttrs, irAlign(*oldField))
+		// (optional) Section name.
+		case *ast.Section:
+			new.Section = stringLit(oldField.Name())
+		// (optional) Comdat.
+		case *ast.Comdat:
+			// When comdat name is omitted, the function name is used as an implicit
+			// comdat name.
+			name := new.Name()
+			if n, ok := oldField.Name(); ok {
+				name = comdatName(n)
+			}
+			def, ok := gen.new.comdatDefs[name]
+			if !ok {
+				return errors.Errorf("unable to locate comdat identifier %q used in function header of %q", enc.Comdat(name), new.Ident())
+			}
+			new.Comdat = def
+		// (optional) Garbage collection.
+		case *ast.GCNode:
+			new.GC = stringLit(oldField.Name())
+		// (optional) Prefix.
+		case *ast.Prefix:
+			prefix, err := gen.irTypeConst(oldField.TypeConst())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			new.Prefix = prefix
+		// (optional) Prologue.
+		case *ast.Prologue:
+			prologue, err := gen.irTypeConst(oldField.TypeConst())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			new.Prologue = prologue
+		// (optional) Personality.
+		case *ast.Personality:
+			personality, err := gen.irTypeConst(oldField.TypeConst())
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			new.Personality = personality
+		default:
+			return errors.Errorf("support for function header field %q not yet implemented", text(oldField))
 		}
-		new.Personality = personality
 	}
 	return nil
 }
@@ -338,8 +338,8 @@ func (fgen *funcGen) irExceptionArg(n ast.ExceptionArg) (value.Value, error) {
 }
 
 // irExceptionScope returns the IR exception scope corresponding to the given
-// AST exception scope.
-func (fgen *funcGen) irExceptionScope(n ast.ExceptionScope) (ir.ExceptionScope, error) {
+// AST exception pad.
+func (fgen *funcGen) irExceptionScope(n ast.ExceptionPad) (ir.ExceptionScope, error) {
 	switch n := n.(type) {
 	case *ast.NoneConst:
 		return constant.None, nil
@@ -521,6 +521,14 @@ func irParamAttribute(n ast.ParamAttribute) ir.ParamAttribute {
 		}
 	case *ast.ParamAttr:
 		return asmenum.ParamAttrFromString(n.Text())
+	// The type of byval, sret and inalloca attributes is implied by the pointee
+	// type of the parameter.
+	case *ast.Byval:
+		return enum.ParamAttrByval
+	case *ast.StructRetAttr:
+		return enum.ParamAttrSRet
+	case *ast.InAlloca:
+		return enum.ParamAttrInAlloca
 	default:
 		panic(fmt.Errorf("support for parameter attribute %T not yet implemented", n))
 	}
@@ -538,8 +546,6 @@ func irReturnAttribute(n ast.ReturnAttribute) ir.ReturnAttribute {
 	//		Key:   unquote(n.Key().Text()),
 	//		Value: unquote(n.Val().Text()),
 	//	}
-	case *ast.Align:
-		return ir.Align(uintLit(n.N()))
 	case *ast.Dereferenceable:
 		return ir.Dereferenceable{N: uintLit(n.N())}
 	case *ast.DereferenceableOrNull:
@@ -21,7 +21,7 @@ func (fgen *funcGen) astToIRInstAlloca(inst ir.Instruction, old *ast.AllocaInst)
 		panic(fmt.Errorf("invalid IR instruction for AST instruction; expected *ir.InstAlloca, got %T", inst))
 	}
 	// (optional) In-alloca.
-	_, inAlloca := old.InAlloca()
+	_, inAlloca := old.InAllocatok()
 	i.InAlloca = inAlloca
 	// (optional) Swift error.
 	_, swiftError := old.SwiftError()
@@ -124,13 +124,13 @@ func (fgen *funcGen) astToIRInstSelect(inst ir.Instruction, old *ast.SelectInst)
 	}
 	i.Cond = cond
 	// X operand.
-	x, err := fgen.astToIRTypeValue(old.X())
+	x, err := fgen.astToIRTypeValue(old.ValueTrue())
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
 	i.X = x
 	// Y operand.
-	y, err := fgen.astToIRTypeValue(old.Y())
+	y, err := fgen.astToIRTypeValue(old.ValueFalse())
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -160,7 +160,7 @@ func (fgen *funcGen) astToIRInstCall(inst ir.Instruction, old *ast.CallInst) (*i
 	// (optional) Fast math flags.
 	i.FastMathFlags = irFastMathFlags(old.FastMathFlags())
 	// (optional) Calling convention.
-	if n := old.CallingConv(); n.LlvmNode().IsValid() {
+	if n, ok := old.CallingConv(); ok {
 		i.CallingConv = irCallingConv(n)
 	}
 	// (optional) Return attributes.
@@ -294,7 +294,7 @@ func (fgen *funcGen) astToIRInstCatchPad(inst ir.Instruction, old *ast.CatchPadI
 		panic(fmt.Errorf("invalid IR instruction for AST instruction; expected *ir.InstCatchPad, got %T", inst))
 	}
 	// Exception scope.
-	ident := localIdent(old.Scope())
+	ident := localIdent(old.CatchSwitch())
 	v, ok := fgen.ls[ident]
 	if !ok {
 		return nil, errors.Errorf("unable to locate local identifier %q", ident.Ident())
@@ -331,7 +331,7 @@ func (fgen *funcGen) astToIRInstCleanupPad(inst ir.Instruction, old *ast.Cleanup
 		panic(fmt.Errorf("invalid IR instruction for AST instruction; expected *ir.InstCleanupPad, got %T", inst))
 	}
 	// Exception scope.
-	scope, err := fgen.irExceptionScope(old.Scope())
+	scope, err := fgen.irExceptionScope(old.ParentPad())
 	if err != nil {
 		return nil, errors.WithStack(err)
 	}
@@ -252,7 +252,7 @@ func (fgen *funcGen) newIRValueInst(ident ir.LocalIdent, old ast.ValueInstructio
 		if !ok {
 			panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", maskType))
 		}
-		typ := types.NewVector(mt.Len, xt.ElemType)
+		typ := &types.VectorType{Scalable: mt.Scalable, Len: mt.Len, ElemType: xt.ElemType}
 		return &ir.InstShuffleVector{LocalIdent: ident, Typ: typ}, nil
 	// Aggregate instructions
 	case *ast.ExtractValueInst:
@@ -404,7 +404,7 @@ func (fgen *funcGen) newIRValueInst(ident ir.LocalIdent, old ast.ValueInstructio
 		case *types.IntType, *types.PointerType:
 			typ = types.I1
 		case *types.VectorType:
-			typ = types.NewVector(xType.Len, types.I1)
+			typ = &types.VectorType{Scalable: xType.Scalable, Len: xType.Len, ElemType: types.I1}
 		default:
 			panic(fmt.Errorf("invalid icmp operand type; expected *types.IntType, *types.PointerType or *types.VectorType, got %T", xType))
 		}
@@ -419,7 +419,7 @@ func (fgen *funcGen) newIRValueInst(ident ir.LocalIdent, old ast.ValueInstructio
 		case *types.FloatType:
 			typ = types.I1
 		case *types.VectorType:
-			typ = types.NewVector(xType.Len, types.I1)
+			typ = &types.VectorType{Scalable: xType.Scalable, Len: xType.Len, ElemType: types.I1}
 		default:
 			panic(fmt.Errorf("invalid fcmp operand type; expected *types.FloatType or *types.VectorType, got %T", xType))
 		}
@@ -431,7 +431,7 @@ func (fgen *funcGen) newIRValueInst(ident ir.LocalIdent, old ast.ValueInstructio
 		}
 		return &ir.InstPhi{LocalIdent: ident, Typ: typ}, nil
 	case *ast.SelectInst:
-		typ, err := fgen.gen.irType(old.X().Typ())
+		typ, err := fgen.gen.irType(old.ValueTrue().Typ())
 		if err != nil {
 			return nil, errors.WithStack(err)
 		}
@@ -713,7 +713,7 @@ func (gen *generator) gepType(elemType types.Type, indices []ast.TypeValue) (typ
 			return nil, errors.WithStack(err)
 		}
 		if t, ok := t.(*types.VectorType); ok {
-			return types.NewVector(t.Len, types.NewPointer(e)), nil
+			return &types.VectorType{Scalable: t.Scalable, Len: t.Len, ElemType: types.NewPointer(e)}, nil
 		}
 	}
 	return types.NewPointer(e), nil
@@ -60,7 +60,7 @@ func (gen *generator) irMDNode(old ast.MDNode) (metadata.MDNode, error) {
 
 func (gen *generator) irMDTuple(old *ast.MDTuple) (*metadata.MDTuple, error) {
 	tuple := &metadata.MDTuple{}
-	for _, oldField := range old.MDFields().MDFields() {
+	for _, oldField := range old.MDFields() {
 		field, err := gen.irMDField(oldField)
 		if err != nil {
 			return nil, errors.WithStack(err)
@@ -15,16 +15,23 @@ import (
 
 // indexTopLevelEntities indexes the AST top-level entities of the given module.
 func (gen *generator) indexTopLevelEntities(old *ast.Module) error {
+	// Index AST source filename and target definitions.
+	for _, def := range old.TargetDefs() {
+		switch def := def.(type) {
+		case *ast.SourceFilename:
+			gen.m.SourceFilename = unquote(def.Name().Text())
+		case *ast.TargetDataLayout:
+			gen.m.DataLayout = unquote(def.DataLayout().Text())
+		case *ast.TargetTriple:
+			gen.m.TargetTriple = unquote(def.TargetTriple().Text())
+		default:
+			panic(fmt.Errorf("support for AST target definition %T not yet implemented", def))
+		}
+	}
 	// Index AST top-level entities.
 	// track added type definitions.
 	for _, entity := range old.TopLevelEntities() {
 		switch entity := entity.(type) {
-		case *ast.SourceFilename:
-			gen.m.SourceFilename = unquote(entity.Name().Text())
-		case *ast.TargetDataLayout:
-			gen.m.DataLayout = unquote(entity.DataLayout().Text())
-		case *ast.TargetTriple:
-			gen.m.TargetTriple = unquote(entity.TargetTriple().Text())
 		case *ast.ModuleAsm:
 			asm := unquote(entity.Asm().Text())
 			gen.m.ModuleAsms = append(gen.m.ModuleAsms, asm)
@@ -50,13 +57,6 @@ func (gen *generator) indexTopLevelEntities(old *ast.Module) error {
 			}
 			gen.old.globals[ident] = entity
 			gen.old.globalOrder = append(gen.old.globalOrder, ident)
-		case *ast.GlobalDef:
-			ident := globalIdent(entity.Name())
-			if prev, ok := gen.old.globals[ident]; ok {
-				return errors.Errorf("global identifier %q already present; prev `%s`, new `%s`", ident.Ident(), text(prev), text(entity))
-			}
-			gen.old.globals[ident] = entity
-			gen.old.globalOrder = append(gen.old.globalOrder, ident)
 		case *ast.IndirectSymbolDef:
 			ident := globalIdent(entity.Name())
 			if prev, ok := gen.old.globals[ident]; ok {
@@ -237,7 +237,7 @@ func (gen *generator) translateAttrGroupDefs() error {
 // translateAttrGroupDef translates the given AST attribute group definition to
 // IR.
 func (gen *generator) translateAttrGroupDef(new *ir.AttrGroupDef, old *ast.AttrGroupDef) error {
-	for _, oldFuncAttr := range old.Attrs() {
+	for _, oldFuncAttr := range old.FuncAttrs() {
 		funcAttr := gen.irFuncAttribute(oldFuncAttr)
 		new.FuncAttrs = append(new.FuncAttrs, funcAttr)
 	}
@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/llir/ll/ast"
@@ -55,11 +56,75 @@ func ParseBytes(path string, b []byte) (*ir.Module, error) {
 // for error reporting.
 func ParseString(path, content string) (*ir.Module, error) {
 	parseStart := time.Now()
-	tree, err := ast.Parse(path, content)
+	input, amx := replaceAMX(content)
+	tree, err := ast.Parse(path, input)
 	if err != nil {
 		return nil, errors.Wrapf(err, "unable to parse %q into AST", path)
 	}
 	root := ast.ToLlvmNode(tree.Root())
 	dbg.Println("parsing into AST took:", time.Since(parseStart))
-	return translate(root.(*ast.Module))
+	return translate(root.(*ast.Module), content, amx)
+}
+
+// replaceAMX returns a copy of content in which each x86_amx type keyword is
+// replaced by the x86_mmx keyword, and the offsets of the replaced keywords.
+//
+// The llir/ll grammar has no x86_amx token. As both keywords have the same
+// length, node offsets into the copy are valid offsets into content, and the
+// AST MMX types at the returned offsets are translated to AMX types. String
+// literals, quoted names, comments, identifiers and labels are left intact.
+func replaceAMX(content string) (string, map[int]bool) {
+	const (
+		amxKeyword = "x86_amx"
+		mmxKeyword = "x86_mmx"
+	)
+	if !strings.Contains(content, amxKeyword) {
+		return content, nil
+	}
+	// isIdentChar reports whether the given byte may be part of an identifier,
+	// keyword or sigil-prefixed name.
+	isIdentChar := func(b byte) bool {
+		switch {
+		case 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z', '0' <= b && b <= '9':
+			return true
+		}
+		return strings.IndexByte("-$._%@!#", b) != -1
+	}
+	buf := []byte(content)
+	amx := make(map[int]bool)
+	for i := 0; i < len(buf); i++ {
+		switch buf[i] {
+		case '"':
+			// Skip string literal or quoted name.
+			end := strings.IndexByte(content[i+1:], '"')
+			if end == -1 {
+				i = len(buf)
+				break
+			}
+			i += 1 + end
+		case ';':
+			// Skip comment.
+			end := strings.IndexByte(content[i:], '\n')
+			if end == -1 {
+				i = len(buf)
+				break
+			}
+			i += end
+		default:
+			if !strings.HasPrefix(content[i:], amxKeyword) {
+				continue
+			}
+			end := i + len(amxKeyword)
+			if i > 0 && isIdentChar(buf[i-1]) {
+				continue
+			}
+			if end < len(buf) && (isIdentChar(buf[end]) || buf[end] == ':') {
+				continue
+			}
+			copy(buf[i:end], mmxKeyword)
+			amx[i] = true
+			i = end - 1
+		}
+	}
+	return string(buf), amx
 }
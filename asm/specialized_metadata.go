@@ -722,8 +722,6 @@ func (gen *generator) irDIModule(old *ast.DIModule) (*metadata.DIModule, error)
 			md.ConfigMacros = stringLit(oldField.ConfigMacros())
 		case *ast.IncludePathField:
 			md.IncludePath = stringLit(oldField.IncludePath())
-		case *ast.IsysrootField:
-			md.Isysroot = stringLit(oldField.Isysroot())
 		default:
 			panic(fmt.Errorf("support for DIModule field %T not yet implemented", old))
 		}
@@ -891,7 +889,12 @@ func (gen *generator) irDISubrange(old *ast.DISubrange) (*metadata.DISubrange, e
 			}
 			md.Count = count
 		case *ast.LowerBoundField:
-			md.LowerBound = intLit(oldField.LowerBound())
+			switch lowerBound := oldField.LowerBound().(type) {
+			case *ast.IntLit:
+				md.LowerBound = intLit(*lowerBound)
+			default:
+				panic(fmt.Errorf("support for DISubrange lower bound %T not yet implemented", lowerBound))
+			}
 		default:
 			panic(fmt.Errorf("support for DISubrange field %T not yet implemented", old))
 		}
@@ -983,7 +986,7 @@ func (gen *generator) irGenericDINode(old *ast.GenericDINode) (*metadata.Generic
 		case *ast.HeaderField:
 			md.Header = stringLit(oldField.Header())
 		case *ast.OperandsField:
-			for _, field := range oldField.Operands().MDFields() {
+			for _, field := range oldField.Operands() {
 				operand, err := gen.irMDField(field)
 				if err != nil {
 					return nil, errors.WithStack(err)
@@ -1036,7 +1039,7 @@ func irDIFlag(old ast.DIFlag) enum.DIFlag {
 
 // irDwarfAttEncoding returns the IR Dwarf attribute encoding corresponding to
 // the given AST Dwarf attribute encoding.
-func irDwarfAttEncoding(old ast.DwarfAttEncoding) enum.DwarfAttEncoding {
+func irDwarfAttEncoding(old ast.DwarfAttEncodingOrUint) enum.DwarfAttEncoding {
 	switch old := old.(type) {
 	case *ast.DwarfAttEncodingEnum:
 		return asmenum.DwarfAttEncodingFromString(old.Text())
@@ -140,7 +140,11 @@ func (fgen *funcGen) astToIRTermRet(term ir.Terminator, old *ast.RetTerm) error
 	// Check if not void return.
 	if !typ.Equal(types.Void) {
 		// Return value.
-		x, err := fgen.astToIRValue(typ, old.X())
+		oldX, ok := old.X()
+		if !ok {
+			return errors.Errorf("missing return value of type %v", typ)
+		}
+		x, err := fgen.astToIRValue(typ, oldX)
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -300,7 +304,7 @@ func (fgen *funcGen) astToIRTermInvoke(term ir.Terminator, old *ast.InvokeTerm)
 		panic(fmt.Errorf("invalid IR terminator for AST terminator; expected *ir.TermInvoke, got %T", term))
 	}
 	// (optional) Calling convention.
-	if n := old.CallingConv(); n.LlvmNode().IsValid() {
+	if n, ok := old.CallingConv(); ok {
 		t.CallingConv = irCallingConv(n)
 	}
 	// (optional) Return attributes.
@@ -351,13 +355,13 @@ func (fgen *funcGen) astToIRTermInvoke(term ir.Terminator, old *ast.InvokeTerm)
 		t.OperandBundles = append(t.OperandBundles, operandBundle)
 	}
 	// Normal control flow return point.
-	normal, err := fgen.irBasicBlock(old.Normal())
+	normal, err := fgen.irBasicBlock(old.NormalRetTarget())
 	if err != nil {
 		return errors.WithStack(err)
 	}
 	t.Normal = normal
 	// Exception control flow return point.
-	exception, err := fgen.irBasicBlock(old.Exception())
+	exception, err := fgen.irBasicBlock(old.ExceptionRetTarget())
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -405,7 +409,7 @@ func (fgen *funcGen) astToIRTermCatchSwitch(term ir.Terminator, old *ast.CatchSw
 		panic(fmt.Errorf("invalid IR terminator for AST terminator; expected *ir.TermCatchSwitch, got %T", term))
 	}
 	// Exception scope.
-	scope, err := fgen.irExceptionScope(old.Scope())
+	scope, err := fgen.irExceptionScope(old.ParentPad())
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -419,7 +423,7 @@ func (fgen *funcGen) astToIRTermCatchSwitch(term ir.Terminator, old *ast.CatchSw
 		t.Handlers = append(t.Handlers, handler)
 	}
 	// Unwind target.
-	unwindTarget, err := fgen.irUnwindTarget(old.UnwindTarget())
+	unwindTarget, err := fgen.irUnwindTarget(old.DefaultUnwindTarget())
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -443,7 +447,7 @@ func (fgen *funcGen) astToIRTermCatchRet(term ir.Terminator, old *ast.CatchRetTe
 		panic(fmt.Errorf("invalid IR terminator for AST terminator; expected *ir.TermCatchRet, got %T", term))
 	}
 	// Exit catchpad.
-	v, err := fgen.astToIRValue(types.Token, old.From())
+	v, err := fgen.astToIRValue(types.Token, old.CatchPad())
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -453,7 +457,7 @@ func (fgen *funcGen) astToIRTermCatchRet(term ir.Terminator, old *ast.CatchRetTe
 	}
 	t.From = catchpad
 	// Target basic block to transfer control flow to.
-	to, err := fgen.irBasicBlock(old.To())
+	to, err := fgen.irBasicBlock(old.Target())
 	if err != nil {
 		return errors.WithStack(err)
 	}
@@ -477,7 +481,7 @@ func (fgen *funcGen) astToIRTermCleanupRet(term ir.Terminator, old *ast.CleanupR
 		panic(fmt.Errorf("invalid IR terminator for AST terminator; expected *ir.TermCleanupRet, got %T", term))
 	}
 	// Exit cleanuppad.
-	v, err := fgen.astToIRValue(types.Token, old.From())
+	v, err := fgen.astToIRValue(types.Token, old.CleanupPad())
 	if err != nil {
 		return errors.WithStack(err)
 	}
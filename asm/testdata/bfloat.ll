@@ -0,0 +1,15 @@
+@a = global bfloat 0xR3F80
+@b = global bfloat 0xR4049
+@c = global bfloat 2.0
+@d = global bfloat -0.0
+@e = global bfloat 0xR7F80
+@f = global bfloat 0xR7FC0
+@g = global <2 x bfloat> <bfloat 0xR3F80, bfloat 0xRBF80>
+
+define bfloat @h(bfloat %x, float %y) {
+	%1 = fadd bfloat %x, 0xR3F80
+	%2 = fptrunc float %y to bfloat
+	%3 = fmul bfloat %1, %2
+	%4 = fpext bfloat %3 to double
+	ret bfloat %3
+}
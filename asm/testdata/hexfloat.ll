@@ -1,2 +1,37 @@
 @a = global half 0xH4400
 @b = global half 0xH2E66
+@c = global half 0xH7C00
+@d = global half 0xHFC00
+@e = global half 0xH7E00
+@f = global half 0xHFD01
+@g = global half 0xH0001
+@h = global half -0.0
+@i = global half 0x3FB99999A0000000
+@j = global float 0.1
+@k = global float 0x3FB99999A0000000
+@l = global float 0x7FF8000000000000
+@m = global float 0xFFF4000000000000
+@n = global float 0x7FF0000000000000
+@o = global float 0x36A0000000000000
+@p = global float -2.5
+@q = global double 0.1
+@r = global double 0x7FF8000000000000
+@s = global double 0xFFF0000000000001
+@t = global double 0xFFF0000000000000
+@u = global double 0x1
+@v = global double 1.0e+300
+@w = global x86_fp80 0xK4000C000000000000000
+@x = global x86_fp80 0xK7FFFC000000000000000
+@y = global x86_fp80 0xKFFFF8000000000000000
+@z = global x86_fp80 0xK7FFFC000000000000123
+@aa = global x86_fp80 0xK00000000000000000001
+@ab = global x86_fp80 1.5
+@ac = global fp128 0xL00000000000000004000800000000000
+@ad = global fp128 0xL00000000000000007FFF800000000000
+@ae = global fp128 0xL0000000000000001FFFF000000000000
+@af = global fp128 0xL00000000000000010000000000000000
+@ag = global fp128 0.1
+@ah = global ppc_fp128 0xM3FF00000000000000000000000000000
+@ai = global ppc_fp128 0xM3FF0000000000000BC90000000000000
+@aj = global ppc_fp128 0xM7FF80000000000000000000000000000
+@ak = global ppc_fp128 0xM80000000000000000000000000000000
@@ -0,0 +1,11 @@
+@a = global i32 poison
+@b = global <2 x i8> <i8 1, i8 poison>
+@c = global { i32, float } { i32 poison, float 1.0 }
+
+define i32 @f(i32 %x) {
+	%1 = add i32 %x, poison
+	%2 = select i1 poison, i32 %1, i32 poison
+	%3 = insertelement <4 x i32> poison, i32 %2, i64 0
+	%4 = shufflevector <4 x i32> %3, <4 x i32> poison, <4 x i32> zeroinitializer
+	ret i32 poison
+}
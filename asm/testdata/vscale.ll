@@ -0,0 +1,19 @@
+%nxv4i32 = type <vscale x 4 x i32>
+
+@a = global <vscale x 4 x i32> zeroinitializer
+@b = global <vscale x 2 x double> undef
+@c = global %nxv4i32 poison
+
+define <vscale x 4 x i32> @f(<vscale x 4 x i32> %x, <vscale x 4 x i32>* %p) {
+	%1 = add <vscale x 4 x i32> %x, %x
+	%2 = icmp slt <vscale x 4 x i32> %1, zeroinitializer
+	%3 = select <vscale x 4 x i1> %2, <vscale x 4 x i32> %1, <vscale x 4 x i32> %x
+	%4 = insertelement <vscale x 4 x i32> undef, i32 1, i32 0
+	%5 = shufflevector <vscale x 4 x i32> %4, <vscale x 4 x i32> undef, <vscale x 4 x i32> zeroinitializer
+	%6 = getelementptr <vscale x 4 x i32>, <vscale x 4 x i32>* %p, i64 1
+	%7 = load <vscale x 4 x i32>, <vscale x 4 x i32>* %6
+	%8 = sitofp <vscale x 4 x i32> %7 to <vscale x 4 x float>
+	%9 = fcmp olt <vscale x 4 x float> %8, zeroinitializer
+	%10 = extractelement <vscale x 4 x i32> %5, i64 0
+	ret <vscale x 4 x i32> %3
+}
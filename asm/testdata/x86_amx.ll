@@ -0,0 +1,19 @@
+%x86_amx_tile = type x86_amx
+%struct.x86_amx = type { x86_mmx, i8* }
+
+@"x86_amx" = global [8 x i8] c"x86_amx\00" ; x86_amx comment
+
+declare x86_amx @llvm.x86.tileloadd64.internal(i16, i16, i8*, i64)
+
+declare void @llvm.x86.tilestored64.internal(i16, i16, i8*, i64, x86_amx)
+
+define void @f(i8* %p, <256 x i32>* %q, %x86_amx_tile %t) {
+	%x86_amx = call x86_amx @llvm.x86.tileloadd64.internal(i16 8, i16 8, i8* %p, i64 64)
+	call void @llvm.x86.tilestored64.internal(i16 8, i16 8, i8* %p, i64 64, x86_amx %x86_amx)
+	%1 = bitcast <256 x i32>* %q to x86_amx*
+	%2 = load x86_amx, x86_amx* %1
+	%3 = bitcast x86_amx %2 to <256 x i32>
+	br label %x86_amx.exit
+x86_amx.exit:
+	ret void
+}
@@ -56,6 +56,8 @@
 //
 // 8. Add IR top-level declarations and definitions to the module in order of
 //    occurrence in the input.
+//
+// 9. Associate comments of the input with IR entities.
 
 package asm
 
@@ -70,9 +72,12 @@ import (
 	"github.com/pkg/errors"
 )
 
-// translate translates the given AST module into an equivalent IR module.
-func translate(old *ast.Module) (*ir.Module, error) {
+// translate translates the given AST module into an equivalent IR module. The
+// comments of the input content are associated with the IR entities. amx holds
+// the offsets of AST MMX types lexed from x86_amx keywords (see replaceAMX).
+func translate(old *ast.Module, content string, amx map[int]bool) (*ir.Module, error) {
 	gen := newGenerator()
+	gen.amx = amx
 	// 1. Index AST top-level entities.
 	indexStart := time.Now()
 	if err := gen.indexTopLevelEntities(old); err != nil {
@@ -121,14 +126,14 @@ func translate(old *ast.Module) (*ir.Module, error) {
 		gen.m.UseListOrderBBs = append(gen.m.UseListOrderBBs, useListOrderBB)
 	}
 	// 7. Fix basic block references in blockaddress constants.
-	for _, c := range gen.todo {
-		if err := fixBlockAddressConst(c); err != nil {
-			return nil, errors.WithStack(err)
-		}
+	if err := gen.fixBlockAddresses(); err != nil {
+		return nil, errors.WithStack(err)
 	}
 	// 8. Add IR top-level declarations and definitions to the module in order of
 	//    occurrence in the input.
 	gen.addDefsToModule()
+	// 9. Associate comments of the input with IR entities.
+	gen.translateComments(old, content)
 	return gen.m, nil
 }
 
@@ -22,7 +22,7 @@ func (gen *generator) resolveTypeDefs() error {
 	for typeName, old := range gen.old.typeDefs {
 		// track is used to identify self-referential named types.
 		track := make(map[string]bool)
-		t, err := newIRType(typeName, old.Typ(), gen.old.typeDefs, track)
+		t, err := gen.newIRType(typeName, old.Typ(), gen.old.typeDefs, track)
 		if err != nil {
 			return errors.WithStack(err)
 		}
@@ -57,7 +57,7 @@ func (gen *generator) resolveTypeDefs() error {
 //
 //    ; struct type containing pointer to itself.
 //    %d = type { %d* }
-func newIRType(typeName string, old ast.LlvmNode, index map[string]*ast.TypeDef, track map[string]bool) (types.Type, error) {
+func (gen *generator) newIRType(typeName string, old ast.LlvmNode, index map[string]*ast.TypeDef, track map[string]bool) (types.Type, error) {
 	switch old := old.(type) {
 	case *ast.OpaqueType:
 		return &types.StructType{TypeName: typeName}, nil
@@ -72,6 +72,9 @@ func newIRType(typeName string, old ast.LlvmNode, index map[string]*ast.TypeDef,
 	case *ast.LabelType:
 		return &types.LabelType{TypeName: typeName}, nil
 	case *ast.MMXType:
+		if gen.isAMX(old) {
+			return &types.AMXType{TypeName: typeName}, nil
+		}
 		return &types.MMXType{TypeName: typeName}, nil
 	case *ast.MetadataType:
 		return &types.MetadataType{TypeName: typeName}, nil
@@ -88,9 +91,11 @@ func newIRType(typeName string, old ast.LlvmNode, index map[string]*ast.TypeDef,
 		newIdent := localIdent(old.Name())
 		newName := getTypeName(newIdent)
 		newTyp := index[newName].Typ()
-		return newIRType(newName, newTyp, index, track)
+		return gen.newIRType(newName, newTyp, index, track)
 	case *ast.PointerType:
 		return &types.PointerType{TypeName: typeName}, nil
+	case *ast.ScalableVectorType:
+		return &types.VectorType{TypeName: typeName, Scalable: true}, nil
 	case *ast.StructType:
 		return &types.StructType{TypeName: typeName}, nil
 	case *ast.PackedStructType:
@@ -126,6 +131,9 @@ func (gen *generator) astToIRTypeDef(t types.Type, old ast.LlvmNode) (types.Type
 	case *ast.LabelType:
 		return gen.astToIRLabelType(t, old)
 	case *ast.MMXType:
+		if gen.isAMX(old) {
+			return gen.astToIRAMXType(t, old)
+		}
 		return gen.astToIRMMXType(t, old)
 	case *ast.MetadataType:
 		return gen.astToIRMetadataType(t, old)
@@ -133,6 +141,8 @@ func (gen *generator) astToIRTypeDef(t types.Type, old ast.LlvmNode) (types.Type
 		return gen.astToIRNamedType(t, old)
 	case *ast.PointerType:
 		return gen.astToIRPointerType(t, old)
+	case *ast.ScalableVectorType:
+		return gen.astToIRScalableVectorType(t, old)
 	case *ast.StructType:
 		return gen.astToIRStructType(t, old)
 	case *ast.PackedStructType:
@@ -252,6 +262,8 @@ func irFloatKind(kind ast.FloatKind) types.FloatKind {
 	switch text {
 	case "half":
 		return types.FloatKindHalf
+	case "bfloat":
+		return types.FloatKindBFloat
 	case "float":
 		return types.FloatKindFloat
 	case "double":
@@ -282,6 +294,29 @@ func (gen *generator) astToIRMMXType(t types.Type, old *ast.MMXType) (types.Type
 	return typ, nil
 }
 
+// --- [ AMX Types ] -----------------------------------------------------------
+
+// astToIRAMXType translates the given AST MMX type, which was lexed from an
+// x86_amx keyword (see replaceAMX), into an equivalent IR AMX type.
+func (gen *generator) astToIRAMXType(t types.Type, old *ast.MMXType) (types.Type, error) {
+	typ, ok := t.(*types.AMXType)
+	if t == nil {
+		typ = &types.AMXType{}
+	} else if !ok {
+		// NOTE: Panic instead of returning error as this case should not be
+		// possible, and would indicate a bug in the implementation.
+		panic(fmt.Errorf("invalid IR type for AST AMX type; expected *types.AMXType, got %T", t))
+	}
+	// nothing to do.
+	return typ, nil
+}
+
+// isAMX reports whether the given AST MMX type was lexed from an x86_amx
+// keyword (see replaceAMX).
+func (gen *generator) isAMX(old *ast.MMXType) bool {
+	return gen.amx[old.LlvmNode().Offset()]
+}
+
 // --- [ Pointer Types ] -------------------------------------------------------
 
 func (gen *generator) astToIRPointerType(t types.Type, old *ast.PointerType) (types.Type, error) {
@@ -328,6 +363,27 @@ func (gen *generator) astToIRVectorType(t types.Type, old *ast.VectorType) (type
 	return typ, nil
 }
 
+func (gen *generator) astToIRScalableVectorType(t types.Type, old *ast.ScalableVectorType) (types.Type, error) {
+	typ, ok := t.(*types.VectorType)
+	if t == nil {
+		typ = &types.VectorType{}
+	} else if !ok {
+		// NOTE: Panic instead of returning error as this case should not be
+		// possible, and would indicate a bug in the implementation.
+		panic(fmt.Errorf("invalid IR type for AST scalable vector type; expected *types.VectorType, got %T", t))
+	}
+	typ.Scalable = true
+	// Minimum vector length.
+	typ.Len = uintLit(old.Len())
+	// Element type.
+	elem, err := gen.irType(old.Elem())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	typ.ElemType = elem
+	return typ, nil
+}
+
 // --- [ Label Types ] ---------------------------------------------------------
 
 func (gen *generator) astToIRLabelType(t types.Type, old *ast.LabelType) (types.Type, error) {
@@ -5,9 +5,16 @@
 // between the input and the llir/llvm string representation of the same LLVM IR
 // module.
 //
+// In semantic mode, lldiff instead compares two LLVM IR modules, and reports
+// the type definitions, global variables, functions, aliases and IFuncs which
+// were added, removed or changed. Functions are compared basic block by basic
+// block, aligning corresponding instructions, such that differences in the
+// names and IDs of local variables are not reported.
+//
 // Usage:
 //
 //    lldiff FILE.ll...
+//    lldiff -semantic OLD.ll NEW.ll
 package main
 
 import (
@@ -29,6 +36,7 @@ Display the difference between LLVM IR input and llir/llvm output.
 Usage:
 
 	lldiff [OPTION]... FILE.ll...
+	lldiff -semantic OLD.ll NEW.ll
 
 Flags:
 `
@@ -37,8 +45,24 @@ Flags:
 }
 
 func main() {
+	var semantic bool
+	flag.BoolVar(&semantic, "semantic", false, "compare the semantics of two LLVM IR modules")
 	flag.Usage = usage
 	flag.Parse()
+	if semantic {
+		if flag.NArg() != 2 {
+			flag.Usage()
+			os.Exit(2)
+		}
+		changed, err := semanticDiff(os.Stdout, flag.Arg(0), flag.Arg(1))
+		if err != nil {
+			log.Fatalf("%+v", err)
+		}
+		if changed {
+			os.Exit(1)
+		}
+		return
+	}
 	dmp := diffmatchpatch.New()
 	for _, path := range flag.Args() {
 		buf, err := ioutil.ReadFile(path)
@@ -0,0 +1,473 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/internal/enc"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/value"
+	"github.com/mewkiz/pkg/term"
+	"github.com/pkg/errors"
+)
+
+// === [ Semantic difference ] =================================================
+
+// semanticDiff writes the semantic difference between the LLVM IR modules of
+// the given files to w, and reports whether the modules differ.
+func semanticDiff(w io.Writer, oldPath, newPath string) (bool, error) {
+	a, err := asm.ParseFile(oldPath)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	b, err := asm.ParseFile(newPath)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	d := &differ{}
+	d.entities("type", typeDefs(a), typeDefs(b))
+	d.entities("global", globals(a), globals(b))
+	d.funcs(a.Funcs, b.Funcs)
+	d.entities("alias", aliases(a), aliases(b))
+	d.entities("ifunc", ifuncs(a), ifuncs(b))
+	if d.buf.Len() == 0 {
+		return false, nil
+	}
+	fmt.Fprintln(w, term.Red(fmt.Sprintf("--- %s", oldPath)))
+	fmt.Fprintln(w, term.Green(fmt.Sprintf("+++ %s", newPath)))
+	if _, err := io.WriteString(w, d.buf.String()); err != nil {
+		return false, errors.WithStack(err)
+	}
+	return true, nil
+}
+
+// differ records the semantic difference between two LLVM IR modules.
+type differ struct {
+	// Output buffer.
+	buf strings.Builder
+}
+
+// hunk writes the header of a difference hunk of the given entity.
+func (d *differ) hunk(kind, name string) {
+	fmt.Fprintf(&d.buf, "@@ %s %s @@\n", kind, name)
+}
+
+// line writes the given line of LLVM IR assembly with the given difference
+// prefix (' ', '-' or '+'). Multi-line strings are split into lines.
+func (d *differ) line(op byte, s string) {
+	for _, line := range strings.Split(s, "\n") {
+		line = string(op) + line
+		switch op {
+		case '-':
+			line = term.Red(line)
+		case '+':
+			line = term.Green(line)
+		}
+		d.buf.WriteString(line)
+		d.buf.WriteString("\n")
+	}
+}
+
+// --- [ Top-level entities ] --------------------------------------------------
+
+// entity is a top-level entity compared by its definition.
+type entity struct {
+	// Identifier of the entity.
+	name string
+	// LLVM IR assembly definition of the entity.
+	def string
+}
+
+// entities records the added, removed and changed entities of the given kind.
+func (d *differ) entities(kind string, old, new []entity) {
+	defs := make(map[string]string)
+	for _, e := range new {
+		defs[e.name] = e.def
+	}
+	seen := make(map[string]bool)
+	for _, e := range old {
+		seen[e.name] = true
+		def, ok := defs[e.name]
+		switch {
+		case !ok:
+			d.hunk(kind, e.name)
+			d.line('-', e.def)
+		case def != e.def:
+			d.hunk(kind, e.name)
+			d.line('-', e.def)
+			d.line('+', def)
+		}
+	}
+	for _, e := range new {
+		if !seen[e.name] {
+			d.hunk(kind, e.name)
+			d.line('+', e.def)
+		}
+	}
+}
+
+// typeDefs returns the type definitions of the given module.
+func typeDefs(m *ir.Module) []entity {
+	var es []entity
+	for _, t := range m.TypeDefs {
+		name := t.String()
+		es = append(es, entity{name: name, def: fmt.Sprintf("%s = type %s", name, t.Def())})
+	}
+	return es
+}
+
+// globals returns the global variables of the given module.
+func globals(m *ir.Module) []entity {
+	var es []entity
+	for _, g := range m.Globals {
+		es = append(es, entity{name: g.Ident(), def: g.Def()})
+	}
+	return es
+}
+
+// aliases returns the aliases of the given module.
+func aliases(m *ir.Module) []entity {
+	var es []entity
+	for _, alias := range m.Aliases {
+		es = append(es, entity{name: alias.Ident(), def: alias.Def()})
+	}
+	return es
+}
+
+// ifuncs returns the IFuncs of the given module.
+func ifuncs(m *ir.Module) []entity {
+	var es []entity
+	for _, ifunc := range m.IFuncs {
+		es = append(es, entity{name: ifunc.Ident(), def: ifunc.Def()})
+	}
+	return es
+}
+
+// --- [ Functions ] -----------------------------------------------------------
+
+// funcs records the added, removed and changed functions.
+func (d *differ) funcs(old, new []*ir.Function) {
+	funcs := make(map[string]*ir.Function)
+	for _, f := range new {
+		funcs[f.Ident()] = f
+	}
+	seen := make(map[string]bool)
+	for _, f := range old {
+		seen[f.Ident()] = true
+		g, ok := funcs[f.Ident()]
+		if !ok {
+			d.hunk("func", f.Ident())
+			d.line('-', f.Def())
+			continue
+		}
+		d.funcDiff(f, g)
+	}
+	for _, g := range new {
+		if !seen[g.Ident()] {
+			d.hunk("func", g.Ident())
+			d.line('+', g.Def())
+		}
+	}
+}
+
+// funcDiffer records the correspondence between the local variables of two
+// functions.
+type funcDiffer struct {
+	// Function pair.
+	funcs [2]*ir.Function
+	// Pair ID of each corresponding local variable of the old and new function
+	// respectively.
+	ids [2]map[value.Value]int
+	// Basic block pairs not yet aligned.
+	queue [][2]*ir.BasicBlock
+	// New basic block paired with each old basic block.
+	blocks map[*ir.BasicBlock]*ir.BasicBlock
+	// Instruction alignment of each pair of basic blocks, indexed by old basic
+	// block.
+	aligns map[*ir.BasicBlock][]pair
+}
+
+// pair is a pair of aligned instructions or terminators; either of which is
+// nil if added or removed.
+type pair [2]interface{}
+
+// funcDiff records the difference between the given functions with equal
+// names.
+//
+// Basic blocks are paired starting from the entry basic blocks and following
+// the successors of aligned terminators. Remaining basic blocks are paired by
+// name, and then in order of occurrence. The instructions of paired basic
+// blocks are aligned by longest common subsequence, comparing local operands by
+// correspondence. The results of aligned instructions correspond.
+func (d *differ) funcDiff(f, g *ir.Function) {
+	fd := &funcDiffer{
+		funcs:  [2]*ir.Function{f, g},
+		ids:    [2]map[value.Value]int{make(map[value.Value]int), make(map[value.Value]int)},
+		blocks: make(map[*ir.BasicBlock]*ir.BasicBlock),
+		aligns: make(map[*ir.BasicBlock][]pair),
+	}
+	for i := 0; i < len(f.Params) && i < len(g.Params); i++ {
+		fd.pair(f.Params[i], g.Params[i])
+	}
+	if len(f.Blocks) > 0 && len(g.Blocks) > 0 {
+		fd.pairBlocks(f.Blocks[0], g.Blocks[0])
+	}
+	for {
+		for len(fd.queue) > 0 {
+			p := fd.queue[0]
+			fd.queue = fd.queue[1:]
+			fd.align(p[0], p[1])
+		}
+		// Pair remaining basic blocks by name.
+		names := make(map[string]*ir.BasicBlock)
+		for _, block := range g.Blocks {
+			if _, ok := fd.ids[1][block]; !ok && !block.IsUnnamed() {
+				names[block.LocalName] = block
+			}
+		}
+		for _, block := range f.Blocks {
+			if _, ok := fd.ids[0][block]; !ok && !block.IsUnnamed() {
+				if other, ok := names[block.LocalName]; ok {
+					fd.pairBlocks(block, other)
+				}
+			}
+		}
+		if len(fd.queue) == 0 {
+			// Pair the first remaining basic blocks in order of occurrence.
+			a, b := unpaired(f, fd.ids[0]), unpaired(g, fd.ids[1])
+			if a == nil || b == nil {
+				break
+			}
+			fd.pairBlocks(a, b)
+		}
+	}
+	// Record differences.
+	out := &differ{}
+	oldHeader, newHeader := header(f), header(g)
+	if fd.canonicalHeader(0) != fd.canonicalHeader(1) {
+		out.line('-', oldHeader)
+		out.line('+', newHeader)
+	}
+	for _, block := range f.Blocks {
+		align, ok := fd.aligns[block]
+		if !ok {
+			out.line('-', block.Def())
+			continue
+		}
+		blockOut := &differ{}
+		changed := false
+		// Unchanged lines are printed as in the new function.
+		blockOut.line(' ', label(fd.blocks[block]))
+		for _, p := range align {
+			switch {
+			case p[1] == nil:
+				blockOut.line('-', "\t"+def(p[0]))
+				changed = true
+			case p[0] == nil:
+				blockOut.line('+', "\t"+def(p[1]))
+				changed = true
+			case fd.key(0, p[0]) != fd.key(1, p[1]):
+				blockOut.line('-', "\t"+def(p[0]))
+				blockOut.line('+', "\t"+def(p[1]))
+				changed = true
+			default:
+				blockOut.line(' ', "\t"+def(p[1]))
+			}
+		}
+		if changed {
+			out.buf.WriteString(blockOut.buf.String())
+		}
+	}
+	for _, block := range g.Blocks {
+		if _, ok := fd.ids[1][block]; !ok {
+			out.line('+', block.Def())
+		}
+	}
+	if out.buf.Len() == 0 {
+		return
+	}
+	d.hunk("func", f.Ident())
+	if fd.canonicalHeader(0) == fd.canonicalHeader(1) {
+		d.line(' ', newHeader)
+	}
+	d.buf.WriteString(out.buf.String())
+}
+
+// pair records the correspondence of the given local variables of the old and
+// new function respectively, unless either is already paired.
+func (fd *funcDiffer) pair(a, b value.Value) bool {
+	if _, ok := fd.ids[0][a]; ok {
+		return false
+	}
+	if _, ok := fd.ids[1][b]; ok {
+		return false
+	}
+	id := len(fd.ids[0])
+	fd.ids[0][a] = id
+	fd.ids[1][b] = id
+	return true
+}
+
+// pairBlocks records the correspondence of the given basic blocks and queues
+// them for alignment, unless either is already paired.
+func (fd *funcDiffer) pairBlocks(a, b *ir.BasicBlock) {
+	if fd.pair(a, b) {
+		fd.blocks[a] = b
+		fd.queue = append(fd.queue, [2]*ir.BasicBlock{a, b})
+	}
+}
+
+// align aligns the instructions and terminators of the given basic blocks, and
+// pairs the results of aligned instructions and the successors of aligned
+// terminators.
+func (fd *funcDiffer) align(a, b *ir.BasicBlock) {
+	xs, ys := insts(a), insts(b)
+	xkeys := make([]string, len(xs))
+	for i, x := range xs {
+		xkeys[i] = fd.key(0, x)
+	}
+	ykeys := make([]string, len(ys))
+	for j, y := range ys {
+		ykeys[j] = fd.key(1, y)
+	}
+	// lcs[i][j] is the length of the longest common subsequence of xs[i:] and
+	// ys[j:].
+	lcs := make([][]int, len(xs)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(ys)+1)
+	}
+	for i := len(xs) - 1; i >= 0; i-- {
+		for j := len(ys) - 1; j >= 0; j-- {
+			switch {
+			case xkeys[i] == ykeys[j]:
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			case lcs[i+1][j] >= lcs[i][j+1]:
+				lcs[i][j] = lcs[i+1][j]
+			default:
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+	var align []pair
+	i, j := 0, 0
+	for i < len(xs) || j < len(ys) {
+		switch {
+		case i < len(xs) && j < len(ys) && xkeys[i] == ykeys[j]:
+			align = append(align, pair{xs[i], ys[j]})
+			if x, ok := xs[i].(value.Value); ok {
+				fd.pair(x, ys[j].(value.Value))
+			}
+			if x, ok := xs[i].(ir.Terminator); ok {
+				xsuccs, ysuccs := x.Succs(), ys[j].(ir.Terminator).Succs()
+				for k := 0; k < len(xsuccs) && k < len(ysuccs); k++ {
+					fd.pairBlocks(xsuccs[k], ysuccs[k])
+				}
+			}
+			i++
+			j++
+		case j == len(ys) || (i < len(xs) && lcs[i+1][j] >= lcs[i][j+1]):
+			align = append(align, pair{xs[i], nil})
+			i++
+		default:
+			align = append(align, pair{nil, ys[j]})
+			j++
+		}
+	}
+	fd.aligns[a] = align
+}
+
+// key returns the canonical key of the given instruction or terminator of the
+// old (side 0) or new (side 1) function. Paired local operands are identified
+// by pair ID, and unpaired local operands are considered equal.
+func (fd *funcDiffer) key(side int, inst interface{}) string {
+	return irutil.InstKey(inst, func(v value.Value) (string, bool) {
+		switch v.(type) {
+		case *ir.Param, *ir.BasicBlock, ir.Instruction, ir.Terminator:
+			if id, ok := fd.ids[side][v]; ok {
+				return fmt.Sprintf("%%%d", id), true
+			}
+			return "?", true
+		}
+		return "", false
+	})
+}
+
+// canonicalHeader returns the header of the old (side 0) or new (side 1)
+// function, with parameters identified by pair ID.
+func (fd *funcDiffer) canonicalHeader(side int) string {
+	f := fd.funcs[side]
+	// Print the function as a declaration, as the local IDs of function
+	// definitions are validated.
+	blocks := f.Blocks
+	f.Blocks = nil
+	saved := make([]ir.LocalIdent, len(f.Params))
+	for i, param := range f.Params {
+		saved[i] = param.LocalIdent
+		param.LocalName = ""
+		param.LocalID = int64(fd.ids[side][param])
+	}
+	s := f.Def()
+	for i, param := range f.Params {
+		param.LocalIdent = saved[i]
+	}
+	f.Blocks = blocks
+	return s
+}
+
+// ### [ Helper functions ] ####################################################
+
+// insts returns the instructions and terminator of the given basic block.
+func insts(block *ir.BasicBlock) []interface{} {
+	var xs []interface{}
+	for _, inst := range block.Insts {
+		xs = append(xs, inst)
+	}
+	if block.Term != nil {
+		xs = append(xs, block.Term)
+	}
+	return xs
+}
+
+// def returns the LLVM IR assembly definition of the given instruction or
+// terminator.
+func def(inst interface{}) string {
+	switch inst := inst.(type) {
+	case ir.Instruction:
+		return inst.Def()
+	case ir.Terminator:
+		return inst.Def()
+	default:
+		panic(fmt.Errorf("support for %T not yet implemented", inst))
+	}
+}
+
+// unpaired returns the first basic block of the given function not present in
+// ids, or nil if all basic blocks are paired.
+func unpaired(f *ir.Function, ids map[value.Value]int) *ir.BasicBlock {
+	for _, block := range f.Blocks {
+		if _, ok := ids[block]; !ok {
+			return block
+		}
+	}
+	return nil
+}
+
+// label returns the label line of the given basic block.
+func label(block *ir.BasicBlock) string {
+	if block.IsUnnamed() {
+		return fmt.Sprintf("; <label>:%d", block.LocalID)
+	}
+	return enc.Label(block.LocalName)
+}
+
+// header returns the function header of the given function, omitting its body.
+func header(f *ir.Function) string {
+	s := f.Def()
+	if pos := strings.Index(s, " {\n"); pos != -1 {
+		return s[:pos] + " {"
+	}
+	return s
+}
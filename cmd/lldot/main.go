@@ -0,0 +1,167 @@
+// The lldot tool exports the control flow graphs and call graph of LLVM IR
+// assembly files in Graphviz DOT format.
+//
+// lldot writes the control flow graph of each function definition of the input
+// to a file named after the function (e.g. "main.dot" for @main, and "0.dot"
+// for the unnamed function @0) in the output directory. Optionally, the call
+// graph of the module is written to "callgraph.dot". Given several input files,
+// the graphs of each input file are written to a subdirectory of the output
+// directory named after the base name of the input file (e.g. "foo" for
+// foo.ll). lldot fails if two graphs would be written to the same output file.
+//
+// Usage:
+//
+//    lldot [OPTION]... FILE.ll...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/analysis"
+	"github.com/pkg/errors"
+)
+
+func usage() {
+	const use = `
+Export control flow graphs and call graphs of LLVM IR assembly to DOT format.
+
+Usage:
+
+	lldot [OPTION]... FILE.ll...
+
+Flags:
+`
+	fmt.Fprintln(os.Stderr, use[1:])
+	flag.PrintDefaults()
+}
+
+// exporter exports the control flow graphs and call graphs of LLVM IR
+// assembly files.
+type exporter struct {
+	// Output directory.
+	outDir string
+	// List instructions in the nodes of control flow graphs.
+	insts bool
+	// Export the call graph of the module.
+	callGraph bool
+	// Comma-separated list of functions to export; or empty to export all
+	// function definitions.
+	funcs string
+	// Write the graphs of each input file to a subdirectory of the output
+	// directory.
+	perFile bool
+	// Paths of the DOT files written.
+	written map[string]bool
+}
+
+func main() {
+	e := exporter{written: make(map[string]bool)}
+	flag.StringVar(&e.outDir, "o", ".", "output directory")
+	flag.BoolVar(&e.insts, "insts", true, "list instructions in control flow graph nodes")
+	flag.BoolVar(&e.callGraph, "callgraph", false, "export call graph to callgraph.dot")
+	flag.StringVar(&e.funcs, "funcs", "", "comma-separated list of functions to export (default all)")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	e.perFile = flag.NArg() > 1
+	for _, path := range flag.Args() {
+		if err := e.exportFile(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// exportFile exports the control flow graphs and call graph of the given LLVM
+// IR assembly file.
+func (e *exporter) exportFile(path string) error {
+	m, err := asm.ParseFile(path)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse %q", path)
+	}
+	dir := e.outDir
+	if e.perFile {
+		dir = filepath.Join(dir, strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return errors.WithStack(err)
+	}
+	selected := make(map[string]bool)
+	if len(e.funcs) > 0 {
+		for _, name := range strings.Split(e.funcs, ",") {
+			selected[strings.TrimPrefix(strings.TrimSpace(name), "@")] = true
+		}
+	}
+	for _, f := range m.Funcs {
+		if len(f.Blocks) == 0 {
+			continue
+		}
+		if len(selected) > 0 && !selected[funcName(f)] {
+			continue
+		}
+		dot, err := analysis.CFGDot(f, e.insts)
+		if err != nil {
+			return errors.Wrapf(err, "unable to export control flow graph of function %s", f.Ident())
+		}
+		if err := e.write(filepath.Join(dir, fileName(funcName(f))), dot); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if e.callGraph {
+		dot := analysis.NewCallGraph(m).Dot()
+		if err := e.write(filepath.Join(dir, "callgraph.dot"), dot); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// write writes the given DOT graph to the given path. It is an error to write
+// to the same path twice.
+func (e *exporter) write(dotPath, dot string) error {
+	if e.written[dotPath] {
+		return errors.Errorf("output file %q already written", dotPath)
+	}
+	e.written[dotPath] = true
+	fmt.Fprintf(os.Stderr, "creating %q\n", dotPath)
+	if err := ioutil.WriteFile(dotPath, []byte(dot), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// funcName returns the name of the given function, or its ID if unnamed (e.g.
+// "0" for @0).
+func funcName(f *ir.Function) string {
+	if f.IsUnnamed() {
+		return strconv.FormatInt(f.ID(), 10)
+	}
+	return f.Name()
+}
+
+// fileName returns the DOT file name of the function with the given name,
+// replacing path separators and NUL characters, and a leading dot which would
+// otherwise hide the file.
+func fileName(funcName string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == os.PathSeparator || r == 0 {
+			return '_'
+		}
+		return r
+	}, funcName)
+	if strings.HasPrefix(name, ".") {
+		name = "_" + name[1:]
+	}
+	return name + ".dot"
+}
@@ -0,0 +1,252 @@
+// The llfmt tool formats LLVM IR assembly files.
+//
+// llfmt parses LLVM IR assembly and outputs the llir/llvm string representation
+// of the same LLVM IR module, preserving comments. Without an explicit path, it
+// processes the standard input. Given a file, it operates on that file; given a
+// directory, it operates on all .ll files in that directory, recursively. By
+// default, llfmt prints the formatted sources to standard output.
+//
+// Usage:
+//
+//    llfmt [OPTION]... [PATH]...
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/pkg/errors"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+func usage() {
+	const use = `
+Format LLVM IR assembly files.
+
+Usage:
+
+	llfmt [OPTION]... [PATH]...
+
+Flags:
+`
+	fmt.Fprintln(os.Stderr, use[1:])
+	flag.PrintDefaults()
+}
+
+// formatter formats LLVM IR assembly files.
+type formatter struct {
+	// Write result to source file instead of standard output.
+	write bool
+	// List files whose formatting differs from llfmt's.
+	list bool
+	// Display diffs instead of rewriting files.
+	diff bool
+	// Renumber unnamed local variables sequentially.
+	renumber bool
+	// Sort top-level entities by name.
+	sort bool
+}
+
+func main() {
+	var f formatter
+	flag.BoolVar(&f.write, "w", false, "write result to (source) file instead of stdout")
+	flag.BoolVar(&f.list, "l", false, "list files whose formatting differs from llfmt's")
+	flag.BoolVar(&f.diff, "d", false, "display diffs instead of rewriting files")
+	flag.BoolVar(&f.renumber, "renumber", false, "renumber unnamed local variables sequentially")
+	flag.BoolVar(&f.sort, "sort", false, "sort global variables, functions, aliases and IFuncs by name")
+	flag.Usage = usage
+	flag.Parse()
+	exitCode := 0
+	if flag.NArg() == 0 {
+		if f.write {
+			fmt.Fprintln(os.Stderr, "error: cannot use -w with standard input")
+			os.Exit(2)
+		}
+		if err := f.formatStdin(); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			exitCode = 2
+		}
+		os.Exit(exitCode)
+	}
+	for _, path := range flag.Args() {
+		if err := f.formatPath(path); err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			exitCode = 2
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// formatStdin formats the LLVM IR assembly read from standard input.
+func (f *formatter) formatStdin() error {
+	src, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	return f.formatFile("<standard input>", src, 0, true)
+}
+
+// formatPath formats the given LLVM IR assembly file, or the .ll files of the
+// given directory, recursively.
+func (f *formatter) formatPath(root string) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		// Format files given explicitly, regardless of extension.
+		if info.IsDir() || (path != root && filepath.Ext(path) != ".ll") {
+			return nil
+		}
+		src, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		return f.formatFile(path, src, info.Mode(), false)
+	})
+}
+
+// formatFile formats the given LLVM IR assembly source of the specified file.
+func (f *formatter) formatFile(path string, src []byte, mode os.FileMode, stdin bool) error {
+	m, err := asm.ParseBytes(path, src)
+	if err != nil {
+		return errors.Wrapf(err, "unable to parse %q", path)
+	}
+	if f.renumber {
+		if err := renumber(m); err != nil {
+			return errors.Wrapf(err, "unable to renumber local variables of %q", path)
+		}
+	}
+	if f.sort {
+		sortEntities(m)
+	}
+	res := []byte(m.String())
+	if !f.list && !f.write && !f.diff {
+		if _, err := os.Stdout.Write(res); err != nil {
+			return errors.WithStack(err)
+		}
+		return nil
+	}
+	if bytes.Equal(src, res) {
+		return nil
+	}
+	if f.list {
+		fmt.Println(path)
+	}
+	if f.write && !stdin {
+		if err := ioutil.WriteFile(path, res, mode); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	if f.diff {
+		fmt.Printf("--- %s.orig\n+++ %s\n", path, path)
+		fmt.Print(diff(string(src), string(res)))
+	}
+	return nil
+}
+
+// renumber numbers the unnamed local variables of the given module
+// sequentially. Named local variables are left unchanged.
+func renumber(m *ir.Module) error {
+	type local interface {
+		IsUnnamed() bool
+		SetID(id int64)
+	}
+	reset := func(v interface{}) {
+		if v, ok := v.(local); ok && v.IsUnnamed() {
+			v.SetID(0)
+		}
+	}
+	for _, f := range m.Funcs {
+		if len(f.Blocks) == 0 {
+			continue
+		}
+		for _, param := range f.Params {
+			reset(param)
+		}
+		for _, block := range f.Blocks {
+			reset(block)
+			for _, inst := range block.Insts {
+				reset(inst)
+			}
+			reset(block.Term)
+		}
+		if err := f.AssignIDs(); err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	return nil
+}
+
+// sortEntities sorts the global variables, functions, aliases and IFuncs of the
+// given module by name.
+func sortEntities(m *ir.Module) {
+	sort.SliceStable(m.Globals, func(i, j int) bool {
+		return m.Globals[i].Name() < m.Globals[j].Name()
+	})
+	sort.SliceStable(m.Funcs, func(i, j int) bool {
+		return m.Funcs[i].Name() < m.Funcs[j].Name()
+	})
+	sort.SliceStable(m.Aliases, func(i, j int) bool {
+		return m.Aliases[i].Name() < m.Aliases[j].Name()
+	})
+	sort.SliceStable(m.IFuncs, func(i, j int) bool {
+		return m.IFuncs[i].Name() < m.IFuncs[j].Name()
+	})
+}
+
+// diff returns the line-based difference between a and b, with three lines of
+// context surrounding each change.
+func diff(a, b string) string {
+	const context = 3
+	dmp := diffmatchpatch.New()
+	x, y, lines := dmp.DiffLinesToChars(a, b)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(x, y, false), lines)
+	// Prefixed lines of the difference.
+	var ls []string
+	for _, d := range diffs {
+		var op string
+		switch d.Type {
+		case diffmatchpatch.DiffEqual:
+			op = " "
+		case diffmatchpatch.DiffDelete:
+			op = "-"
+		case diffmatchpatch.DiffInsert:
+			op = "+"
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if len(line) > 0 {
+				ls = append(ls, op+strings.TrimSuffix(line, "\n"))
+			}
+		}
+	}
+	// Output changed lines and their context.
+	buf := &strings.Builder{}
+	last := -1
+	for i, line := range ls {
+		keep := false
+		for j := i - context; j <= i+context; j++ {
+			if j >= 0 && j < len(ls) && ls[j][0] != ' ' {
+				keep = true
+				break
+			}
+		}
+		if !keep {
+			continue
+		}
+		if last != i-1 {
+			buf.WriteString("@@\n")
+		}
+		buf.WriteString(line)
+		buf.WriteString("\n")
+		last = i
+	}
+	return buf.String()
+}
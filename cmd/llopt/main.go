@@ -0,0 +1,201 @@
+// The llopt tool runs a pipeline of transformation passes on LLVM IR assembly.
+//
+// llopt parses an LLVM IR assembly file (or standard input if no file is
+// given), runs the passes specified by the -passes flag in order, and outputs
+// the string representation of the transformed LLVM IR module.
+//
+// Passes are looked up in the pass registry of the transform package; the
+// available passes are listed by the -list flag. Custom passes are registered
+// with transform.Register, either from a Go plugin loaded by the -load flag, or
+// from a package imported by a copy of this command.
+//
+// Usage:
+//
+//    llopt [OPTION]... [FILE.ll]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"plugin"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/transform"
+	"github.com/pkg/errors"
+)
+
+func usage() {
+	const use = `
+Run a pipeline of transformation passes on LLVM IR assembly.
+
+Usage:
+
+	llopt [OPTION]... [FILE.ll]
+
+Flags:
+`
+	fmt.Fprintln(os.Stderr, use[1:])
+	flag.PrintDefaults()
+}
+
+// pipeline is a sequence of transformation passes.
+type pipeline struct {
+	// Passes in order of execution.
+	passes []*transform.Pass
+	// Report the execution time of each pass.
+	timePasses bool
+	// Verify the module after each pass.
+	verifyEach bool
+	// Output the module after each pass.
+	printAfterAll bool
+}
+
+func main() {
+	var (
+		// Comma-separated list of passes.
+		passNames string
+		// Comma-separated list of Go plugins.
+		plugins string
+		// List the registered passes.
+		list bool
+		// Output path.
+		output string
+		p      pipeline
+	)
+	flag.StringVar(&passNames, "passes", "", "comma-separated list of passes to run (e.g. internalize,globaldce)")
+	flag.StringVar(&plugins, "load", "", "comma-separated list of Go plugins registering custom passes")
+	flag.BoolVar(&list, "list", false, "list the registered passes")
+	flag.StringVar(&output, "o", "", "output path (default: standard output)")
+	flag.BoolVar(&p.timePasses, "time-passes", false, "report the execution time of each pass")
+	flag.BoolVar(&p.verifyEach, "verify-each", false, "verify the module after each pass")
+	flag.BoolVar(&p.printAfterAll, "print-after-all", false, "print the module to standard error after each pass")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if len(plugins) > 0 {
+		for _, path := range strings.Split(plugins, ",") {
+			if _, err := plugin.Open(path); err != nil {
+				fmt.Fprintf(os.Stderr, "error: unable to load plugin %q; %v\n", path, err)
+				os.Exit(2)
+			}
+		}
+	}
+	if list {
+		listPasses(os.Stdout)
+		return
+	}
+	if len(passNames) > 0 {
+		for _, name := range strings.Split(passNames, ",") {
+			pass, ok := transform.LookupPass(strings.TrimSpace(name))
+			if !ok {
+				fmt.Fprintf(os.Stderr, "error: unknown pass %q\n", name)
+				os.Exit(2)
+			}
+			p.passes = append(p.passes, pass)
+		}
+	}
+	if err := p.runFile(flag.Arg(0), output); err != nil {
+		fmt.Fprintf(os.Stderr, "%+v\n", err)
+		os.Exit(1)
+	}
+}
+
+// runFile runs the pipeline on the given LLVM IR assembly file, or standard
+// input if path is empty, and writes the result to the given output path, or
+// standard output if output is empty.
+func (p *pipeline) runFile(path, output string) error {
+	var m *ir.Module
+	var err error
+	if len(path) == 0 {
+		m, err = asm.Parse("<standard input>", os.Stdin)
+	} else {
+		m, err = asm.ParseFile(path)
+	}
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err := p.run(m); err != nil {
+		return errors.WithStack(err)
+	}
+	if len(output) == 0 {
+		_, err := io.WriteString(os.Stdout, m.String())
+		return errors.WithStack(err)
+	}
+	if err := ioutil.WriteFile(output, []byte(m.String()), 0644); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// run runs the passes of the pipeline on the given module.
+func (p *pipeline) run(m *ir.Module) error {
+	var total time.Duration
+	for _, pass := range p.passes {
+		start := time.Now()
+		changed, err := pass.Run(m)
+		elapsed := time.Since(start)
+		total += elapsed
+		if err != nil {
+			return errors.Wrapf(err, "pass %q failed", pass.Name)
+		}
+		if p.timePasses {
+			fmt.Fprintf(os.Stderr, "llopt: pass %q took: %v (changed: %v)\n", pass.Name, elapsed, changed)
+		}
+		if p.printAfterAll {
+			fmt.Fprintf(os.Stderr, "; *** IR Dump After %s ***\n%s", pass.Name, m)
+		}
+		if p.verifyEach {
+			if err := verify(m); err != nil {
+				return errors.Wrapf(err, "invalid module after pass %q", pass.Name)
+			}
+		}
+	}
+	if p.timePasses {
+		fmt.Fprintf(os.Stderr, "llopt: total pass execution took: %v\n", total)
+	}
+	return nil
+}
+
+// verify checks that the given module is valid; i.e. that the operands of its
+// instructions are well-typed, and that its string representation may be
+// parsed.
+func verify(m *ir.Module) (err error) {
+	// Printing panics on invalid local IDs.
+	defer func() {
+		if e := recover(); e != nil {
+			err = errors.Errorf("unable to print module; %v", e)
+		}
+	}()
+	for _, f := range m.Funcs {
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				if err := ir.CheckInst(inst); err != nil {
+					return errors.Wrapf(err, "invalid instruction in function %s", f.Ident())
+				}
+			}
+		}
+	}
+	if _, err := asm.ParseString("<verify>", m.String()); err != nil {
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// listPasses writes the names and descriptions of the registered passes to w.
+func listPasses(w io.Writer) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	for _, pass := range transform.Passes() {
+		fmt.Fprintf(tw, "%s\t%s\n", pass.Name, pass.Desc)
+	}
+	tw.Flush()
+}
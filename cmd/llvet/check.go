@@ -0,0 +1,186 @@
+package main
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/analysis"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Structural checks ] ===================================================
+
+// checkModule runs the structural validity checks on the function definitions
+// of the module.
+func (v *vetter) checkModule() {
+	for _, f := range v.m.Funcs {
+		if len(f.Blocks) == 0 {
+			continue
+		}
+		v.checkFunc(f)
+	}
+}
+
+// checkFunc runs the structural validity checks on the given function
+// definition.
+func (v *vetter) checkFunc(f *ir.Function) {
+	preds := analysis.Preds(f)
+	if len(preds[f.Blocks[0]]) > 0 {
+		v.report(f.Blocks[0], "entry basic block of function %s has predecessors", f.Ident())
+	}
+	for _, block := range f.Blocks {
+		v.checkPhis(block, preds[block])
+		for _, inst := range block.Insts {
+			if err := ir.CheckInst(inst); err != nil {
+				v.report(inst, "%v", err)
+			}
+		}
+		if ret, ok := block.Term.(*ir.TermRet); ok {
+			v.checkRet(f, ret)
+		}
+	}
+	v.checkDominance(f)
+}
+
+// checkPhis checks that the phi instructions of the given basic block precede
+// all other instructions, and that their incoming basic blocks match the
+// predecessors of the basic block.
+func (v *vetter) checkPhis(block *ir.BasicBlock, preds []*ir.BasicBlock) {
+	isPred := make(map[*ir.BasicBlock]bool)
+	for _, pred := range preds {
+		isPred[pred] = true
+	}
+	nonPhi := false
+	for _, inst := range block.Insts {
+		phi, ok := inst.(*ir.InstPhi)
+		if !ok {
+			nonPhi = true
+			continue
+		}
+		if nonPhi {
+			v.report(phi, "phi instruction %s is not at the beginning of basic block %s", phi.Ident(), block.Ident())
+		}
+		hasInc := make(map[*ir.BasicBlock]bool)
+		for _, inc := range phi.Incs {
+			if !isPred[inc.Pred] {
+				v.report(phi, "incoming basic block %s of phi instruction %s is not a predecessor of basic block %s", inc.Pred.Ident(), phi.Ident(), block.Ident())
+			}
+			hasInc[inc.Pred] = true
+		}
+		for _, pred := range preds {
+			if !hasInc[pred] {
+				v.report(phi, "phi instruction %s has no incoming value for predecessor %s", phi.Ident(), pred.Ident())
+			}
+		}
+	}
+}
+
+// checkRet checks that the return value of the given ret terminator matches
+// the return type of the function.
+func (v *vetter) checkRet(f *ir.Function, ret *ir.TermRet) {
+	want := f.Sig.RetType
+	switch {
+	case ret.X == nil:
+		if !types.Equal(want, types.Void) {
+			v.report(ret, "missing return value of type %s in function %s", want, f.Ident())
+		}
+	case !types.Equal(ret.X.Type(), want):
+		v.report(ret, "return value of type %s does not match return type %s of function %s", ret.X.Type(), want, f.Ident())
+	}
+}
+
+// checkDominance checks that the definitions of the local variables used as
+// operands of the given function dominate their uses. Uses in unreachable basic
+// blocks are not checked.
+func (v *vetter) checkDominance(f *ir.Function) {
+	dom := analysis.NewDomTree(f)
+	preds := analysis.Preds(f)
+	// Basic block and index of each instruction and terminator; terminators have
+	// the index len(block.Insts).
+	type location struct {
+		block *ir.BasicBlock
+		index int
+	}
+	defs := make(map[value.Value]location)
+	for _, block := range f.Blocks {
+		for i, inst := range block.Insts {
+			if inst, ok := inst.(value.Value); ok {
+				defs[inst] = location{block: block, index: i}
+			}
+		}
+		if term, ok := block.Term.(value.Value); ok {
+			defs[term] = location{block: block, index: len(block.Insts)}
+		}
+	}
+	// edgeDominates reports whether every path from the entry basic block to c
+	// passes through the edge from a to its successor b.
+	edgeDominates := func(a, b, c *ir.BasicBlock) bool {
+		n := 0
+		for _, succ := range a.Term.Succs() {
+			if succ == b {
+				n++
+			}
+		}
+		if n != 1 {
+			return false
+		}
+		// Apart from the edge, b may only be entered through back edges.
+		for _, pred := range preds[b] {
+			if pred != a && dom.Reachable(pred) && !dom.Dominates(b, pred) {
+				return false
+			}
+		}
+		return dom.Dominates(b, c)
+	}
+	// dominates reports whether the definition of x dominates a use at the given
+	// location; a use at index -1 denotes the end of the basic block.
+	dominates := func(x value.Value, use location) bool {
+		def, ok := defs[x]
+		if !ok || !dom.Reachable(def.block) {
+			return true
+		}
+		// The result of an invoke terminator is only available through its normal
+		// edge.
+		if invoke, ok := x.(*ir.TermInvoke); ok {
+			return edgeDominates(def.block, invoke.Normal, use.block)
+		}
+		if def.block != use.block {
+			return dom.Dominates(def.block, use.block)
+		}
+		// The result of a terminator is only available in its successors.
+		if def.index == len(def.block.Insts) {
+			return false
+		}
+		return use.index == -1 || def.index < use.index
+	}
+	check := func(user interface{}, use location) {
+		if !dom.Reachable(use.block) {
+			return
+		}
+		if phi, ok := user.(*ir.InstPhi); ok {
+			// Incoming values are used at the end of the incoming basic blocks.
+			for _, inc := range phi.Incs {
+				// The result of an invoke is available on its normal edge.
+				if invoke, ok := inc.X.(*ir.TermInvoke); ok && invoke.Normal == use.block && inc.Pred == defs[invoke].block {
+					continue
+				}
+				pred := location{block: inc.Pred, index: -1}
+				if dom.Reachable(inc.Pred) && !dominates(inc.X, pred) {
+					v.report(phi, "definition of %s does not dominate its use in phi instruction %s", inc.X.Ident(), phi.Ident())
+				}
+			}
+			return
+		}
+		for _, op := range irutil.Operands(user) {
+			if !dominates(op, use) {
+				v.report(user, "definition of %s does not dominate its use", op.Ident())
+			}
+		}
+	}
+	for _, block := range f.Blocks {
+		for i, inst := range block.Insts {
+			check(inst, location{block: block, index: i})
+		}
+		check(block.Term, location{block: block, index: len(block.Insts)})
+	}
+}
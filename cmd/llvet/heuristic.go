@@ -0,0 +1,214 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/analysis"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Heuristic checks ] ====================================================
+
+// lintModule runs the heuristic checks on the module.
+func (v *vetter) lintModule() {
+	// Values used as operands in the module.
+	used := make(map[value.Value]bool)
+	irutil.Walk(v.m, func(c *irutil.Cursor) bool {
+		if c.IsOperand() {
+			used[c.Node().(value.Value)] = true
+		}
+		return true
+	}, nil)
+	v.lintUnusedDecls(used)
+	var layout *dataLayout
+	if len(v.m.DataLayout) > 0 {
+		layout = parseDataLayout(v.m.DataLayout)
+	}
+	for _, g := range v.m.Globals {
+		v.lintAlign(g, g.Align, g.ContentType, layout)
+	}
+	for _, f := range v.m.Funcs {
+		if len(f.Blocks) == 0 {
+			continue
+		}
+		for _, param := range f.Params {
+			if !used[param] {
+				v.report(param, "parameter %s of function %s is unused", param.Ident(), f.Ident())
+			}
+		}
+		v.lintUninitLoads(f)
+		v.lintNoReturn(f)
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				switch inst := inst.(type) {
+				case *ir.InstAlloca:
+					v.lintAlign(inst, inst.Align, inst.ElemType, layout)
+				case *ir.InstLoad:
+					v.lintAlign(inst, inst.Align, inst.Typ, layout)
+				case *ir.InstStore:
+					v.lintAlign(inst, inst.Align, inst.Src.Type(), layout)
+				case *ir.InstCall:
+					v.lintCallingConv(inst, inst.CallingConv, inst.Callee)
+				}
+			}
+			if term, ok := block.Term.(*ir.TermInvoke); ok {
+				v.lintCallingConv(term, term.CallingConv, term.Invokee)
+			}
+		}
+	}
+}
+
+// lintUnusedDecls reports the global variable and function declarations of the
+// module which are not used.
+func (v *vetter) lintUnusedDecls(used map[value.Value]bool) {
+	for _, g := range v.m.Globals {
+		if g.Init == nil && !used[g] {
+			v.report(g, "global variable %s is declared but not used", g.Ident())
+		}
+	}
+	for _, f := range v.m.Funcs {
+		if len(f.Blocks) == 0 && !used[f] {
+			v.report(f, "function %s is declared but not used", f.Ident())
+		}
+	}
+}
+
+// lintUninitLoads reports the loads from allocas of the given function which
+// are not preceded by a store to the alloca on any path. Only allocas which do
+// not escape (i.e. which are only used as the source of loads and the
+// destination of stores) are considered.
+func (v *vetter) lintUninitLoads(f *ir.Function) {
+	// Allocas which do not escape.
+	allocas := make(map[value.Value]bool)
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if alloca, ok := inst.(*ir.InstAlloca); ok {
+				allocas[alloca] = true
+			}
+		}
+	}
+	escape := func(user interface{}) {
+		for _, op := range irutil.Operands(user) {
+			delete(allocas, op)
+		}
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			switch inst := inst.(type) {
+			case *ir.InstLoad:
+				// Loads do not leak the source address.
+			case *ir.InstStore:
+				// Stores leak the stored value but not the destination address.
+				delete(allocas, inst.Src)
+			default:
+				escape(inst)
+			}
+		}
+		escape(block.Term)
+	}
+	if len(allocas) == 0 {
+		return
+	}
+	reaching := analysis.NewReachingStores(f)
+	for _, block := range analysis.ReversePostorder(f) {
+		for i, inst := range block.Insts {
+			load, ok := inst.(*ir.InstLoad)
+			if !ok || !allocas[load.Src] {
+				continue
+			}
+			if len(reaching.StoresTo(load.Src, block, i)) == 0 {
+				v.report(load, "load from uninitialized alloca %s", load.Src.Ident())
+			}
+		}
+	}
+}
+
+// lintNoReturn reports the reachable ret terminators of the given function if
+// declared noreturn.
+func (v *vetter) lintNoReturn(f *ir.Function) {
+	if !hasFuncAttr(f.FuncAttrs, enum.FuncAttrNoReturn) {
+		return
+	}
+	for _, block := range analysis.ReversePostorder(f) {
+		if _, ok := block.Term.(*ir.TermRet); ok {
+			v.report(block.Term, "function %s is declared noreturn but returns", f.Ident())
+		}
+	}
+}
+
+// lintCallingConv reports the given call or invoke if its calling convention
+// does not match the calling convention of the callee. Only direct calls are
+// considered.
+func (v *vetter) lintCallingConv(call interface{}, callingConv enum.CallingConv, callee value.Value) {
+	f, ok := callee.(*ir.Function)
+	if !ok {
+		return
+	}
+	if normCallingConv(callingConv) != normCallingConv(f.CallingConv) {
+		v.report(call, "calling convention %s of call does not match calling convention %s of callee %s", callingConvString(callingConv), callingConvString(f.CallingConv), f.Ident())
+	}
+}
+
+// lintAlign reports the alignment of the given entity if it is not a power of
+// two, or if it is less than the ABI alignment of the given type specified by
+// the data layout. The data layout is nil if not specified.
+func (v *vetter) lintAlign(entity interface{}, align ir.Align, t types.Type, layout *dataLayout) {
+	if align == 0 {
+		return
+	}
+	if align&(align-1) != 0 {
+		v.report(entity, "alignment %d is not a power of two", align)
+		return
+	}
+	if layout == nil {
+		return
+	}
+	if abi, ok := layout.abiAlign(t); ok && int64(align) < abi {
+		v.report(entity, "alignment %d of %s is less than its ABI alignment %d specified by the data layout", align, t, abi)
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// hasFuncAttr reports whether the given function attributes, including those of
+// referenced attribute groups, contain the given attribute.
+func hasFuncAttr(attrs []ir.FuncAttribute, want enum.FuncAttr) bool {
+	for _, attr := range attrs {
+		switch attr := attr.(type) {
+		case enum.FuncAttr:
+			if attr == want {
+				return true
+			}
+		case *ir.AttrGroupDef:
+			if hasFuncAttr(attr.FuncAttrs, want) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// normCallingConv returns the given calling convention, with the default
+// calling convention replaced by the C calling convention.
+func normCallingConv(callingConv enum.CallingConv) enum.CallingConv {
+	if callingConv == enum.CallingConvNone {
+		return enum.CallingConvC
+	}
+	return callingConv
+}
+
+// callingConvString returns the string representation of the given calling
+// convention.
+func callingConvString(callingConv enum.CallingConv) string {
+	callingConv = normCallingConv(callingConv)
+	s := callingConv.String()
+	cc := uint(callingConv)
+	if unknown := fmt.Sprintf("CallingConv(%d)", cc); s == unknown {
+		return fmt.Sprintf("cc %d", cc)
+	}
+	return s
+}
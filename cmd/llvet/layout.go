@@ -0,0 +1,141 @@
+package main
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+// === [ Data layout ] =========================================================
+
+// dataLayout specifies the ABI alignments of types, as specified by the data
+// layout string of a module. Only the alignments explicitly specified are
+// recorded.
+type dataLayout struct {
+	// ABI alignment in bytes of integer types, indexed by bit size.
+	ints map[int64]int64
+	// ABI alignment in bytes of floating-point types, indexed by bit size.
+	floats map[int64]int64
+	// ABI alignment in bytes of vector types, indexed by bit size.
+	vectors map[int64]int64
+	// ABI alignment in bytes of pointer types, indexed by address space.
+	pointers map[types.AddrSpace]int64
+}
+
+// parseDataLayout parses the given data layout string (e.g.
+// "e-m:e-i64:64-f80:128-n8:16:32:64-S128"). Malformed specifications are
+// ignored.
+func parseDataLayout(s string) *dataLayout {
+	layout := &dataLayout{
+		ints:     make(map[int64]int64),
+		floats:   make(map[int64]int64),
+		vectors:  make(map[int64]int64),
+		pointers: make(map[types.AddrSpace]int64),
+	}
+	for _, spec := range strings.Split(s, "-") {
+		if len(spec) == 0 {
+			continue
+		}
+		// Specifications have the form "[ifv]size:abi[:pref]" and
+		// "p[n]:size:abi[:pref[:idx]]", with alignments specified in bits.
+		fields := strings.Split(spec[1:], ":")
+		switch spec[0] {
+		case 'i', 'f', 'v':
+			if len(fields) < 2 {
+				continue
+			}
+			size, err := strconv.ParseInt(fields[0], 10, 64)
+			if err != nil {
+				continue
+			}
+			abi, err := strconv.ParseInt(fields[1], 10, 64)
+			if err != nil {
+				continue
+			}
+			switch spec[0] {
+			case 'i':
+				layout.ints[size] = abi / 8
+			case 'f':
+				layout.floats[size] = abi / 8
+			case 'v':
+				layout.vectors[size] = abi / 8
+			}
+		case 'p':
+			if len(fields) < 3 {
+				continue
+			}
+			var addrSpace int64
+			if len(fields[0]) > 0 {
+				var err error
+				if addrSpace, err = strconv.ParseInt(fields[0], 10, 64); err != nil {
+					continue
+				}
+			}
+			abi, err := strconv.ParseInt(fields[2], 10, 64)
+			if err != nil {
+				continue
+			}
+			layout.pointers[types.AddrSpace(addrSpace)] = abi / 8
+		}
+	}
+	return layout
+}
+
+// abiAlign returns the ABI alignment in bytes of the given type, as specified
+// by the data layout. The boolean return value indicates success.
+func (layout *dataLayout) abiAlign(t types.Type) (int64, bool) {
+	switch t := t.(type) {
+	case *types.IntType:
+		align, ok := layout.ints[t.BitSize]
+		return align, ok
+	case *types.FloatType:
+		size, ok := floatSize(t.Kind)
+		if !ok {
+			return 0, false
+		}
+		align, ok := layout.floats[size]
+		return align, ok
+	case *types.VectorType:
+		if t.Scalable {
+			return 0, false
+		}
+		var elemSize int64
+		switch elem := t.ElemType.(type) {
+		case *types.IntType:
+			elemSize = elem.BitSize
+		case *types.FloatType:
+			size, ok := floatSize(elem.Kind)
+			if !ok {
+				return 0, false
+			}
+			elemSize = size
+		default:
+			return 0, false
+		}
+		align, ok := layout.vectors[int64(t.Len)*elemSize]
+		return align, ok
+	case *types.PointerType:
+		align, ok := layout.pointers[t.AddrSpace]
+		return align, ok
+	}
+	return 0, false
+}
+
+// floatSize returns the bit size of the given floating-point kind. The boolean
+// return value indicates success.
+func floatSize(kind types.FloatKind) (int64, bool) {
+	switch kind {
+	case types.FloatKindHalf, types.FloatKindBFloat:
+		return 16, true
+	case types.FloatKindFloat:
+		return 32, true
+	case types.FloatKindDouble:
+		return 64, true
+	case types.FloatKindX86FP80:
+		return 80, true
+	case types.FloatKindFP128, types.FloatKindPPCFP128:
+		return 128, true
+	}
+	return 0, false
+}
@@ -0,0 +1,189 @@
+// The llvet tool reports likely mistakes in LLVM IR assembly files.
+//
+// llvet parses LLVM IR assembly files and runs structural validity checks
+// (e.g. operand types, phi instructions, dominance of uses) as well as
+// heuristic checks (e.g. loads from uninitialized allocas, unused parameters)
+// on the resulting LLVM IR modules. Reported problems are output one per line
+// as
+//
+//    file:line:col: message
+//
+// llvet exits with status 1 if any problem is reported, and with status 2 if
+// an input file could not be parsed.
+//
+// Usage:
+//
+//    llvet [OPTION]... FILE.ll...
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/llir/ll/ast"
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/pkg/errors"
+)
+
+func usage() {
+	const use = `
+Report likely mistakes in LLVM IR assembly files.
+
+Usage:
+
+	llvet [OPTION]... FILE.ll...
+
+Flags:
+`
+	fmt.Fprintln(os.Stderr, use[1:])
+	flag.PrintDefaults()
+}
+
+func main() {
+	var structural bool
+	var heuristic bool
+	flag.BoolVar(&structural, "structural", true, "run structural validity checks")
+	flag.BoolVar(&heuristic, "heuristic", true, "run heuristic checks")
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	exitCode := 0
+	for _, path := range flag.Args() {
+		diags, err := vetFile(path, structural, heuristic)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%+v\n", err)
+			exitCode = 2
+			continue
+		}
+		for _, diag := range diags {
+			fmt.Println(diag)
+		}
+		if len(diags) > 0 && exitCode == 0 {
+			exitCode = 1
+		}
+	}
+	os.Exit(exitCode)
+}
+
+// vetFile runs the enabled checks on the given LLVM IR assembly file, and
+// returns the problems found in order of occurrence.
+func vetFile(path string, structural, heuristic bool) ([]*diagnostic, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	content := string(buf)
+	m, err := asm.ParseString(path, content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %q", path)
+	}
+	// Parse the input into an AST to locate the IR entities.
+	tree, err := ast.Parse(path, content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to parse %q into AST", path)
+	}
+	old := ast.ToLlvmNode(tree.Root()).(*ast.Module)
+	v := &vetter{path: path, m: m, nodes: locate(m, old)}
+	if structural {
+		v.checkModule()
+	}
+	if heuristic {
+		v.lintModule()
+	}
+	sort.SliceStable(v.diags, func(i, j int) bool {
+		a, b := v.diags[i], v.diags[j]
+		if a.line != b.line {
+			return a.line < b.line
+		}
+		return a.col < b.col
+	})
+	return v.diags, nil
+}
+
+// vetter reports the problems of an LLVM IR module.
+type vetter struct {
+	// Path of the LLVM IR assembly file.
+	path string
+	// LLVM IR module.
+	m *ir.Module
+	// AST nodes of IR entities; used to locate problems.
+	nodes map[interface{}]*ast.Node
+	// Problems found.
+	diags []*diagnostic
+}
+
+// diagnostic is a problem found in an LLVM IR assembly file.
+type diagnostic struct {
+	// Path of the LLVM IR assembly file.
+	path string
+	// Line and column number of the problem (1-based).
+	line, col int
+	// Problem description.
+	msg string
+}
+
+// String returns the string representation of the diagnostic.
+func (diag *diagnostic) String() string {
+	return fmt.Sprintf("%s:%d:%d: %s", diag.path, diag.line, diag.col, diag.msg)
+}
+
+// report records a problem of the given IR entity.
+func (v *vetter) report(entity interface{}, format string, args ...interface{}) {
+	// Locate problems of entities without AST node (e.g. entities not present in
+	// the input) at the beginning of the file.
+	line, col := 1, 1
+	if n, ok := v.nodes[entity]; ok {
+		line, col = n.LineColumn()
+	}
+	diag := &diagnostic{path: v.path, line: line, col: col, msg: fmt.Sprintf(format, args...)}
+	v.diags = append(v.diags, diag)
+}
+
+// locate returns the AST nodes of the global variables, functions, function
+// parameters, basic blocks, instructions and terminators of the given IR
+// module, which was translated from the given AST module.
+func locate(m *ir.Module, old *ast.Module) map[interface{}]*ast.Node {
+	nodes := make(map[interface{}]*ast.Node)
+	// Global variables and functions are translated in order of occurrence.
+	var globals, funcs int
+	for _, entity := range old.TopLevelEntities() {
+		switch entity := entity.(type) {
+		case *ast.GlobalDecl:
+			nodes[m.Globals[globals]] = entity.LlvmNode()
+			globals++
+		case *ast.FuncDecl:
+			f := m.Funcs[funcs]
+			nodes[f] = entity.LlvmNode()
+			locateParams(nodes, f, entity.Header())
+			funcs++
+		case *ast.FuncDef:
+			f := m.Funcs[funcs]
+			nodes[f] = entity.LlvmNode()
+			locateParams(nodes, f, entity.Header())
+			for i, oldBlock := range entity.Body().Blocks() {
+				block := f.Blocks[i]
+				nodes[block] = oldBlock.LlvmNode()
+				for j, oldInst := range oldBlock.Insts() {
+					nodes[block.Insts[j]] = oldInst.LlvmNode()
+				}
+				nodes[block.Term] = oldBlock.Term().LlvmNode()
+			}
+			funcs++
+		}
+	}
+	return nodes
+}
+
+// locateParams records the AST nodes of the parameters of the given function.
+func locateParams(nodes map[interface{}]*ast.Node, f *ir.Function, hdr ast.FuncHeader) {
+	for i, oldParam := range hdr.Params().Params() {
+		nodes[f.Params[i]] = oldParam.LlvmNode()
+	}
+}
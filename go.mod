@@ -1,15 +1,16 @@
 module github.com/llir/llvm
 
+go 1.13
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/kr/pretty v0.1.0
-	github.com/llir/ll v0.0.0-20181130124432-c921b17125cd
+	github.com/llir/ll v0.0.0-20220802044011-65001c0fb73c
 	github.com/mewkiz/pkg v0.0.0-20181119122551-9729f4f4ff2b
-	github.com/mewmew/float v0.0.0-20181121163145-c0f786d7da73
 	github.com/mewspring/tools v0.0.0-20181107085742-4dbfa080ff87
 	github.com/pkg/errors v0.8.0
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/sergi/go-diff v1.0.0
 	github.com/stretchr/testify v1.2.2 // indirect
-	golang.org/x/tools v0.0.0-20181130052023-1c3d964395ce
+	golang.org/x/tools v0.1.4
 )
@@ -0,0 +1,323 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Call graph ] ==========================================================
+
+// CallGraph is the call graph of a module.
+type CallGraph struct {
+	// Call graph nodes; one per function of the module, in order of occurrence.
+	Nodes []*CallNode
+	// Functions of the module whose address is taken; i.e. functions referenced
+	// other than as the callee of a call instruction or invoke terminator, in
+	// order of occurrence. Address-taken functions are the potential targets of
+	// indirect calls.
+	AddressTaken []*ir.Function
+
+	// Call graph node of each function.
+	nodes map[*ir.Function]*CallNode
+}
+
+// CallNode is a call graph node of a function.
+type CallNode struct {
+	// Function.
+	Func *ir.Function
+	// Outgoing call edges, in order of call sites in the function.
+	Callees []*CallEdge
+	// Incoming call edges.
+	Callers []*CallEdge
+}
+
+// CallEdge is a call graph edge from a call site to a potential callee.
+type CallEdge struct {
+	// Calling function.
+	Caller *CallNode
+	// Called function.
+	Callee *CallNode
+	// Call site.
+	//
+	// Site has one of the following underlying types.
+	//
+	//    *ir.InstCall
+	//    *ir.TermInvoke
+	Site value.Value
+	// Call site is an indirect call, and Callee is one of its conservatively
+	// resolved targets.
+	Indirect bool
+}
+
+// NewCallGraph returns the call graph of the given module.
+//
+// Edges are recorded from call instructions and invoke terminators to their
+// callee, seeing through bitcast constant expressions and aliases. Indirect
+// calls are conservatively resolved to every address-taken function of the
+// module with a function signature equal to that of the call site.
+func NewCallGraph(m *ir.Module) *CallGraph {
+	cg := &CallGraph{nodes: make(map[*ir.Function]*CallNode)}
+	for _, f := range m.Funcs {
+		node := &CallNode{Func: f}
+		cg.Nodes = append(cg.Nodes, node)
+		cg.nodes[f] = node
+	}
+	cg.AddressTaken = addressTaken(m)
+	for _, caller := range cg.Nodes {
+		for _, block := range caller.Func.Blocks {
+			for _, inst := range block.Insts {
+				if call, ok := inst.(*ir.InstCall); ok {
+					cg.addCall(caller, call, call.Callee)
+				}
+			}
+			if invoke, ok := block.Term.(*ir.TermInvoke); ok {
+				cg.addCall(caller, invoke, invoke.Invokee)
+			}
+		}
+	}
+	return cg
+}
+
+// Node returns the call graph node of the given function, or nil if the
+// function is not part of the module.
+func (cg *CallGraph) Node(f *ir.Function) *CallNode {
+	return cg.nodes[f]
+}
+
+// SCCs returns the strongly connected components of the call graph in
+// bottom-up order; i.e. each component precedes the components of its callers
+// (except for calls within the component). The call graph nodes of each
+// component are in order of occurrence in the module.
+//
+// The strongly connected components are computed using Tarjan's algorithm.
+func (cg *CallGraph) SCCs() [][]*CallNode {
+	var (
+		result  [][]*CallNode
+		stack   []*CallNode
+		onStack = make(map[*CallNode]bool)
+		index   = make(map[*CallNode]int)
+		lowlink = make(map[*CallNode]int)
+		order   = make(map[*CallNode]int)
+		n       = 0
+	)
+	for i, node := range cg.Nodes {
+		order[node] = i
+	}
+	var connect func(node *CallNode)
+	connect = func(node *CallNode) {
+		index[node] = n
+		lowlink[node] = n
+		n++
+		stack = append(stack, node)
+		onStack[node] = true
+		for _, edge := range node.Callees {
+			callee := edge.Callee
+			if _, ok := index[callee]; !ok {
+				connect(callee)
+				if lowlink[callee] < lowlink[node] {
+					lowlink[node] = lowlink[callee]
+				}
+			} else if onStack[callee] && index[callee] < lowlink[node] {
+				lowlink[node] = index[callee]
+			}
+		}
+		if lowlink[node] != index[node] {
+			return
+		}
+		var scc []*CallNode
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == node {
+				break
+			}
+		}
+		// Sort the component in order of occurrence in the module.
+		sort.Slice(scc, func(i, j int) bool {
+			return order[scc[i]] < order[scc[j]]
+		})
+		result = append(result, scc)
+	}
+	for _, node := range cg.Nodes {
+		if _, ok := index[node]; !ok {
+			connect(node)
+		}
+	}
+	return result
+}
+
+// Reachable returns the functions reachable from the given root functions
+// through call edges (including the roots), in order of occurrence in the
+// module.
+func (cg *CallGraph) Reachable(roots ...*ir.Function) []*ir.Function {
+	visited := make(map[*CallNode]bool)
+	var visit func(node *CallNode)
+	visit = func(node *CallNode) {
+		if visited[node] {
+			return
+		}
+		visited[node] = true
+		for _, edge := range node.Callees {
+			visit(edge.Callee)
+		}
+	}
+	for _, root := range roots {
+		if node := cg.nodes[root]; node != nil {
+			visit(node)
+		}
+	}
+	var funcs []*ir.Function
+	for _, node := range cg.Nodes {
+		if visited[node] {
+			funcs = append(funcs, node.Func)
+		}
+	}
+	return funcs
+}
+
+// ReachableFromMain returns the functions reachable from the main function
+// through call edges (including main), in order of occurrence in the module;
+// or nil if the module has no main function definition.
+func (cg *CallGraph) ReachableFromMain() []*ir.Function {
+	for _, node := range cg.Nodes {
+		if node.Func.Name() == "main" && len(node.Func.Blocks) > 0 {
+			return cg.Reachable(node.Func)
+		}
+	}
+	return nil
+}
+
+// addCall adds call edges from the given call site of the caller to the
+// potential targets of the callee.
+func (cg *CallGraph) addCall(caller *CallNode, site, callee value.Value) {
+	addEdge := func(f *ir.Function, indirect bool) {
+		node := cg.nodes[f]
+		if node == nil {
+			return
+		}
+		edge := &CallEdge{Caller: caller, Callee: node, Site: site, Indirect: indirect}
+		caller.Callees = append(caller.Callees, edge)
+		node.Callers = append(node.Callers, edge)
+	}
+	switch target := resolveCallee(callee).(type) {
+	case *ir.Function:
+		addEdge(target, false)
+	case *ir.InlineAsm:
+		// Inline assembly is not a function.
+	default:
+		sig := calleeSig(callee)
+		if sig == nil {
+			return
+		}
+		for _, f := range cg.AddressTaken {
+			if f.Sig.Equal(sig) {
+				addEdge(f, true)
+			}
+		}
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// resolveCallee returns the underlying value of the given callee, seeing through
+// bitcast constant expressions and aliases.
+func resolveCallee(callee value.Value) value.Value {
+	visited := make(map[*ir.Alias]bool)
+	for {
+		switch v := callee.(type) {
+		case *constant.ExprBitCast:
+			callee = v.From
+		case *ir.Alias:
+			if visited[v] {
+				// Cyclic alias.
+				return v
+			}
+			visited[v] = true
+			callee = v.Aliasee
+		default:
+			return callee
+		}
+	}
+}
+
+// calleeSig returns the function signature of the given callee, or nil if the
+// callee is not of pointer to function type.
+func calleeSig(callee value.Value) *types.FuncType {
+	ptr, ok := callee.Type().(*types.PointerType)
+	if !ok {
+		return nil
+	}
+	sig, _ := ptr.ElemType.(*types.FuncType)
+	return sig
+}
+
+// addressTaken returns the functions of the given module whose address is taken,
+// in order of occurrence in the module.
+func addressTaken(m *ir.Module) []*ir.Function {
+	taken := make(map[*ir.Function]bool)
+	var use func(v value.Value)
+	use = func(v value.Value) {
+		switch v := resolveCallee(v).(type) {
+		case *ir.Function:
+			taken[v] = true
+		case constant.Constant:
+			for _, op := range irutil.Operands(v) {
+				use(op)
+			}
+		}
+	}
+	// useCallee marks functions referenced by the callee of a call site, other
+	// than the called function itself.
+	useCallee := func(callee value.Value) {
+		if _, ok := resolveCallee(callee).(*ir.Function); !ok {
+			use(callee)
+		}
+	}
+	for _, g := range m.Globals {
+		if g.Init != nil {
+			use(g.Init)
+		}
+	}
+	for _, ifunc := range m.IFuncs {
+		use(ifunc.Resolver)
+	}
+	for _, f := range m.Funcs {
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				ops := irutil.Operands(inst)
+				if call, ok := inst.(*ir.InstCall); ok {
+					useCallee(call.Callee)
+					ops = ops[1:]
+				}
+				for _, op := range ops {
+					use(op)
+				}
+			}
+			if block.Term == nil {
+				continue
+			}
+			ops := irutil.Operands(block.Term)
+			if invoke, ok := block.Term.(*ir.TermInvoke); ok {
+				useCallee(invoke.Invokee)
+				ops = ops[1:]
+			}
+			for _, op := range ops {
+				use(op)
+			}
+		}
+	}
+	var funcs []*ir.Function
+	for _, f := range m.Funcs {
+		if taken[f] {
+			funcs = append(funcs, f)
+		}
+	}
+	return funcs
+}
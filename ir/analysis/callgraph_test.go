@@ -0,0 +1,136 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+)
+
+func TestCallGraph(t *testing.T) {
+	const input = `
+@handlers = global [1 x void (i32)*] [void (i32)* @handler]
+
+@alias = alias void (), void ()* @leaf
+
+define i32 @main() personality i8* null {
+	call void bitcast (void (i8*)* @cast to void ()*)()
+	call void @alias()
+	%p = load void (i32)*, void (i32)** getelementptr ([1 x void (i32)*], [1 x void (i32)*]* @handlers, i64 0, i64 0)
+	call void %p(i32 1)
+	invoke void @even(i32 10)
+		to label %normal unwind label %unwind
+
+normal:
+	ret i32 0
+
+unwind:
+	%lp = landingpad { i8*, i32 } cleanup
+	ret i32 1
+}
+
+define void @cast(i8* %x) {
+	ret void
+}
+
+define void @leaf() {
+	ret void
+}
+
+define void @handler(i32 %x) {
+	ret void
+}
+
+define void @other(i32 %x) {
+	call void @leaf()
+	ret void
+}
+
+define void @even(i32 %n) {
+	call void @odd(i32 %n)
+	ret void
+}
+
+define void @odd(i32 %n) {
+	call void @even(i32 %n)
+	call void @leaf()
+	ret void
+}
+
+define void @notaken(i32 %x) {
+	ret void
+}
+
+define void @taker(void (i32)** %dst) {
+	store void (i32)* @other, void (i32)** %dst
+	ret void
+}
+`
+	m, err := asm.ParseString("callgraph.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	cg := NewCallGraph(m)
+	names := func(funcs []*ir.Function) string {
+		var ss []string
+		for _, f := range funcs {
+			ss = append(ss, f.Name())
+		}
+		return strings.Join(ss, " ")
+	}
+	// Address-taken functions.
+	if got, want := names(cg.AddressTaken), "handler other"; got != want {
+		t.Errorf("address-taken functions mismatch; expected %q, got %q", want, got)
+	}
+	// Call edges.
+	golden := map[string]string{
+		"main":    "cast leaf handler* other* even",
+		"cast":    "",
+		"leaf":    "",
+		"handler": "",
+		"other":   "leaf",
+		"even":    "odd",
+		"odd":     "even leaf",
+		"notaken": "",
+		"taker":   "",
+	}
+	for _, node := range cg.Nodes {
+		var callees []string
+		for _, edge := range node.Callees {
+			if edge.Caller != node {
+				t.Errorf("%q: caller mismatch; expected %q, got %q", node.Func.Name(), node.Func.Name(), edge.Caller.Func.Name())
+			}
+			name := edge.Callee.Func.Name()
+			if edge.Indirect {
+				name += "*"
+			}
+			callees = append(callees, name)
+		}
+		if got, want := strings.Join(callees, " "), golden[node.Func.Name()]; got != want {
+			t.Errorf("%q: callees mismatch; expected %q, got %q", node.Func.Name(), want, got)
+		}
+	}
+	if got, want := len(cg.Node(m.Funcs[2]).Callers), 3; got != want {
+		t.Errorf("number of callers of leaf mismatch; expected %d, got %d", want, got)
+	}
+	// Strongly connected components.
+	var sccs []string
+	for _, scc := range cg.SCCs() {
+		var ss []string
+		for _, node := range scc {
+			ss = append(ss, node.Func.Name())
+		}
+		sccs = append(sccs, strings.Join(ss, ","))
+	}
+	if got, want := strings.Join(sccs, " "), "cast leaf handler other even,odd main notaken taker"; got != want {
+		t.Errorf("strongly connected components mismatch; expected %q, got %q", want, got)
+	}
+	// Reachability.
+	if got, want := names(cg.ReachableFromMain()), "main cast leaf handler other even odd"; got != want {
+		t.Errorf("functions reachable from main mismatch; expected %q, got %q", want, got)
+	}
+	if got, want := names(cg.Reachable(m.Funcs[4])), "leaf other"; got != want {
+		t.Errorf("functions reachable from other mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -0,0 +1,150 @@
+package analysis
+
+import (
+	"github.com/llir/llvm/ir"
+)
+
+// === [ Data flow framework ] =================================================
+
+// Direction specifies the direction of a data flow problem.
+type Direction uint8
+
+// Data flow directions.
+const (
+	// Forward data flow problem; facts propagate from the entry basic block
+	// along control flow edges.
+	Forward Direction = iota
+	// Backward data flow problem; facts propagate from the exit basic blocks
+	// against control flow edges.
+	Backward
+)
+
+// Fact is a data flow fact; i.e. an element of the lattice of a data flow
+// problem.
+type Fact interface{}
+
+// Problem is a data flow problem over the basic blocks of a function.
+//
+// Facts passed to the methods of a Problem must not be modified.
+type Problem interface {
+	// Direction returns the direction of the data flow problem.
+	Direction() Direction
+	// Boundary returns the fact at the entry of the entry basic block of
+	// forward problems, or at the exit of the exit basic blocks (i.e. basic
+	// blocks without successors) of backward problems.
+	Boundary() Fact
+	// Initial returns the initial fact of each basic block; the identity of
+	// Meet.
+	Initial() Fact
+	// Meet returns the meet of the given facts, as computed at control flow
+	// merge points.
+	Meet(a, b Fact) Fact
+	// Equal reports whether the given facts are equal.
+	Equal(a, b Fact) bool
+	// Transfer returns the fact at the exit of the given basic block based on
+	// the fact at its entry for forward problems, or the fact at the entry of
+	// the basic block based on the fact at its exit for backward problems.
+	Transfer(block *ir.BasicBlock, fact Fact) Fact
+}
+
+// Solution is the maximal fixed point solution of a data flow problem.
+type Solution struct {
+	// Fact at the entry of each basic block.
+	In map[*ir.BasicBlock]Fact
+	// Fact at the exit of each basic block.
+	Out map[*ir.BasicBlock]Fact
+}
+
+// Solve solves the given data flow problem over the basic blocks of the
+// function, using an iterative worklist algorithm. The predecessors and
+// successors of basic blocks are determined by the successors of their
+// terminators (see Succs).
+//
+// Basic blocks are visited in reverse postorder for forward problems, and in
+// postorder for backward problems; basic blocks not reachable from the entry
+// basic block are visited last.
+func Solve(f *ir.Function, p Problem) *Solution {
+	s := &Solution{
+		In:  make(map[*ir.BasicBlock]Fact),
+		Out: make(map[*ir.BasicBlock]Fact),
+	}
+	if len(f.Blocks) == 0 {
+		return s
+	}
+	order := ReversePostorder(f)
+	reachable := make(map[*ir.BasicBlock]bool)
+	for _, block := range order {
+		reachable[block] = true
+	}
+	for _, block := range f.Blocks {
+		if !reachable[block] {
+			order = append(order, block)
+		}
+	}
+	forward := p.Direction() == Forward
+	if !forward {
+		for i, j := 0, len(order)-1; i < j; i, j = i+1, j-1 {
+			order[i], order[j] = order[j], order[i]
+		}
+	}
+	preds := Preds(f)
+	for _, block := range order {
+		s.In[block] = p.Initial()
+		s.Out[block] = p.Initial()
+	}
+	entry := f.Blocks[0]
+	// Worklist of basic blocks, in visitation order.
+	work := append([]*ir.BasicBlock(nil), order...)
+	inWork := make(map[*ir.BasicBlock]bool)
+	for _, block := range work {
+		inWork[block] = true
+	}
+	for len(work) > 0 {
+		block := work[0]
+		work = work[1:]
+		inWork[block] = false
+		if forward {
+			in := p.Initial()
+			if block == entry {
+				in = p.Boundary()
+			}
+			for _, pred := range preds[block] {
+				in = p.Meet(in, s.Out[pred])
+			}
+			s.In[block] = in
+			out := p.Transfer(block, in)
+			if p.Equal(out, s.Out[block]) {
+				continue
+			}
+			s.Out[block] = out
+			for _, succ := range Succs(block) {
+				if !inWork[succ] {
+					inWork[succ] = true
+					work = append(work, succ)
+				}
+			}
+		} else {
+			succs := Succs(block)
+			out := p.Initial()
+			if len(succs) == 0 {
+				out = p.Boundary()
+			}
+			for _, succ := range succs {
+				out = p.Meet(out, s.In[succ])
+			}
+			s.Out[block] = out
+			in := p.Transfer(block, out)
+			if p.Equal(in, s.In[block]) {
+				continue
+			}
+			s.In[block] = in
+			for _, pred := range preds[block] {
+				if !inWork[pred] {
+					inWork[pred] = true
+					work = append(work, pred)
+				}
+			}
+		}
+	}
+	return s
+}
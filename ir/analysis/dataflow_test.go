@@ -0,0 +1,134 @@
+package analysis
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestLiveness(t *testing.T) {
+	const input = `
+define i32 @f(i32 %n, i32 %k) {
+entry:
+	br label %loop
+
+loop:
+	%i = phi i32 [ 0, %entry ], [ %j, %body ]
+	%sum = phi i32 [ 0, %entry ], [ %s, %body ]
+	%c = icmp slt i32 %i, %n
+	br i1 %c, label %body, label %exit
+
+body:
+	%s = add i32 %sum, %k
+	%j = add i32 %i, 1
+	br label %loop
+
+exit:
+	ret i32 %sum
+}
+`
+	m, err := asm.ParseString("liveness.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	f := m.Funcs[0]
+	l := NewLiveness(f)
+	golden := []struct {
+		block   string
+		in, out string
+	}{
+		{block: "entry", in: "%n %k", out: "%n %k"},
+		{block: "loop", in: "%n %k", out: "%n %k %i %sum"},
+		{block: "body", in: "%n %k %i %sum", out: "%n %k %s %j"},
+		{block: "exit", in: "%sum", out: ""},
+	}
+	for _, g := range golden {
+		block := findBlock(t, f, g.block)
+		if got := idents(l.LiveIn(block)); got != g.in {
+			t.Errorf("%q: live-in mismatch; expected %q, got %q", g.block, g.in, got)
+		}
+		if got := idents(l.LiveOut(block)); got != g.out {
+			t.Errorf("%q: live-out mismatch; expected %q, got %q", g.block, g.out, got)
+		}
+	}
+	body := findBlock(t, f, "body")
+	if got, want := idents(l.LiveAt(body, 1)), "%n %k %i %s"; got != want {
+		t.Errorf("live values before %%j mismatch; expected %q, got %q", want, got)
+	}
+	if !l.IsLiveOut(f.Params[1], body) {
+		t.Errorf("expected %%k to be live-out of body")
+	}
+}
+
+func TestReachingStores(t *testing.T) {
+	const input = `
+define void @f(i1 %c, i32* %p, i32* %q) {
+entry:
+	store i32 0, i32* %p
+	store i32 1, i32* %q
+	br i1 %c, label %then, label %exit
+
+then:
+	store i32 2, i32* %p
+	store i32 3, i32* %p
+	br label %exit
+
+exit:
+	%x = load i32, i32* %p
+	ret void
+}
+`
+	m, err := asm.ParseString("reaching.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	f := m.Funcs[0]
+	r := NewReachingStores(f)
+	storeValues := func(stores []*ir.InstStore) string {
+		var ss []string
+		for _, store := range stores {
+			ss = append(ss, store.Src.Ident())
+		}
+		return strings.Join(ss, " ")
+	}
+	then := findBlock(t, f, "then")
+	if got, want := storeValues(r.In(then)), "0 1"; got != want {
+		t.Errorf("stores reaching entry of then mismatch; expected %q, got %q", want, got)
+	}
+	if got, want := storeValues(r.At(then, 1)), "1 2"; got != want {
+		t.Errorf("stores reaching second store of then mismatch; expected %q, got %q", want, got)
+	}
+	if got, want := storeValues(r.Out(then)), "1 3"; got != want {
+		t.Errorf("stores reaching exit of then mismatch; expected %q, got %q", want, got)
+	}
+	exit := findBlock(t, f, "exit")
+	if got, want := storeValues(r.In(exit)), "0 1 3"; got != want {
+		t.Errorf("stores reaching entry of exit mismatch; expected %q, got %q", want, got)
+	}
+	if got, want := storeValues(r.StoresTo(f.Params[1], exit, 0)), "0 3"; got != want {
+		t.Errorf("stores to %%p reaching load mismatch; expected %q, got %q", want, got)
+	}
+}
+
+// findBlock returns the basic block with the given name of the function.
+func findBlock(t *testing.T, f *ir.Function, name string) *ir.BasicBlock {
+	for _, block := range f.Blocks {
+		if block.Name() == name {
+			return block
+		}
+	}
+	t.Fatalf("unable to locate basic block %q", name)
+	return nil
+}
+
+// idents returns the space-separated identifiers of the given values.
+func idents(vs []value.Value) string {
+	var ss []string
+	for _, v := range vs {
+		ss = append(ss, v.Ident())
+	}
+	return strings.Join(ss, " ")
+}
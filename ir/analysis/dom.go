@@ -0,0 +1,201 @@
+// Package analysis provides control flow and data flow analyses of LLVM IR
+// functions.
+package analysis
+
+import (
+	"github.com/llir/llvm/ir"
+)
+
+// === [ Dominator tree ] ======================================================
+
+// DomTree is the dominator tree of a function. Basic blocks not reachable from
+// the entry basic block are not part of the dominator tree.
+type DomTree struct {
+	// Function.
+	f *ir.Function
+	// Entry basic block; nil if function declaration.
+	entry *ir.BasicBlock
+	// Immediate dominator of each reachable basic block; the entry basic block
+	// is its own immediate dominator.
+	idom map[*ir.BasicBlock]*ir.BasicBlock
+	// Children of each basic block in the dominator tree, in reverse postorder
+	// of the control flow graph.
+	children map[*ir.BasicBlock][]*ir.BasicBlock
+	// Preorder and postorder numbers of basic blocks in the dominator tree.
+	pre, post map[*ir.BasicBlock]int
+}
+
+// NewDomTree returns the dominator tree of the given function.
+//
+// The dominator tree is computed using the iterative algorithm of Cooper,
+// Harvey and Kennedy; "A Simple, Fast Dominance Algorithm".
+func NewDomTree(f *ir.Function) *DomTree {
+	t := &DomTree{
+		f:        f,
+		idom:     make(map[*ir.BasicBlock]*ir.BasicBlock),
+		children: make(map[*ir.BasicBlock][]*ir.BasicBlock),
+		pre:      make(map[*ir.BasicBlock]int),
+		post:     make(map[*ir.BasicBlock]int),
+	}
+	if len(f.Blocks) == 0 {
+		return t
+	}
+	t.entry = f.Blocks[0]
+	rpo := ReversePostorder(f)
+	index := make(map[*ir.BasicBlock]int)
+	for i, block := range rpo {
+		index[block] = i
+	}
+	preds := Preds(f)
+	intersect := func(a, b *ir.BasicBlock) *ir.BasicBlock {
+		for a != b {
+			for index[a] > index[b] {
+				a = t.idom[a]
+			}
+			for index[b] > index[a] {
+				b = t.idom[b]
+			}
+		}
+		return a
+	}
+	t.idom[t.entry] = t.entry
+	for changed := true; changed; {
+		changed = false
+		for _, block := range rpo[1:] {
+			var idom *ir.BasicBlock
+			for _, pred := range preds[block] {
+				if _, ok := t.idom[pred]; !ok {
+					// Skip unprocessed or unreachable predecessors.
+					continue
+				}
+				if idom == nil {
+					idom = pred
+					continue
+				}
+				idom = intersect(pred, idom)
+			}
+			if t.idom[block] != idom {
+				t.idom[block] = idom
+				changed = true
+			}
+		}
+	}
+	for _, block := range rpo[1:] {
+		idom := t.idom[block]
+		t.children[idom] = append(t.children[idom], block)
+	}
+	// Number basic blocks in preorder and postorder of the dominator tree, to
+	// answer dominance queries in constant time.
+	n := 0
+	var number func(block *ir.BasicBlock)
+	number = func(block *ir.BasicBlock) {
+		t.pre[block] = n
+		n++
+		for _, child := range t.children[block] {
+			number(child)
+		}
+		t.post[block] = n
+		n++
+	}
+	number(t.entry)
+	return t
+}
+
+// Entry returns the root of the dominator tree; i.e. the entry basic block of
+// the function, or nil if the function is a declaration.
+func (t *DomTree) Entry() *ir.BasicBlock {
+	return t.entry
+}
+
+// IDom returns the immediate dominator of the given basic block, or nil if
+// block is the entry basic block or not reachable.
+func (t *DomTree) IDom(block *ir.BasicBlock) *ir.BasicBlock {
+	if block == t.entry {
+		return nil
+	}
+	return t.idom[block]
+}
+
+// Children returns the basic blocks immediately dominated by the given basic
+// block.
+func (t *DomTree) Children(block *ir.BasicBlock) []*ir.BasicBlock {
+	return t.children[block]
+}
+
+// Reachable reports whether the given basic block is reachable from the entry
+// basic block.
+func (t *DomTree) Reachable(block *ir.BasicBlock) bool {
+	_, ok := t.idom[block]
+	return ok
+}
+
+// Dominates reports whether basic block a dominates basic block b. Every
+// reachable basic block dominates itself. Unreachable basic blocks neither
+// dominate nor are dominated by any basic block.
+func (t *DomTree) Dominates(a, b *ir.BasicBlock) bool {
+	if !t.Reachable(a) || !t.Reachable(b) {
+		return false
+	}
+	return t.pre[a] <= t.pre[b] && t.post[b] <= t.post[a]
+}
+
+// StrictlyDominates reports whether basic block a dominates basic block b, and
+// a is not b.
+func (t *DomTree) StrictlyDominates(a, b *ir.BasicBlock) bool {
+	return a != b && t.Dominates(a, b)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// Succs returns the successor basic blocks of the given basic block, or nil if
+// the basic block has no terminator.
+func Succs(block *ir.BasicBlock) []*ir.BasicBlock {
+	if block.Term == nil {
+		return nil
+	}
+	return block.Term.Succs()
+}
+
+// Preds returns the predecessor basic blocks of each basic block of the given
+// function. The predecessors of a basic block are ordered by the position of
+// the predecessor in the function, and each predecessor is included once.
+func Preds(f *ir.Function) map[*ir.BasicBlock][]*ir.BasicBlock {
+	preds := make(map[*ir.BasicBlock][]*ir.BasicBlock)
+	for _, block := range f.Blocks {
+		seen := make(map[*ir.BasicBlock]bool)
+		for _, succ := range Succs(block) {
+			if seen[succ] {
+				continue
+			}
+			seen[succ] = true
+			preds[succ] = append(preds[succ], block)
+		}
+	}
+	return preds
+}
+
+// ReversePostorder returns the basic blocks of the given function reachable
+// from the entry basic block, in reverse postorder of a depth-first traversal
+// of the control flow graph.
+func ReversePostorder(f *ir.Function) []*ir.BasicBlock {
+	if len(f.Blocks) == 0 {
+		return nil
+	}
+	var post []*ir.BasicBlock
+	visited := make(map[*ir.BasicBlock]bool)
+	var visit func(block *ir.BasicBlock)
+	visit = func(block *ir.BasicBlock) {
+		visited[block] = true
+		for _, succ := range Succs(block) {
+			if !visited[succ] {
+				visit(succ)
+			}
+		}
+		post = append(post, block)
+	}
+	visit(f.Blocks[0])
+	for i, j := 0, len(post)-1; i < j; i, j = i+1, j-1 {
+		post[i], post[j] = post[j], post[i]
+	}
+	return post
+}
@@ -0,0 +1,154 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/ir"
+	"github.com/pkg/errors"
+)
+
+// === [ Graphviz DOT export ] =================================================
+
+// CFGDot returns the control flow graph of the given function definition in
+// Graphviz DOT format. Each basic block is a node labelled by its name, and by
+// its instructions and terminator if insts is set. Edges of conditional
+// branches are labelled "true" and "false", edges of switch terminators by
+// their case comparand or "default", and edges of invoke terminators "normal"
+// and "unwind".
+func CFGDot(f *ir.Function, insts bool) (string, error) {
+	if err := f.AssignIDs(); err != nil {
+		return "", errors.WithStack(err)
+	}
+	buf := &strings.Builder{}
+	title := fmt.Sprintf("CFG for %s function", f.Ident())
+	fmt.Fprintf(buf, "digraph %s {\n", dotQuote(title))
+	fmt.Fprintf(buf, "\tlabel=%s\n", dotQuote(title))
+	buf.WriteString("\tnode [shape=box fontname=monospace]\n")
+	index := make(map[*ir.BasicBlock]int)
+	for i, block := range f.Blocks {
+		index[block] = i
+	}
+	for i, block := range f.Blocks {
+		label := dotQuote(block.Ident())
+		if insts {
+			label = blockLabel(block)
+		}
+		fmt.Fprintf(buf, "\tb%d [label=%s]\n", i, label)
+	}
+	for i, block := range f.Blocks {
+		for _, e := range cfgEdges(block.Term) {
+			fmt.Fprintf(buf, "\tb%d -> b%d", i, index[e.target])
+			if len(e.label) > 0 {
+				fmt.Fprintf(buf, " [label=%s]", dotQuote(e.label))
+			}
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String(), nil
+}
+
+// Dot returns the call graph in Graphviz DOT format. Each function is a node
+// labelled by its name; function declarations are drawn dashed. Calls from a
+// function to a potential callee are represented by a single edge, which is
+// drawn dashed if all such calls are indirect.
+func (cg *CallGraph) Dot() string {
+	buf := &strings.Builder{}
+	buf.WriteString("digraph \"Call graph\" {\n")
+	buf.WriteString("\tlabel=\"Call graph\"\n")
+	buf.WriteString("\tnode [shape=box fontname=monospace]\n")
+	index := make(map[*CallNode]int)
+	for i, node := range cg.Nodes {
+		index[node] = i
+		fmt.Fprintf(buf, "\tf%d [label=%s", i, dotQuote(node.Func.Ident()))
+		if len(node.Func.Blocks) == 0 {
+			buf.WriteString(" style=dashed")
+		}
+		buf.WriteString("]\n")
+	}
+	for i, node := range cg.Nodes {
+		// Callees in order of first call site, and whether all calls to the
+		// callee are indirect.
+		var callees []*CallNode
+		indirect := make(map[*CallNode]bool)
+		for _, e := range node.Callees {
+			if _, ok := indirect[e.Callee]; !ok {
+				callees = append(callees, e.Callee)
+				indirect[e.Callee] = e.Indirect
+				continue
+			}
+			indirect[e.Callee] = indirect[e.Callee] && e.Indirect
+		}
+		for _, callee := range callees {
+			fmt.Fprintf(buf, "\tf%d -> f%d", i, index[callee])
+			if indirect[callee] {
+				buf.WriteString(" [style=dashed]")
+			}
+			buf.WriteString("\n")
+		}
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+// cfgEdge is a control flow edge of a terminator.
+type cfgEdge struct {
+	// Target basic block.
+	target *ir.BasicBlock
+	// Edge label; or empty if not labelled.
+	label string
+}
+
+// cfgEdges returns the outgoing control flow edges of the given terminator, in
+// order of occurrence.
+func cfgEdges(term ir.Terminator) []cfgEdge {
+	switch term := term.(type) {
+	case *ir.TermCondBr:
+		return []cfgEdge{{target: term.TargetTrue, label: "true"}, {target: term.TargetFalse, label: "false"}}
+	case *ir.TermSwitch:
+		edges := []cfgEdge{{target: term.TargetDefault, label: "default"}}
+		for _, c := range term.Cases {
+			edges = append(edges, cfgEdge{target: c.Target, label: c.X.Ident()})
+		}
+		return edges
+	case *ir.TermInvoke:
+		return []cfgEdge{{target: term.Normal, label: "normal"}, {target: term.Exception, label: "unwind"}}
+	}
+	var edges []cfgEdge
+	for _, succ := range term.Succs() {
+		edges = append(edges, cfgEdge{target: succ})
+	}
+	return edges
+}
+
+// blockLabel returns the DOT label of the given basic block, listing its name,
+// instructions and terminator as left-aligned lines.
+func blockLabel(block *ir.BasicBlock) string {
+	lines := []string{block.Ident() + ":"}
+	for _, inst := range block.Insts {
+		lines = append(lines, "  "+inst.Def())
+	}
+	lines = append(lines, "  "+block.Term.Def())
+	buf := &strings.Builder{}
+	buf.WriteString(`"`)
+	for _, line := range lines {
+		buf.WriteString(dotEscape(line))
+		buf.WriteString(`\l`)
+	}
+	buf.WriteString(`"`)
+	return buf.String()
+}
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	return `"` + dotEscape(s) + `"`
+}
+
+// dotEscape escapes backslashes, quotes and line breaks of s for use within a
+// quoted DOT string.
+func dotEscape(s string) string {
+	s = strings.Replace(s, `\`, `\\`, -1)
+	s = strings.Replace(s, `"`, `\"`, -1)
+	return strings.Replace(s, "\n", `\l`, -1)
+}
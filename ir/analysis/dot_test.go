@@ -0,0 +1,86 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestCFGDot(t *testing.T) {
+	const input = `
+define i32 @f(i32 %x, i1 %c) {
+entry:
+	br i1 %c, label %sw, label %exit
+
+sw:
+	switch i32 %x, label %exit [
+		i32 1, label %one
+	]
+
+one:
+	br label %exit
+
+exit:
+	%s = phi i32 [ 0, %entry ], [ 1, %sw ], [ 2, %one ]
+	ret i32 %s
+}
+
+define void @g() {
+	call void @h()
+	call void @h()
+	ret void
+}
+
+declare void @h()
+`
+	m, err := asm.ParseString("<input>", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	const wantCFG = `digraph "CFG for @f function" {
+	label="CFG for @f function"
+	node [shape=box fontname=monospace]
+	b0 [label="%entry"]
+	b1 [label="%sw"]
+	b2 [label="%one"]
+	b3 [label="%exit"]
+	b0 -> b1 [label="true"]
+	b0 -> b3 [label="false"]
+	b1 -> b3 [label="default"]
+	b1 -> b2 [label="1"]
+	b2 -> b3
+}
+`
+	got, err := CFGDot(m.Funcs[0], false)
+	if err != nil {
+		t.Fatalf("unable to export control flow graph; %v", err)
+	}
+	if got != wantCFG {
+		t.Errorf("control flow graph mismatch; expected:\n%s\ngot:\n%s", wantCFG, got)
+	}
+	const wantInsts = `digraph "CFG for @g function" {
+	label="CFG for @g function"
+	node [shape=box fontname=monospace]
+	b0 [label="%0:\l  call void @h()\l  call void @h()\l  ret void\l"]
+}
+`
+	got, err = CFGDot(m.Funcs[1], true)
+	if err != nil {
+		t.Fatalf("unable to export control flow graph; %v", err)
+	}
+	if got != wantInsts {
+		t.Errorf("control flow graph mismatch; expected:\n%s\ngot:\n%s", wantInsts, got)
+	}
+	const wantCallGraph = `digraph "Call graph" {
+	label="Call graph"
+	node [shape=box fontname=monospace]
+	f0 [label="@f"]
+	f1 [label="@g"]
+	f2 [label="@h" style=dashed]
+	f1 -> f2
+}
+`
+	if got := NewCallGraph(m).Dot(); got != wantCallGraph {
+		t.Errorf("call graph mismatch; expected:\n%s\ngot:\n%s", wantCallGraph, got)
+	}
+}
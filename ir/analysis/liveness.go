@@ -0,0 +1,227 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Liveness ] ============================================================
+
+// ValueSet is a set of values.
+type ValueSet map[value.Value]bool
+
+// Liveness is the liveness of the SSA values (i.e. function parameters and
+// instruction results) of a function.
+//
+// The operands of a phi instruction are used at the exit of the corresponding
+// incoming basic block, and the result of a phi instruction is defined at the
+// entry of its basic block; thus, neither is live at the entry of the basic
+// block of the phi instruction. Metadata arguments (e.g. of calls to
+// llvm.dbg.value) are not considered uses.
+type Liveness struct {
+	// Data flow solution; live values at the entry and exit of each basic
+	// block, excluding the operands of phi instructions.
+	sol *Solution
+	// Operands of phi instructions used at the exit of each basic block.
+	phiUses map[*ir.BasicBlock]ValueSet
+	// Position of each SSA value; for deterministic ordering.
+	pos map[value.Value]int
+}
+
+// NewLiveness returns the liveness of the SSA values of the given function.
+func NewLiveness(f *ir.Function) *Liveness {
+	l := &Liveness{
+		phiUses: make(map[*ir.BasicBlock]ValueSet),
+		pos:     make(map[value.Value]int),
+	}
+	for _, param := range f.Params {
+		l.pos[param] = len(l.pos)
+	}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if v, ok := inst.(value.Value); ok {
+				l.pos[v] = len(l.pos)
+			}
+			phi, ok := inst.(*ir.InstPhi)
+			if !ok {
+				continue
+			}
+			for _, inc := range phi.Incs {
+				if !isSSAValue(inc.X) {
+					continue
+				}
+				uses := l.phiUses[inc.Pred]
+				if uses == nil {
+					uses = make(ValueSet)
+					l.phiUses[inc.Pred] = uses
+				}
+				uses[inc.X] = true
+			}
+		}
+		if v, ok := block.Term.(value.Value); ok {
+			l.pos[v] = len(l.pos)
+		}
+	}
+	l.sol = Solve(f, &liveness{phiUses: l.phiUses})
+	return l
+}
+
+// LiveIn returns the values live at the entry of the given basic block, in
+// order of definition.
+func (l *Liveness) LiveIn(block *ir.BasicBlock) []value.Value {
+	live, _ := l.sol.In[block].(ValueSet)
+	return l.sorted(live)
+}
+
+// LiveOut returns the values live at the exit of the given basic block, in
+// order of definition.
+func (l *Liveness) LiveOut(block *ir.BasicBlock) []value.Value {
+	return l.sorted(l.liveOut(block))
+}
+
+// LiveAt returns the values live immediately before the instruction at the
+// given index of the basic block, in order of definition. The index
+// len(block.Insts) denotes the terminator of the basic block.
+//
+// The number of values live at each instruction may be used to estimate
+// register pressure.
+func (l *Liveness) LiveAt(block *ir.BasicBlock, index int) []value.Value {
+	live := l.liveOut(block)
+	if block.Term != nil {
+		live = transferInst(live, block.Term)
+	}
+	for i := len(block.Insts) - 1; i >= index; i-- {
+		live = transferInst(live, block.Insts[i])
+	}
+	return l.sorted(live)
+}
+
+// IsLiveOut reports whether the given value is live at the exit of the basic
+// block.
+func (l *Liveness) IsLiveOut(v value.Value, block *ir.BasicBlock) bool {
+	return l.liveOut(block)[v]
+}
+
+// liveOut returns the set of values live at the exit of the given basic block.
+func (l *Liveness) liveOut(block *ir.BasicBlock) ValueSet {
+	out, _ := l.sol.Out[block].(ValueSet)
+	live := make(ValueSet)
+	for v := range out {
+		live[v] = true
+	}
+	for v := range l.phiUses[block] {
+		live[v] = true
+	}
+	return live
+}
+
+// sorted returns the values of the given set, in order of definition.
+func (l *Liveness) sorted(set ValueSet) []value.Value {
+	var vs []value.Value
+	for v := range set {
+		vs = append(vs, v)
+	}
+	sort.Slice(vs, func(i, j int) bool {
+		return l.pos[vs[i]] < l.pos[vs[j]]
+	})
+	return vs
+}
+
+// liveness is the backward data flow problem of live SSA values.
+type liveness struct {
+	// Operands of phi instructions used at the exit of each basic block.
+	phiUses map[*ir.BasicBlock]ValueSet
+}
+
+// Direction returns the direction of the data flow problem.
+func (*liveness) Direction() Direction {
+	return Backward
+}
+
+// Boundary returns the fact at the exit of exit basic blocks.
+func (*liveness) Boundary() Fact {
+	return ValueSet{}
+}
+
+// Initial returns the initial fact of each basic block.
+func (*liveness) Initial() Fact {
+	return ValueSet{}
+}
+
+// Meet returns the union of the given sets of live values.
+func (*liveness) Meet(a, b Fact) Fact {
+	return unionValues(a.(ValueSet), b.(ValueSet))
+}
+
+// Equal reports whether the given sets of live values are equal.
+func (*liveness) Equal(a, b Fact) bool {
+	x, y := a.(ValueSet), b.(ValueSet)
+	if len(x) != len(y) {
+		return false
+	}
+	for v := range x {
+		if !y[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// Transfer returns the values live at the entry of the given basic block based
+// on the values live at its exit.
+func (p *liveness) Transfer(block *ir.BasicBlock, fact Fact) Fact {
+	live := unionValues(fact.(ValueSet), p.phiUses[block])
+	if block.Term != nil {
+		live = transferInst(live, block.Term)
+	}
+	for i := len(block.Insts) - 1; i >= 0; i-- {
+		live = transferInst(live, block.Insts[i])
+	}
+	return live
+}
+
+// ### [ Helper functions ] ####################################################
+
+// transferInst updates the given set of live values to account for the
+// definition and uses of the given instruction or terminator, and returns the
+// values live immediately before it. The operands of phi instructions are not
+// considered uses.
+func transferInst(live ValueSet, inst interface{}) ValueSet {
+	if v, ok := inst.(value.Value); ok {
+		delete(live, v)
+	}
+	if _, ok := inst.(*ir.InstPhi); ok {
+		return live
+	}
+	for _, op := range irutil.Operands(inst) {
+		if isSSAValue(op) {
+			live[op] = true
+		}
+	}
+	return live
+}
+
+// isSSAValue reports whether the given value is an SSA value; i.e. a function
+// parameter or the result of an instruction or terminator.
+func isSSAValue(v value.Value) bool {
+	switch v.(type) {
+	case *ir.Param, ir.Instruction, ir.Terminator:
+		return true
+	}
+	return false
+}
+
+// unionValues returns the union of the given sets of values.
+func unionValues(a, b ValueSet) ValueSet {
+	set := make(ValueSet, len(a)+len(b))
+	for v := range a {
+		set[v] = true
+	}
+	for v := range b {
+		set[v] = true
+	}
+	return set
+}
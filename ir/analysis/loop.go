@@ -0,0 +1,339 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/llir/llvm/ir"
+)
+
+// === [ Loops ] ===============================================================
+
+// Loop is a natural loop; i.e. a set of basic blocks with a single entry (the
+// loop header), which dominates all basic blocks of the loop, and at least one
+// back edge to the loop header.
+type Loop struct {
+	// Loop header; the single entry of the loop.
+	Header *ir.BasicBlock
+	// Basic blocks of the loop (including the basic blocks of nested loops), in
+	// order of occurrence in the function.
+	Blocks []*ir.BasicBlock
+	// Latches of the loop; i.e. basic blocks of the loop with a back edge to the
+	// loop header.
+	Latches []*ir.BasicBlock
+	// Exiting basic blocks of the loop; i.e. basic blocks of the loop with a
+	// successor outside of the loop.
+	Exiting []*ir.BasicBlock
+	// Exit basic blocks of the loop; i.e. basic blocks outside of the loop with
+	// a predecessor inside of the loop.
+	Exits []*ir.BasicBlock
+	// Preheader of the loop; i.e. the single predecessor of the loop header
+	// outside of the loop, provided that the loop header is its only successor;
+	// or nil if not present.
+	Preheader *ir.BasicBlock
+	// Parent loop; or nil if outermost loop.
+	Parent *Loop
+	// Loops immediately nested within the loop.
+	Children []*Loop
+	// Nesting depth of the loop; 1 for outermost loops.
+	Depth int
+
+	// Basic blocks of the loop.
+	blocks map[*ir.BasicBlock]bool
+}
+
+// Contains reports whether the given basic block is part of the loop (or one of
+// its nested loops).
+func (l *Loop) Contains(block *ir.BasicBlock) bool {
+	return l.blocks[block]
+}
+
+// LoopInfo is the loop nesting forest of a function.
+type LoopInfo struct {
+	// Outermost loops, in order of their loop headers in the function.
+	Loops []*Loop
+	// Irreducible regions of the function; i.e. cycles of the control flow
+	// graph with multiple entries, which are therefore not natural loops. The
+	// basic blocks of each region are in order of occurrence in the function.
+	Irreducible [][]*ir.BasicBlock
+
+	// Innermost loop of each basic block.
+	loopOf map[*ir.BasicBlock]*Loop
+}
+
+// NewLoopInfo returns the loops of the given function, as identified by back
+// edges in the dominator tree of the function.
+func NewLoopInfo(f *ir.Function, dom *DomTree) *LoopInfo {
+	li := &LoopInfo{loopOf: make(map[*ir.BasicBlock]*Loop)}
+	preds := Preds(f)
+	index := make(map[*ir.BasicBlock]int)
+	for i, block := range f.Blocks {
+		index[block] = i
+	}
+	// Identify natural loops; loops with the same header are merged.
+	var loops []*Loop
+	for _, header := range f.Blocks {
+		var latches []*ir.BasicBlock
+		for _, pred := range preds[header] {
+			if dom.Dominates(header, pred) {
+				latches = append(latches, pred)
+			}
+		}
+		if len(latches) == 0 {
+			continue
+		}
+		l := &Loop{
+			Header:  header,
+			Latches: latches,
+			blocks:  map[*ir.BasicBlock]bool{header: true},
+		}
+		// Walk backwards from the latches to the header.
+		work := append([]*ir.BasicBlock(nil), latches...)
+		for len(work) > 0 {
+			block := work[len(work)-1]
+			work = work[:len(work)-1]
+			if l.blocks[block] || !dom.Reachable(block) {
+				continue
+			}
+			l.blocks[block] = true
+			work = append(work, preds[block]...)
+		}
+		loops = append(loops, l)
+	}
+	// Determine loop nesting; the parent of a loop is the smallest other loop
+	// containing its header.
+	sort.SliceStable(loops, func(i, j int) bool {
+		return len(loops[i].blocks) > len(loops[j].blocks)
+	})
+	for i, l := range loops {
+		for j := i - 1; j >= 0; j-- {
+			if loops[j].Contains(l.Header) {
+				l.Parent = loops[j]
+				break
+			}
+		}
+	}
+	sort.SliceStable(loops, func(i, j int) bool {
+		return index[loops[i].Header] < index[loops[j].Header]
+	})
+	for _, l := range loops {
+		if l.Parent == nil {
+			li.Loops = append(li.Loops, l)
+		} else {
+			l.Parent.Children = append(l.Parent.Children, l)
+		}
+	}
+	// Compute loop properties, visiting outer loops before inner loops so that
+	// each basic block is mapped to its innermost loop.
+	var visit func(l *Loop, depth int)
+	visit = func(l *Loop, depth int) {
+		l.Depth = depth
+		for _, block := range f.Blocks {
+			if !l.blocks[block] {
+				continue
+			}
+			l.Blocks = append(l.Blocks, block)
+			li.loopOf[block] = l
+			exiting := false
+			for _, succ := range Succs(block) {
+				if l.blocks[succ] {
+					continue
+				}
+				exiting = true
+				if !containsBlock(l.Exits, succ) {
+					l.Exits = append(l.Exits, succ)
+				}
+			}
+			if exiting {
+				l.Exiting = append(l.Exiting, block)
+			}
+		}
+		var outside []*ir.BasicBlock
+		for _, pred := range preds[l.Header] {
+			if !l.blocks[pred] {
+				outside = append(outside, pred)
+			}
+		}
+		if len(outside) == 1 && len(Succs(outside[0])) == 1 {
+			l.Preheader = outside[0]
+		}
+		for _, child := range l.Children {
+			visit(child, depth+1)
+		}
+	}
+	for _, l := range li.Loops {
+		visit(l, 1)
+	}
+	li.Irreducible = irreducibleRegions(f, dom, preds, index)
+	return li
+}
+
+// LoopFor returns the innermost loop containing the given basic block, or nil
+// if the basic block is not part of any loop.
+func (li *LoopInfo) LoopFor(block *ir.BasicBlock) *Loop {
+	return li.loopOf[block]
+}
+
+// Depth returns the loop nesting depth of the given basic block; 0 if the
+// basic block is not part of any loop.
+func (li *LoopInfo) Depth(block *ir.BasicBlock) int {
+	if l := li.loopOf[block]; l != nil {
+		return l.Depth
+	}
+	return 0
+}
+
+// AllLoops returns all loops of the function in preorder of the loop nesting
+// forest; i.e. each loop precedes its nested loops.
+func (li *LoopInfo) AllLoops() []*Loop {
+	var loops []*Loop
+	var visit func(l *Loop)
+	visit = func(l *Loop) {
+		loops = append(loops, l)
+		for _, child := range l.Children {
+			visit(child)
+		}
+	}
+	for _, l := range li.Loops {
+		visit(l)
+	}
+	return loops
+}
+
+// IsReducible reports whether the control flow graph of the function is
+// reducible; i.e. whether every cycle is part of a natural loop.
+func (li *LoopInfo) IsReducible() bool {
+	return len(li.Irreducible) == 0
+}
+
+// ### [ Helper functions ] ####################################################
+
+// irreducibleRegions returns the irreducible regions of the given function.
+//
+// Each strongly connected component of the reachable control flow graph with
+// a single entry is a natural loop; its nested cycles are found by removing the
+// entry and recursing. A strongly connected component with multiple entries is
+// an irreducible region; its nested cycles are found by removing all entries
+// and recursing.
+func irreducibleRegions(f *ir.Function, dom *DomTree, preds map[*ir.BasicBlock][]*ir.BasicBlock, index map[*ir.BasicBlock]int) [][]*ir.BasicBlock {
+	var regions [][]*ir.BasicBlock
+	var visit func(blocks map[*ir.BasicBlock]bool)
+	visit = func(blocks map[*ir.BasicBlock]bool) {
+		for _, scc := range sccs(f, blocks) {
+			inSCC := make(map[*ir.BasicBlock]bool)
+			for _, block := range scc {
+				inSCC[block] = true
+			}
+			// Locate entries of the strongly connected component.
+			var entries []*ir.BasicBlock
+			for _, block := range scc {
+				if block == dom.Entry() {
+					entries = append(entries, block)
+					continue
+				}
+				for _, pred := range preds[block] {
+					if dom.Reachable(pred) && !inSCC[pred] {
+						entries = append(entries, block)
+						break
+					}
+				}
+			}
+			if len(entries) > 1 {
+				region := append([]*ir.BasicBlock(nil), scc...)
+				sort.Slice(region, func(i, j int) bool {
+					return index[region[i]] < index[region[j]]
+				})
+				regions = append(regions, region)
+			}
+			inner := make(map[*ir.BasicBlock]bool)
+			for _, block := range scc {
+				if !containsBlock(entries, block) {
+					inner[block] = true
+				}
+			}
+			visit(inner)
+		}
+	}
+	blocks := make(map[*ir.BasicBlock]bool)
+	for _, block := range f.Blocks {
+		if dom.Reachable(block) {
+			blocks[block] = true
+		}
+	}
+	visit(blocks)
+	return regions
+}
+
+// sccs returns the cyclic strongly connected components of the subgraph of the
+// control flow graph of the given function induced by the given basic blocks;
+// i.e. strongly connected components with more than one basic block, or with a
+// basic block which is its own successor.
+//
+// The strongly connected components are computed using Tarjan's algorithm.
+func sccs(f *ir.Function, blocks map[*ir.BasicBlock]bool) [][]*ir.BasicBlock {
+	var (
+		result  [][]*ir.BasicBlock
+		stack   []*ir.BasicBlock
+		onStack = make(map[*ir.BasicBlock]bool)
+		index   = make(map[*ir.BasicBlock]int)
+		lowlink = make(map[*ir.BasicBlock]int)
+		n       = 0
+	)
+	var connect func(block *ir.BasicBlock)
+	connect = func(block *ir.BasicBlock) {
+		index[block] = n
+		lowlink[block] = n
+		n++
+		stack = append(stack, block)
+		onStack[block] = true
+		selfLoop := false
+		for _, succ := range Succs(block) {
+			if !blocks[succ] {
+				continue
+			}
+			if succ == block {
+				selfLoop = true
+			}
+			if _, ok := index[succ]; !ok {
+				connect(succ)
+				if lowlink[succ] < lowlink[block] {
+					lowlink[block] = lowlink[succ]
+				}
+			} else if onStack[succ] && index[succ] < lowlink[block] {
+				lowlink[block] = index[succ]
+			}
+		}
+		if lowlink[block] != index[block] {
+			return
+		}
+		var scc []*ir.BasicBlock
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top] = false
+			scc = append(scc, top)
+			if top == block {
+				break
+			}
+		}
+		if len(scc) > 1 || selfLoop {
+			result = append(result, scc)
+		}
+	}
+	for _, block := range f.Blocks {
+		if _, ok := index[block]; !ok && blocks[block] {
+			connect(block)
+		}
+	}
+	return result
+}
+
+// containsBlock reports whether the given basic block is in the slice of basic
+// blocks.
+func containsBlock(blocks []*ir.BasicBlock, block *ir.BasicBlock) bool {
+	for _, b := range blocks {
+		if b == block {
+			return true
+		}
+	}
+	return false
+}
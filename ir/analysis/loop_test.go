@@ -0,0 +1,112 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+)
+
+func TestLoopInfo(t *testing.T) {
+	const input = `
+define void @nested(i1 %c) {
+entry:
+	br label %outer
+
+outer:
+	br label %inner
+
+inner:
+	br i1 %c, label %inner, label %outer.latch
+
+outer.latch:
+	br i1 %c, label %outer, label %exit
+
+exit:
+	ret void
+}
+
+define void @irreducible(i1 %c) {
+entry:
+	br i1 %c, label %a, label %b
+
+a:
+	br i1 %c, label %b, label %exit
+
+b:
+	br label %a
+
+exit:
+	ret void
+}
+`
+	m, err := asm.ParseString("loop.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+
+	// Test nested loops.
+	f := m.Funcs[0]
+	entry, outer, inner, latch, exit := f.Blocks[0], f.Blocks[1], f.Blocks[2], f.Blocks[3], f.Blocks[4]
+	dom := NewDomTree(f)
+	if got := dom.IDom(exit); got != latch {
+		t.Errorf("immediate dominator mismatch of %q; expected %q, got %v", exit.Name(), latch.Name(), got)
+	}
+	if !dom.Dominates(outer, latch) || dom.Dominates(latch, inner) {
+		t.Errorf("dominance mismatch")
+	}
+	li := NewLoopInfo(f, dom)
+	if !li.IsReducible() {
+		t.Errorf("expected reducible control flow graph; got irreducible regions %v", li.Irreducible)
+	}
+	if len(li.Loops) != 1 {
+		t.Fatalf("number of outermost loops mismatch; expected 1, got %d", len(li.Loops))
+	}
+	o := li.Loops[0]
+	checkBlocks(t, "outer loop blocks", o.Blocks, outer, inner, latch)
+	checkBlocks(t, "outer loop latches", o.Latches, latch)
+	checkBlocks(t, "outer loop exiting", o.Exiting, latch)
+	checkBlocks(t, "outer loop exits", o.Exits, exit)
+	if o.Header != outer || o.Preheader != entry || o.Depth != 1 {
+		t.Errorf("outer loop mismatch; header %v, preheader %v, depth %d", o.Header, o.Preheader, o.Depth)
+	}
+	if len(o.Children) != 1 {
+		t.Fatalf("number of nested loops mismatch; expected 1, got %d", len(o.Children))
+	}
+	i := o.Children[0]
+	checkBlocks(t, "inner loop blocks", i.Blocks, inner)
+	checkBlocks(t, "inner loop exits", i.Exits, latch)
+	if i.Header != inner || i.Preheader != outer || i.Depth != 2 || i.Parent != o {
+		t.Errorf("inner loop mismatch; header %v, preheader %v, depth %d", i.Header, i.Preheader, i.Depth)
+	}
+	if li.LoopFor(inner) != i || li.Depth(latch) != 1 || li.Depth(exit) != 0 {
+		t.Errorf("loop membership mismatch")
+	}
+
+	// Test irreducible control flow.
+	f = m.Funcs[1]
+	a, b := f.Blocks[1], f.Blocks[2]
+	li = NewLoopInfo(f, NewDomTree(f))
+	if len(li.Loops) != 0 {
+		t.Errorf("number of loops mismatch; expected 0, got %d", len(li.Loops))
+	}
+	if len(li.Irreducible) != 1 {
+		t.Fatalf("number of irreducible regions mismatch; expected 1, got %d", len(li.Irreducible))
+	}
+	checkBlocks(t, "irreducible region", li.Irreducible[0], a, b)
+}
+
+// checkBlocks reports an error if the given basic blocks differ from the
+// expected basic blocks.
+func checkBlocks(t *testing.T, desc string, got []*ir.BasicBlock, want ...*ir.BasicBlock) {
+	if len(got) != len(want) {
+		t.Errorf("%s mismatch; expected %v, got %v", desc, want, got)
+		return
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("%s mismatch; expected %v, got %v", desc, want, got)
+			return
+		}
+	}
+}
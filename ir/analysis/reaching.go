@@ -0,0 +1,166 @@
+package analysis
+
+import (
+	"sort"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Reaching definitions ] ================================================
+
+// StoreSet is a set of store instructions.
+type StoreSet map[*ir.InstStore]bool
+
+// ReachingStores is the reaching definitions of the memory stores of a
+// function; i.e. for each program point, the store instructions which may have
+// written the value of a memory location at that point.
+//
+// A store kills the stores to the same destination pointer value (which must
+// alias). Stores to other pointer values are never killed, as they may alias.
+type ReachingStores struct {
+	// Data flow solution; stores reaching the entry and exit of each basic
+	// block.
+	sol *Solution
+	// Position of each store instruction; for deterministic ordering.
+	pos map[*ir.InstStore]int
+}
+
+// NewReachingStores returns the reaching definitions of the memory stores of
+// the given function.
+func NewReachingStores(f *ir.Function) *ReachingStores {
+	r := &ReachingStores{pos: make(map[*ir.InstStore]int)}
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			if store, ok := inst.(*ir.InstStore); ok {
+				r.pos[store] = len(r.pos)
+			}
+		}
+	}
+	r.sol = Solve(f, reachingStores{})
+	return r
+}
+
+// In returns the stores reaching the entry of the given basic block, in order
+// of occurrence in the function.
+func (r *ReachingStores) In(block *ir.BasicBlock) []*ir.InstStore {
+	in, _ := r.sol.In[block].(StoreSet)
+	return r.sorted(in)
+}
+
+// Out returns the stores reaching the exit of the given basic block, in order
+// of occurrence in the function.
+func (r *ReachingStores) Out(block *ir.BasicBlock) []*ir.InstStore {
+	out, _ := r.sol.Out[block].(StoreSet)
+	return r.sorted(out)
+}
+
+// At returns the stores reaching the instruction at the given index of the
+// basic block, in order of occurrence in the function. The index
+// len(block.Insts) denotes the terminator of the basic block.
+func (r *ReachingStores) At(block *ir.BasicBlock, index int) []*ir.InstStore {
+	in, _ := r.sol.In[block].(StoreSet)
+	return r.sorted(transferStores(in, block.Insts[:index]))
+}
+
+// StoresTo returns the stores to the given destination pointer value reaching
+// the instruction at the given index of the basic block, in order of
+// occurrence in the function. The index len(block.Insts) denotes the
+// terminator of the basic block.
+func (r *ReachingStores) StoresTo(dst value.Value, block *ir.BasicBlock, index int) []*ir.InstStore {
+	var stores []*ir.InstStore
+	for _, store := range r.At(block, index) {
+		if store.Dst == dst {
+			stores = append(stores, store)
+		}
+	}
+	return stores
+}
+
+// sorted returns the store instructions of the given set, in order of
+// occurrence in the function.
+func (r *ReachingStores) sorted(set StoreSet) []*ir.InstStore {
+	var stores []*ir.InstStore
+	for store := range set {
+		stores = append(stores, store)
+	}
+	sort.Slice(stores, func(i, j int) bool {
+		return r.pos[stores[i]] < r.pos[stores[j]]
+	})
+	return stores
+}
+
+// reachingStores is the forward data flow problem of reaching stores.
+type reachingStores struct{}
+
+// Direction returns the direction of the data flow problem.
+func (reachingStores) Direction() Direction {
+	return Forward
+}
+
+// Boundary returns the fact at the entry of the entry basic block.
+func (reachingStores) Boundary() Fact {
+	return StoreSet{}
+}
+
+// Initial returns the initial fact of each basic block.
+func (reachingStores) Initial() Fact {
+	return StoreSet{}
+}
+
+// Meet returns the union of the given sets of reaching stores.
+func (reachingStores) Meet(a, b Fact) Fact {
+	x, y := a.(StoreSet), b.(StoreSet)
+	set := make(StoreSet, len(x)+len(y))
+	for store := range x {
+		set[store] = true
+	}
+	for store := range y {
+		set[store] = true
+	}
+	return set
+}
+
+// Equal reports whether the given sets of reaching stores are equal.
+func (reachingStores) Equal(a, b Fact) bool {
+	x, y := a.(StoreSet), b.(StoreSet)
+	if len(x) != len(y) {
+		return false
+	}
+	for store := range x {
+		if !y[store] {
+			return false
+		}
+	}
+	return true
+}
+
+// Transfer returns the stores reaching the exit of the given basic block based
+// on the stores reaching its entry.
+func (reachingStores) Transfer(block *ir.BasicBlock, fact Fact) Fact {
+	return transferStores(fact.(StoreSet), block.Insts)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// transferStores returns the stores reaching the point after the given
+// instructions based on the given stores reaching the point before them.
+func transferStores(in StoreSet, insts []ir.Instruction) StoreSet {
+	out := make(StoreSet, len(in))
+	for store := range in {
+		out[store] = true
+	}
+	for _, inst := range insts {
+		store, ok := inst.(*ir.InstStore)
+		if !ok {
+			continue
+		}
+		for prev := range out {
+			if prev.Dst == store.Dst {
+				delete(out, prev)
+			}
+		}
+		out[store] = true
+	}
+	return out
+}
@@ -0,0 +1,870 @@
+package analysis
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Control flow structuring ] ============================================
+
+// Region is a high-level control flow region of a function, as recovered by
+// Structure.
+//
+// A Region has one of the following underlying types.
+//
+//    *analysis.BlockRegion
+//    *analysis.SeqRegion
+//    *analysis.IfRegion
+//    *analysis.SwitchRegion
+//    *analysis.LoopRegion
+//    *analysis.BreakRegion
+//    *analysis.ContinueRegion
+//    *analysis.GuardRegion
+//    *analysis.SkipRegion
+//    *analysis.GotoRegion
+//
+// A nil Region denotes an empty region.
+type Region interface {
+	// String returns a C-like pseudo-code representation of the region.
+	fmt.Stringer
+	// isRegion ensures that only control flow regions can be assigned to the
+	// analysis.Region interface.
+	isRegion()
+}
+
+// BlockRegion is the straight-line code of a basic block; i.e. its
+// instructions. The terminator of the basic block is part of the region if it
+// does not transfer control to another basic block (e.g. ret or unreachable),
+// or if it transfers control in a way not expressible by structured regions
+// (e.g. invoke or indirectbr); the targets of such terminators are labeled.
+// Otherwise (br, conditional br and switch), the control transfer of the
+// terminator is represented by the enclosing regions.
+type BlockRegion struct {
+	// Basic block.
+	Block *ir.BasicBlock
+	// Terminator of the basic block is part of the region.
+	Term bool
+	// Basic block is the target of a goto region or unstructured terminator,
+	// and must therefore be labeled.
+	Labeled bool
+}
+
+// SeqRegion is a sequence of regions executed in order.
+type SeqRegion struct {
+	// Regions of the sequence.
+	Regions []Region
+}
+
+// IfRegion is an if-then or if-then-else region.
+type IfRegion struct {
+	// Branching condition.
+	Cond value.Value
+	// Condition is negated; i.e. the then region is executed if Cond is false.
+	Negated bool
+	// Region executed if the condition holds.
+	Then Region
+	// Region executed if the condition does not hold; nil if not present.
+	Else Region
+}
+
+// SwitchRegion is a switch region. Control does not fall through from one case
+// to the next.
+type SwitchRegion struct {
+	// Switch terminator; specifies the control variable.
+	Term *ir.TermSwitch
+	// Switch cases, in order of first occurrence of each case target; cases
+	// sharing a target are merged, and cases targeting the default target are
+	// omitted.
+	Cases []*SwitchCase
+	// Default region.
+	Default Region
+}
+
+// SwitchCase is a case of a switch region.
+type SwitchCase struct {
+	// Case comparands.
+	Values []constant.Constant
+	// Case region.
+	Body Region
+}
+
+// LoopKind specifies the kind of a loop region.
+type LoopKind uint8
+
+// Loop kinds.
+const (
+	// Endless loop; exited through break, goto or return regions.
+	//
+	//    for { Body }
+	LoopEndless LoopKind = iota
+	// Pre-tested loop; the instructions of the loop header are evaluated
+	// before testing the condition of each iteration.
+	//
+	//    for { Header; if !Cond { break }; Body }
+	LoopPreTested
+	// Post-tested loop.
+	//
+	//    for { Body; if !Cond { break } }
+	LoopPostTested
+)
+
+// LoopRegion is a loop region.
+type LoopRegion struct {
+	// Kind of loop.
+	Kind LoopKind
+	// Loop header.
+	Header *ir.BasicBlock
+	// Loop condition of pre-tested and post-tested loops; the loop continues
+	// while the condition holds.
+	Cond value.Value
+	// Loop condition is negated; i.e. the loop continues while Cond is false.
+	Negated bool
+	// Loop body. The loop header is part of the body unless the loop is
+	// pre-tested.
+	Body Region
+	// Loop is the target of a labeled break or continue region.
+	Labeled bool
+
+	// Block region of the loop header of pre-tested loops.
+	head *BlockRegion
+}
+
+// BreakRegion exits a loop, continuing execution after the loop.
+type BreakRegion struct {
+	// Loop to exit.
+	Loop *LoopRegion
+	// Break is labeled, as it exits a loop or switch nested within Loop.
+	Labeled bool
+}
+
+// ContinueRegion continues with the next iteration of a loop.
+type ContinueRegion struct {
+	// Loop to continue.
+	Loop *LoopRegion
+	// Continue is labeled, as it continues a loop other than the innermost
+	// enclosing loop.
+	Labeled bool
+}
+
+// GuardRegion is a region skipped if its guard is set; i.e. if control is
+// being transferred past the region by a preceding skip region. The guard is
+// cleared on reaching the guard region.
+type GuardRegion struct {
+	// Basic block of the region; names the guard.
+	Block *ir.BasicBlock
+	// Guarded region.
+	Body Region
+}
+
+// SkipRegion sets the guards of guard regions, transferring control past them.
+// Skip regions are used for forward control transfers to basic blocks
+// following a sequence of regions, when the transfer cannot be expressed by
+// falling through or breaking out of a loop; e.g. to an exit of a loop with
+// several exits, or to a basic block shared by two branches of which only one
+// passes through an intermediate merge node.
+type SkipRegion struct {
+	// Guard regions to skip, in order of occurrence.
+	Guards []*GuardRegion
+}
+
+// GotoRegion transfers control to a labeled basic block.
+type GotoRegion struct {
+	// Target basic block.
+	Target *ir.BasicBlock
+}
+
+// Structure returns the high-level control flow structure of the given
+// function definition, as a tree of regions. Basic blocks not reachable from
+// the entry basic block are omitted.
+//
+// The structuring algorithm follows the dominator tree of the function, in the
+// style of Ramsey's "Beyond Relooper" and the "No More Gotos" approach of
+// Yakdan et al. Every natural loop is recovered as a loop region (classified
+// as pre-tested, post-tested or endless) followed by the basic blocks reached
+// on exit from the loop, and conditional branches and switches are recovered
+// as if and switch regions followed by the basic blocks at which their control
+// flow merges. Control flow of reducible functions is expressed using break
+// and continue regions, and forward control transfers past the basic blocks
+// following a region are expressed using skip and guard regions; goto regions
+// are only emitted for irreducible control flow and for the targets of
+// unstructured terminators.
+func Structure(f *ir.Function) Region {
+	if len(f.Blocks) == 0 {
+		return nil
+	}
+	dom := NewDomTree(f)
+	li := NewLoopInfo(f, dom)
+	s := &structurer{
+		dom:     dom,
+		rpo:     make(map[*ir.BasicBlock]int),
+		merge:   make(map[*ir.BasicBlock]bool),
+		header:  make(map[*ir.BasicBlock]bool),
+		follows: make(map[*ir.BasicBlock][]*ir.BasicBlock),
+		exit:    make(map[*ir.BasicBlock]bool),
+		regions: make(map[*ir.BasicBlock]*BlockRegion),
+		labeled: make(map[*ir.BasicBlock]bool),
+	}
+	for _, l := range li.AllLoops() {
+		s.header[l.Header] = true
+	}
+	rpo := ReversePostorder(f)
+	for i, block := range rpo {
+		s.rpo[block] = i
+	}
+	preds := Preds(f)
+	for _, block := range rpo {
+		// A basic block is a merge node if it has more than one forward in-edge,
+		// or is the target of an unstructured terminator.
+		nforward := 0
+		for _, pred := range preds[block] {
+			if !dom.Reachable(pred) {
+				continue
+			}
+			if s.rpo[pred] < s.rpo[block] {
+				nforward++
+			}
+			if !isStructuredTerm(pred.Term) {
+				s.merge[block] = true
+				s.labeled[block] = true
+			}
+		}
+		if nforward > 1 {
+			s.merge[block] = true
+		}
+		// A basic block outside of a loop immediately dominated by a basic block
+		// of the loop follows the outermost such loop.
+		idom := dom.IDom(block)
+		if idom == nil {
+			continue
+		}
+		var outer *Loop
+		for l := li.LoopFor(idom); l != nil && !l.Contains(block); l = l.Parent {
+			outer = l
+		}
+		if outer != nil {
+			s.follows[outer.Header] = append(s.follows[outer.Header], block)
+			s.exit[block] = true
+			s.merge[block] = true
+		}
+	}
+	r := s.tree(f.Blocks[0], nil)
+	for block := range s.labeled {
+		if region, ok := s.regions[block]; ok {
+			region.Labeled = true
+		}
+	}
+	return r
+}
+
+// structurer recovers the high-level control flow structure of a function.
+type structurer struct {
+	// Dominator tree of the function.
+	dom *DomTree
+	// Reverse postorder number of each reachable basic block.
+	rpo map[*ir.BasicBlock]int
+	// Merge nodes; i.e. basic blocks not translated in place of their forward
+	// in-edge.
+	merge map[*ir.BasicBlock]bool
+	// Natural loop headers.
+	header map[*ir.BasicBlock]bool
+	// Basic blocks following each loop, ordered by reverse postorder.
+	follows map[*ir.BasicBlock][]*ir.BasicBlock
+	// Basic blocks following a loop.
+	exit map[*ir.BasicBlock]bool
+	// Block region of each translated basic block.
+	regions map[*ir.BasicBlock]*BlockRegion
+	// Basic blocks which must be labeled.
+	labeled map[*ir.BasicBlock]bool
+}
+
+// context is the syntactic context of a region being translated, from the
+// innermost enclosing construct outwards.
+type context []*contextEntry
+
+// contextEntry is an enclosing construct of a region being translated.
+type contextEntry struct {
+	// Enclosing loop; or nil if not a loop.
+	loop *LoopRegion
+	// Basic block following the enclosing sequence; or nil if not a sequence.
+	follow *ir.BasicBlock
+	// Guard region of the basic block following the enclosing sequence; or nil
+	// if not skipped by any control transfer.
+	guard *GuardRegion
+	// Enclosing construct is a switch.
+	isSwitch bool
+}
+
+// push returns a new context with the given entry as innermost construct.
+func (ctx context) push(entry *contextEntry) context {
+	return append(context{entry}, ctx...)
+}
+
+// tree returns the region of the dominator subtree rooted at the given basic
+// block.
+func (s *structurer) tree(block *ir.BasicBlock, ctx context) Region {
+	if !s.header[block] {
+		return s.within(block, s.mergeChildren(block), ctx)
+	}
+	return s.loop(block, s.follows[block], ctx)
+}
+
+// loop returns the loop region of the given loop header followed by the given
+// basic blocks, ordered by reverse postorder.
+func (s *structurer) loop(header *ir.BasicBlock, follows []*ir.BasicBlock, ctx context) Region {
+	if len(follows) == 0 {
+		loop := &LoopRegion{Header: header}
+		loop.Body = s.within(header, s.mergeChildren(header), ctx.push(&contextEntry{loop: loop}))
+		classifyLoop(loop)
+		return loop
+	}
+	follow := follows[len(follows)-1]
+	entry := &contextEntry{follow: follow}
+	first := s.loop(header, follows[:len(follows)-1], ctx.push(entry))
+	return seq(first, entry.guarded(s.tree(follow, ctx)))
+}
+
+// within returns the region of the given basic block followed by the given
+// merge nodes immediately dominated by the basic block, ordered by reverse
+// postorder.
+func (s *structurer) within(block *ir.BasicBlock, merges []*ir.BasicBlock, ctx context) Region {
+	if len(merges) == 0 {
+		region := &BlockRegion{Block: block, Term: !isStructuredTerm(block.Term)}
+		s.regions[block] = region
+		return seq(region, s.term(block, ctx))
+	}
+	// The merge node with the highest reverse postorder number follows the
+	// region of the basic block and the other merge nodes.
+	follow := merges[len(merges)-1]
+	entry := &contextEntry{follow: follow}
+	first := s.within(block, merges[:len(merges)-1], ctx.push(entry))
+	return seq(first, entry.guarded(s.tree(follow, ctx)))
+}
+
+// term returns the region of the control transfer of the terminator of the
+// given basic block.
+func (s *structurer) term(block *ir.BasicBlock, ctx context) Region {
+	switch term := block.Term.(type) {
+	case *ir.TermBr:
+		return s.branch(block, term.Target, ctx)
+	case *ir.TermCondBr:
+		if term.TargetTrue == term.TargetFalse {
+			return s.branch(block, term.TargetTrue, ctx)
+		}
+		return newIf(term.Cond, false, s.branch(block, term.TargetTrue, ctx), s.branch(block, term.TargetFalse, ctx))
+	case *ir.TermSwitch:
+		region := &SwitchRegion{Term: term}
+		caseCtx := ctx.push(&contextEntry{isSwitch: true})
+		cases := make(map[*ir.BasicBlock]*SwitchCase)
+		for _, c := range term.Cases {
+			if c.Target == term.TargetDefault {
+				continue
+			}
+			if sc, ok := cases[c.Target]; ok {
+				sc.Values = append(sc.Values, c.X)
+				continue
+			}
+			sc := &SwitchCase{Values: []constant.Constant{c.X}}
+			cases[c.Target] = sc
+			region.Cases = append(region.Cases, sc)
+			sc.Body = s.branch(block, c.Target, caseCtx)
+		}
+		region.Default = s.branch(block, term.TargetDefault, caseCtx)
+		return region
+	default:
+		// Control transfer of unstructured terminators is expressed by the
+		// terminator itself, and their targets are merge nodes.
+		return nil
+	}
+}
+
+// branch returns the region of the control transfer from the given source
+// basic block to the target basic block.
+func (s *structurer) branch(src, target *ir.BasicBlock, ctx context) Region {
+	if s.rpo[target] <= s.rpo[src] {
+		// Retreating edge.
+		for i, entry := range ctx {
+			if entry.loop == nil || entry.loop.Header != target {
+				continue
+			}
+			// Back edge to enclosing loop.
+			if ctx[:i].onlySwitches() {
+				// Implicit continue at end of loop body.
+				return nil
+			}
+			labeled := ctx[:i].hasLoop()
+			if labeled {
+				entry.loop.Labeled = true
+			}
+			return &ContinueRegion{Loop: entry.loop, Labeled: labeled}
+		}
+		return s.jump(target)
+	}
+	if !s.merge[target] {
+		// Forward edge to basic block with a single forward in-edge; translate
+		// the target in place.
+		return s.tree(target, ctx)
+	}
+	// Forward edge to merge node.
+	for k, entry := range ctx {
+		if entry.follow == target {
+			return exit(ctx[:k])
+		}
+	}
+	return s.jump(target)
+}
+
+// exit returns the region transferring control out of the given enclosing
+// constructs, to the basic block following them.
+func exit(ctx context) Region {
+	// Break out of the outermost loop, and skip the basic blocks following the
+	// sequences enclosing the loop.
+	j := -1
+	for i, entry := range ctx {
+		if entry.loop != nil {
+			j = i
+		}
+	}
+	var skip *SkipRegion
+	for _, entry := range ctx[j+1:] {
+		if entry.follow == nil {
+			continue
+		}
+		if entry.guard == nil {
+			entry.guard = &GuardRegion{Block: entry.follow}
+		}
+		if skip == nil {
+			skip = &SkipRegion{}
+		}
+		skip.Guards = append(skip.Guards, entry.guard)
+	}
+	if j == -1 {
+		if skip == nil {
+			// Implicit fall through to the following merge node.
+			return nil
+		}
+		return skip
+	}
+	loop := ctx[j].loop
+	labeled := ctx[:j].hasLoop() || ctx[:j].hasSwitch()
+	if labeled {
+		loop.Labeled = true
+	}
+	brk := &BreakRegion{Loop: loop, Labeled: labeled}
+	if skip == nil {
+		return brk
+	}
+	return seq(skip, brk)
+}
+
+// jump returns a goto region targeting the given basic block.
+func (s *structurer) jump(target *ir.BasicBlock) Region {
+	s.labeled[target] = true
+	return &GotoRegion{Target: target}
+}
+
+// mergeChildren returns the merge nodes immediately dominated by the given
+// basic block, ordered by reverse postorder. Basic blocks following a loop are
+// excluded.
+func (s *structurer) mergeChildren(block *ir.BasicBlock) []*ir.BasicBlock {
+	// Note, children in the dominator tree are in reverse postorder.
+	var merges []*ir.BasicBlock
+	for _, child := range s.dom.Children(block) {
+		if s.merge[child] && !s.exit[child] {
+			merges = append(merges, child)
+		}
+	}
+	return merges
+}
+
+// guarded returns the given region of the basic block following the sequence of
+// the context entry, guarded if skipped by any control transfer.
+func (entry *contextEntry) guarded(r Region) Region {
+	if entry.guard == nil {
+		return r
+	}
+	entry.guard.Body = r
+	return entry.guard
+}
+
+// onlySwitches reports whether all entries of the context are switches.
+func (ctx context) onlySwitches() bool {
+	for _, entry := range ctx {
+		if !entry.isSwitch {
+			return false
+		}
+	}
+	return true
+}
+
+// hasLoop reports whether the context contains a loop.
+func (ctx context) hasLoop() bool {
+	for _, entry := range ctx {
+		if entry.loop != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// hasSwitch reports whether the context contains a switch.
+func (ctx context) hasSwitch() bool {
+	for _, entry := range ctx {
+		if entry.isSwitch {
+			return true
+		}
+	}
+	return false
+}
+
+// ### [ Helper functions ] ####################################################
+
+// classifyLoop classifies the given endless loop as pre-tested or post-tested,
+// if the loop header or the end of the loop body conditionally exits the loop.
+func classifyLoop(loop *LoopRegion) {
+	body := flatten(loop.Body)
+	// Pre-tested loop.
+	//
+	//    for { Header; if Cond { A } else { break }; B }
+	//
+	// becomes
+	//
+	//    while Cond { A; B }
+	if len(body) >= 2 {
+		if hdr, ok := body[0].(*BlockRegion); ok && hdr.Block == loop.Header && !hdr.Term {
+			// Note, a loop consisting of a single basic block is post-tested.
+			if cond, negated, rest, ok := loopExit(loop, body[1]); ok && (len(body) > 2 || rest != nil) {
+				loop.Kind = LoopPreTested
+				loop.head = hdr
+				loop.Cond = cond
+				loop.Negated = negated
+				loop.Body = seq(append([]Region{rest}, body[2:]...)...)
+				return
+			}
+		}
+	}
+	// Post-tested loop.
+	//
+	//    for { A; if Cond { <continue> } else { break } }
+	//
+	// becomes
+	//
+	//    do { A } while Cond
+	//
+	// Note, a continue region in A transfers control to the loop header, while
+	// a continue in a post-tested loop would first test the loop condition; such
+	// loops are therefore left endless.
+	if len(body) >= 2 && !continues(seq(body[:len(body)-1]...), loop) {
+		if cond, negated, rest, ok := loopExit(loop, body[len(body)-1]); ok && rest == nil {
+			loop.Kind = LoopPostTested
+			loop.Cond = cond
+			loop.Negated = negated
+			loop.Body = seq(body[:len(body)-1]...)
+		}
+	}
+}
+
+// loopExit reports whether the given region is an if region with one branch
+// exiting the given loop (using an unlabeled break), and returns the condition
+// for remaining in the loop and the other branch.
+func loopExit(loop *LoopRegion, r Region) (cond value.Value, negated bool, rest Region, ok bool) {
+	ifRegion, ok := r.(*IfRegion)
+	if !ok {
+		return nil, false, nil, false
+	}
+	isExit := func(r Region) bool {
+		b, ok := r.(*BreakRegion)
+		return ok && b.Loop == loop && !b.Labeled
+	}
+	switch {
+	case isExit(ifRegion.Else):
+		return ifRegion.Cond, ifRegion.Negated, ifRegion.Then, true
+	case isExit(ifRegion.Then):
+		return ifRegion.Cond, !ifRegion.Negated, ifRegion.Else, true
+	}
+	return nil, false, nil, false
+}
+
+// continues reports whether the given region contains a continue region of the
+// given loop.
+func continues(r Region, loop *LoopRegion) bool {
+	switch r := r.(type) {
+	case *SeqRegion:
+		for _, r := range r.Regions {
+			if continues(r, loop) {
+				return true
+			}
+		}
+	case *IfRegion:
+		return continues(r.Then, loop) || continues(r.Else, loop)
+	case *SwitchRegion:
+		for _, c := range r.Cases {
+			if continues(c.Body, loop) {
+				return true
+			}
+		}
+		return continues(r.Default, loop)
+	case *LoopRegion:
+		return continues(r.Body, loop)
+	case *GuardRegion:
+		return continues(r.Body, loop)
+	case *ContinueRegion:
+		return r.Loop == loop
+	}
+	return false
+}
+
+// newIf returns a new if region based on the given condition and branches. The
+// condition is negated if only the else branch is present, and nil is returned
+// if neither branch is present. A branch which never completes normally is
+// hoisted as the then branch of an if-then region, followed by the other
+// branch.
+func newIf(cond value.Value, negated bool, then, els Region) Region {
+	switch {
+	case then == nil && els == nil:
+		return nil
+	case then == nil:
+		return &IfRegion{Cond: cond, Negated: !negated, Then: els}
+	case els == nil:
+		return &IfRegion{Cond: cond, Negated: negated, Then: then}
+	case isJump(then):
+		return seq(&IfRegion{Cond: cond, Negated: negated, Then: then}, els)
+	case isJump(els):
+		return seq(&IfRegion{Cond: cond, Negated: !negated, Then: els}, then)
+	}
+	return &IfRegion{Cond: cond, Negated: negated, Then: then, Else: els}
+}
+
+// isJump reports whether the given region never completes normally; i.e.
+// whether it always transfers control elsewhere.
+func isJump(r Region) bool {
+	rs := flatten(r)
+	if len(rs) == 0 {
+		return false
+	}
+	switch r := rs[len(rs)-1].(type) {
+	case *BlockRegion:
+		return r.Term
+	case *BreakRegion, *ContinueRegion, *GotoRegion:
+		return true
+	case *IfRegion:
+		return isJump(r.Then) && isJump(r.Else)
+	}
+	return false
+}
+
+// seq returns the sequence of the given regions; nested sequences are
+// flattened and empty regions are omitted.
+func seq(regions ...Region) Region {
+	var rs []Region
+	for _, r := range regions {
+		rs = append(rs, flatten(r)...)
+	}
+	switch len(rs) {
+	case 0:
+		return nil
+	case 1:
+		return rs[0]
+	}
+	return &SeqRegion{Regions: rs}
+}
+
+// flatten returns the regions of the given region if it is a sequence, the
+// region itself otherwise, or nil if empty.
+func flatten(r Region) []Region {
+	switch r := r.(type) {
+	case nil:
+		return nil
+	case *SeqRegion:
+		var rs []Region
+		for _, r := range r.Regions {
+			rs = append(rs, flatten(r)...)
+		}
+		return rs
+	default:
+		return []Region{r}
+	}
+}
+
+// isStructuredTerm reports whether the control transfer of the given
+// terminator may be represented by structured regions; i.e. whether it is a
+// br, conditional br or switch terminator.
+func isStructuredTerm(term ir.Terminator) bool {
+	switch term.(type) {
+	case *ir.TermBr, *ir.TermCondBr, *ir.TermSwitch:
+		return true
+	}
+	return false
+}
+
+// --- [ Pseudo-code ] ---------------------------------------------------------
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *BlockRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *SeqRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *IfRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *SwitchRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *LoopRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *BreakRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *ContinueRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *GuardRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *SkipRegion) String() string { return regionString(r) }
+
+// String returns a C-like pseudo-code representation of the region.
+func (r *GotoRegion) String() string { return regionString(r) }
+
+// regionString returns a C-like pseudo-code representation of the given
+// region.
+func regionString(r Region) string {
+	buf := &strings.Builder{}
+	writeRegion(buf, r, 0)
+	return buf.String()
+}
+
+// writeRegion writes a C-like pseudo-code representation of the given region
+// to buf, indented by the given number of tabs.
+func writeRegion(buf *strings.Builder, r Region, indent int) {
+	tabs := strings.Repeat("\t", indent)
+	switch r := r.(type) {
+	case nil:
+	case *BlockRegion:
+		if r.Labeled {
+			fmt.Fprintf(buf, "%s%s:\n", tabs, labelName(r.Block))
+		}
+		fmt.Fprintf(buf, "%s%s", tabs, r.Block.Ident())
+		if r.Term {
+			fmt.Fprintf(buf, "; %s", r.Block.Term.Def())
+		}
+		buf.WriteString("\n")
+	case *SeqRegion:
+		for _, r := range r.Regions {
+			writeRegion(buf, r, indent)
+		}
+	case *IfRegion:
+		fmt.Fprintf(buf, "%sif %s {\n", tabs, condString(r.Cond, r.Negated))
+		writeRegion(buf, r.Then, indent+1)
+		if r.Else != nil {
+			fmt.Fprintf(buf, "%s} else {\n", tabs)
+			writeRegion(buf, r.Else, indent+1)
+		}
+		fmt.Fprintf(buf, "%s}\n", tabs)
+	case *SwitchRegion:
+		fmt.Fprintf(buf, "%sswitch %s {\n", tabs, r.Term.X.Ident())
+		for _, c := range r.Cases {
+			var values []string
+			for _, v := range c.Values {
+				values = append(values, v.Ident())
+			}
+			fmt.Fprintf(buf, "%scase %s:\n", tabs, strings.Join(values, ", "))
+			writeRegion(buf, c.Body, indent+1)
+		}
+		fmt.Fprintf(buf, "%sdefault:\n", tabs)
+		writeRegion(buf, r.Default, indent+1)
+		fmt.Fprintf(buf, "%s}\n", tabs)
+	case *LoopRegion:
+		label := ""
+		if r.Labeled {
+			label = fmt.Sprintf("%s: ", loopLabel(r))
+		}
+		switch r.Kind {
+		case LoopEndless:
+			fmt.Fprintf(buf, "%s%sfor {\n", tabs, label)
+			writeRegion(buf, r.Body, indent+1)
+			fmt.Fprintf(buf, "%s}\n", tabs)
+		case LoopPreTested:
+			if r.head.Labeled {
+				// Transferring control to the loop header is equivalent to entering
+				// the loop.
+				fmt.Fprintf(buf, "%s%s:\n", tabs, labelName(r.Header))
+			}
+			fmt.Fprintf(buf, "%s%swhile %s; %s {\n", tabs, label, r.Header.Ident(), condString(r.Cond, r.Negated))
+			writeRegion(buf, r.Body, indent+1)
+			fmt.Fprintf(buf, "%s}\n", tabs)
+		case LoopPostTested:
+			fmt.Fprintf(buf, "%s%sdo {\n", tabs, label)
+			writeRegion(buf, r.Body, indent+1)
+			fmt.Fprintf(buf, "%s} while %s\n", tabs, condString(r.Cond, r.Negated))
+		}
+	case *BreakRegion:
+		if r.Labeled {
+			fmt.Fprintf(buf, "%sbreak %s\n", tabs, loopLabel(r.Loop))
+		} else {
+			fmt.Fprintf(buf, "%sbreak\n", tabs)
+		}
+	case *ContinueRegion:
+		if r.Labeled {
+			fmt.Fprintf(buf, "%scontinue %s\n", tabs, loopLabel(r.Loop))
+		} else {
+			fmt.Fprintf(buf, "%scontinue\n", tabs)
+		}
+	case *GuardRegion:
+		fmt.Fprintf(buf, "%sif %s {\n", tabs, guardName(r))
+		fmt.Fprintf(buf, "%s\t%s = false\n", tabs, guardName(r))
+		fmt.Fprintf(buf, "%s} else {\n", tabs)
+		writeRegion(buf, r.Body, indent+1)
+		fmt.Fprintf(buf, "%s}\n", tabs)
+	case *SkipRegion:
+		for _, g := range r.Guards {
+			fmt.Fprintf(buf, "%s%s = true\n", tabs, guardName(g))
+		}
+	case *GotoRegion:
+		fmt.Fprintf(buf, "%sgoto %s\n", tabs, labelName(r.Target))
+	default:
+		panic(fmt.Errorf("support for region %T not yet implemented", r))
+	}
+}
+
+// condString returns the string representation of the given condition.
+func condString(cond value.Value, negated bool) string {
+	if negated {
+		return "!" + cond.Ident()
+	}
+	return cond.Ident()
+}
+
+// labelName returns the label name of the given basic block.
+func labelName(block *ir.BasicBlock) string {
+	return "L_" + strings.TrimPrefix(block.Ident(), "%")
+}
+
+// loopLabel returns the label name of the given loop.
+func loopLabel(loop *LoopRegion) string {
+	return "loop_" + strings.TrimPrefix(loop.Header.Ident(), "%")
+}
+
+// guardName returns the name of the guard of the given guard region.
+func guardName(guard *GuardRegion) string {
+	return "skip_" + strings.TrimPrefix(guard.Block.Ident(), "%")
+}
+
+// isRegion ensures that only control flow regions can be assigned to the
+// analysis.Region interface.
+func (*BlockRegion) isRegion()    {}
+func (*SeqRegion) isRegion()      {}
+func (*IfRegion) isRegion()       {}
+func (*SwitchRegion) isRegion()   {}
+func (*LoopRegion) isRegion()     {}
+func (*BreakRegion) isRegion()    {}
+func (*ContinueRegion) isRegion() {}
+func (*GuardRegion) isRegion()    {}
+func (*SkipRegion) isRegion()     {}
+func (*GotoRegion) isRegion()     {}
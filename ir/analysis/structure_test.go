@@ -0,0 +1,415 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestStructure(t *testing.T) {
+	golden := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		// if-then-else
+		{
+			name: "if_else",
+			input: `
+define i32 @f(i1 %c) {
+entry:
+	br i1 %c, label %then, label %else
+
+then:
+	br label %exit
+
+else:
+	br label %exit
+
+exit:
+	%x = phi i32 [ 1, %then ], [ 2, %else ]
+	ret i32 %x
+}
+`,
+			want: `%entry
+if %c {
+	%then
+} else {
+	%else
+}
+%exit; ret i32 %x
+`,
+		},
+		// if-then
+		{
+			name: "if_then",
+			input: `
+define void @f(i1 %c) {
+entry:
+	br i1 %c, label %exit, label %then
+
+then:
+	br label %exit
+
+exit:
+	ret void
+}
+`,
+			want: `%entry
+if !%c {
+	%then
+}
+%exit; ret void
+`,
+		},
+		// pre-tested loop
+		{
+			name: "while",
+			input: `
+define void @f(i32 %n) {
+entry:
+	br label %cond
+
+cond:
+	%i = phi i32 [ 0, %entry ], [ %j, %body ]
+	%c = icmp slt i32 %i, %n
+	br i1 %c, label %body, label %exit
+
+body:
+	%j = add i32 %i, 1
+	br label %cond
+
+exit:
+	ret void
+}
+`,
+			want: `%entry
+while %cond; %c {
+	%body
+}
+%exit; ret void
+`,
+		},
+		// post-tested loop
+		{
+			name: "do_while",
+			input: `
+define void @f(i32 %n) {
+entry:
+	br label %body
+
+body:
+	%i = phi i32 [ 0, %entry ], [ %j, %body ]
+	%j = add i32 %i, 1
+	%c = icmp slt i32 %j, %n
+	br i1 %c, label %body, label %exit
+
+exit:
+	ret void
+}
+`,
+			want: `%entry
+do {
+	%body
+} while %c
+%exit; ret void
+`,
+		},
+		// endless loop with break and continue
+		{
+			name: "break_continue",
+			input: `
+define void @f(i1 %a, i1 %b) {
+entry:
+	br label %loop
+
+loop:
+	br i1 %a, label %exit, label %next
+
+next:
+	br i1 %b, label %skip, label %latch
+
+skip:
+	br label %loop
+
+latch:
+	call void @g()
+	br label %loop
+
+exit:
+	ret void
+}
+
+declare void @g()
+`,
+			want: `%entry
+while %loop; !%a {
+	%next
+	if %b {
+		%skip
+	} else {
+		%latch
+	}
+}
+%exit; ret void
+`,
+		},
+		// loop with continue; not post-tested as continue skips the condition
+		{
+			name: "continue_not_do_while",
+			input: `
+define void @f(i1 %a, i1 %b, i1 %c) {
+entry:
+	br label %loop
+
+loop:
+	br i1 %a, label %skip, label %mid
+
+skip:
+	br i1 %b, label %loop, label %mid
+
+mid:
+	br i1 %c, label %loop, label %exit
+
+exit:
+	ret void
+}
+`,
+			want: `%entry
+for {
+	%loop
+	if %a {
+		%skip
+		if %b {
+			continue
+		}
+	}
+	%mid
+	if !%c {
+		break
+	}
+}
+%exit; ret void
+`,
+		},
+		// nested loops with labeled break
+		{
+			name: "nested",
+			input: `
+define void @f(i1 %a, i1 %b, i1 %c) {
+entry:
+	br label %outer
+
+outer:
+	br label %inner
+
+inner:
+	br i1 %a, label %exit, label %inner.latch
+
+inner.latch:
+	br i1 %b, label %inner, label %outer.latch
+
+outer.latch:
+	br i1 %c, label %outer, label %exit
+
+exit:
+	ret void
+}
+`,
+			want: `%entry
+loop_outer: do {
+	%outer
+	do {
+		%inner
+		if %a {
+			break loop_outer
+		}
+		%inner.latch
+	} while %b
+	%outer.latch
+} while %c
+%exit; ret void
+`,
+		},
+		// loop with two exits merging after the loop
+		{
+			name: "two_exits",
+			input: `
+define void @f(i1 %a, i1 %b) {
+entry:
+	br label %loop
+
+loop:
+	br i1 %a, label %exit.a, label %latch
+
+exit.a:
+	call void @g()
+	br label %exit
+
+latch:
+	br i1 %b, label %loop, label %exit.b
+
+exit.b:
+	call void @h()
+	br label %exit
+
+exit:
+	ret void
+}
+
+declare void @g()
+
+declare void @h()
+`,
+			want: `%entry
+do {
+	%loop
+	if %a {
+		skip_exit.b = true
+		break
+	}
+	%latch
+} while %b
+if skip_exit.b {
+	skip_exit.b = false
+} else {
+	%exit.b
+	skip_exit.a = true
+}
+if skip_exit.a {
+	skip_exit.a = false
+} else {
+	%exit.a
+}
+%exit; ret void
+`,
+		},
+		// forward edge past a merge node
+		{
+			name: "skip_merge",
+			input: `
+define void @f(i1 %a, i1 %b) {
+entry:
+	br i1 %a, label %x, label %y
+
+x:
+	br label %merge
+
+y:
+	br i1 %b, label %merge, label %exit
+
+merge:
+	call void @g()
+	br label %exit
+
+exit:
+	ret void
+}
+
+declare void @g()
+`,
+			want: `%entry
+if %a {
+	%x
+} else {
+	%y
+	if !%b {
+		skip_merge = true
+	}
+}
+if skip_merge {
+	skip_merge = false
+} else {
+	%merge
+}
+%exit; ret void
+`,
+		},
+		// switch
+		{
+			name: "switch",
+			input: `
+define void @f(i32 %x) {
+entry:
+	switch i32 %x, label %default [
+		i32 1, label %one
+		i32 2, label %two
+		i32 3, label %one
+		i32 4, label %default
+	]
+
+one:
+	br label %exit
+
+two:
+	ret void
+
+default:
+	br label %exit
+
+exit:
+	ret void
+}
+`,
+			want: `%entry
+switch %x {
+case 1, 3:
+	%one
+case 2:
+	%two; ret void
+default:
+	%default
+}
+%exit; ret void
+`,
+		},
+		// irreducible control flow
+		{
+			name: "irreducible",
+			input: `
+define void @f(i1 %a, i1 %b, i1 %c) {
+entry:
+	br i1 %a, label %x, label %y
+
+x:
+	br i1 %b, label %y, label %exit
+
+y:
+	br i1 %c, label %x, label %exit
+
+exit:
+	ret void
+}
+`,
+			want: `%entry
+if %a {
+	L_x:
+	%x
+	if !%b {
+		skip_y = true
+	}
+}
+if skip_y {
+	skip_y = false
+} else {
+	%y
+	if %c {
+		goto L_x
+	}
+}
+%exit; ret void
+`,
+		},
+	}
+	for _, g := range golden {
+		m, err := asm.ParseString(g.name+".ll", g.input)
+		if err != nil {
+			t.Errorf("%q: unable to parse module; %v", g.name, err)
+			continue
+		}
+		got := Structure(m.Funcs[0]).String()
+		if got != g.want {
+			t.Errorf("%q: structure mismatch; expected:\n%s\ngot:\n%s", g.name, g.want, got)
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/metadata"
+)
+
+// === [ Metadata attachments ] ================================================
+
+// MDAttachments returns the metadata attachments of the given value, which is
+// one of *ir.Global, *ir.Function, ir.Instruction or ir.Terminator. A nil slice
+// is returned for values that may not have metadata attachments.
+func MDAttachments(v interface{}) []*metadata.MetadataAttachment {
+	mds := mdAttachments(v)
+	if mds == nil {
+		return nil
+	}
+	return *mds
+}
+
+// MDAttachment returns the metadata node attached to the given value with the
+// specified name (without '!' prefix; e.g. "dbg"), or nil if not present.
+func MDAttachment(v interface{}, name string) metadata.MDNode {
+	for _, md := range MDAttachments(v) {
+		if md.Name == name {
+			return md.Node
+		}
+	}
+	return nil
+}
+
+// SetMDAttachment attaches the given metadata node to the value with the
+// specified name (without '!' prefix; e.g. "dbg"), replacing any existing
+// attachment of the same name.
+func SetMDAttachment(v interface{}, name string, node metadata.MDNode) {
+	mds := mdAttachments(v)
+	if mds == nil {
+		panic(fmt.Errorf("support for metadata attachments on value %T not yet implemented", v))
+	}
+	for _, md := range *mds {
+		if md.Name == name {
+			md.Node = node
+			return
+		}
+	}
+	md := &metadata.MetadataAttachment{Name: name, Node: node}
+	*mds = append(*mds, md)
+}
+
+// RemoveMDAttachment removes the metadata attachment with the specified name
+// (without '!' prefix; e.g. "dbg") from the given value, and reports whether
+// such an attachment was present.
+func RemoveMDAttachment(v interface{}, name string) bool {
+	mds := mdAttachments(v)
+	if mds == nil {
+		return false
+	}
+	removed := false
+	var keep []*metadata.MetadataAttachment
+	for _, md := range *mds {
+		if md.Name == name {
+			removed = true
+			continue
+		}
+		keep = append(keep, md)
+	}
+	*mds = keep
+	return removed
+}
+
+// ### [ Helper functions ] ####################################################
+
+// mdAttachments returns a pointer to the metadata attachments of the given
+// value, or nil if the value may not have metadata attachments.
+func mdAttachments(v interface{}) *[]*metadata.MetadataAttachment {
+	switch v := v.(type) {
+	// Global variables and functions.
+	case *Global:
+		return &v.Metadata
+	case *Function:
+		return &v.Metadata
+	// Instructions.
+	case *InstAdd:
+		return &v.Metadata
+	case *InstFAdd:
+		return &v.Metadata
+	case *InstSub:
+		return &v.Metadata
+	case *InstFSub:
+		return &v.Metadata
+	case *InstMul:
+		return &v.Metadata
+	case *InstFMul:
+		return &v.Metadata
+	case *InstUDiv:
+		return &v.Metadata
+	case *InstSDiv:
+		return &v.Metadata
+	case *InstFDiv:
+		return &v.Metadata
+	case *InstURem:
+		return &v.Metadata
+	case *InstSRem:
+		return &v.Metadata
+	case *InstFRem:
+		return &v.Metadata
+	case *InstShl:
+		return &v.Metadata
+	case *InstLShr:
+		return &v.Metadata
+	case *InstAShr:
+		return &v.Metadata
+	case *InstAnd:
+		return &v.Metadata
+	case *InstOr:
+		return &v.Metadata
+	case *InstXor:
+		return &v.Metadata
+	case *InstExtractElement:
+		return &v.Metadata
+	case *InstInsertElement:
+		return &v.Metadata
+	case *InstShuffleVector:
+		return &v.Metadata
+	case *InstExtractValue:
+		return &v.Metadata
+	case *InstInsertValue:
+		return &v.Metadata
+	case *InstAlloca:
+		return &v.Metadata
+	case *InstLoad:
+		return &v.Metadata
+	case *InstStore:
+		return &v.Metadata
+	case *InstFence:
+		return &v.Metadata
+	case *InstCmpXchg:
+		return &v.Metadata
+	case *InstAtomicRMW:
+		return &v.Metadata
+	case *InstGetElementPtr:
+		return &v.Metadata
+	case *InstTrunc:
+		return &v.Metadata
+	case *InstZExt:
+		return &v.Metadata
+	case *InstSExt:
+		return &v.Metadata
+	case *InstFPTrunc:
+		return &v.Metadata
+	case *InstFPExt:
+		return &v.Metadata
+	case *InstFPToUI:
+		return &v.Metadata
+	case *InstFPToSI:
+		return &v.Metadata
+	case *InstUIToFP:
+		return &v.Metadata
+	case *InstSIToFP:
+		return &v.Metadata
+	case *InstPtrToInt:
+		return &v.Metadata
+	case *InstIntToPtr:
+		return &v.Metadata
+	case *InstBitCast:
+		return &v.Metadata
+	case *InstAddrSpaceCast:
+		return &v.Metadata
+	case *InstICmp:
+		return &v.Metadata
+	case *InstFCmp:
+		return &v.Metadata
+	case *InstPhi:
+		return &v.Metadata
+	case *InstSelect:
+		return &v.Metadata
+	case *InstCall:
+		return &v.Metadata
+	case *InstVAArg:
+		return &v.Metadata
+	case *InstLandingPad:
+		return &v.Metadata
+	case *InstCatchPad:
+		return &v.Metadata
+	case *InstCleanupPad:
+		return &v.Metadata
+	// Terminators.
+	case *TermRet:
+		return &v.Metadata
+	case *TermBr:
+		return &v.Metadata
+	case *TermCondBr:
+		return &v.Metadata
+	case *TermSwitch:
+		return &v.Metadata
+	case *TermIndirectBr:
+		return &v.Metadata
+	case *TermInvoke:
+		return &v.Metadata
+	case *TermResume:
+		return &v.Metadata
+	case *TermCatchSwitch:
+		return &v.Metadata
+	case *TermCatchRet:
+		return &v.Metadata
+	case *TermCleanupRet:
+		return &v.Metadata
+	case *TermUnreachable:
+		return &v.Metadata
+	default:
+		return nil
+	}
+}
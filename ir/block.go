@@ -47,16 +47,30 @@ func (block *BasicBlock) Type() types.Type {
 
 // Def returns the LLVM syntax representation of the basic block definition.
 func (block *BasicBlock) Def() string {
+	return block.def(nil)
+}
+
+// def returns the LLVM syntax representation of the basic block definition,
+// including the given comments of the basic block and its instructions.
+func (block *BasicBlock) def(comments CommentMap) string {
 	// Name=LabelIdentopt Insts=Instruction* Term=Terminator
 	buf := &strings.Builder{}
+	comments.writeLeading(buf, block, "")
 	if block.IsUnnamed() {
 		fmt.Fprintf(buf, "; <label>:%d\n", block.LocalID)
+		// The label of an unnamed basic block is itself a comment, thus output
+		// its trailing comment on a line of its own.
+		if trailing := comments.trailing(block); len(trailing) > 0 {
+			fmt.Fprintf(buf, "\t%s\n", trailing[1:])
+		}
 	} else {
-		fmt.Fprintf(buf, "%s\n", enc.Label(block.LocalName))
+		fmt.Fprintf(buf, "%s%s\n", enc.Label(block.LocalName), comments.trailing(block))
 	}
 	for _, inst := range block.Insts {
-		fmt.Fprintf(buf, "\t%s\n", inst.Def())
+		comments.writeLeading(buf, inst, "\t")
+		fmt.Fprintf(buf, "\t%s%s\n", inst.Def(), comments.trailing(inst))
 	}
-	fmt.Fprintf(buf, "\t%s", block.Term.Def())
+	comments.writeLeading(buf, block.Term, "\t")
+	fmt.Fprintf(buf, "\t%s%s", block.Term.Def(), comments.trailing(block.Term))
 	return buf.String()
 }
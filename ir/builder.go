@@ -0,0 +1,132 @@
+package ir
+
+import (
+	"fmt"
+)
+
+// === [ Builder ] =============================================================
+
+// Builder is an LLVM IR instruction builder, which inserts instructions at an
+// insertion point within a basic block.
+//
+// The insertion point is positioned before the instruction at index Index of
+// Block.Insts; the index len(Block.Insts) denotes the position before the
+// terminator of the basic block. The index is kept up to date as instructions
+// are inserted into or removed from the basic block through the builder.
+type Builder struct {
+	// Basic block of the insertion point.
+	Block *BasicBlock
+	// Index of the insertion point in the instructions of the basic block.
+	Index int
+}
+
+// NewBuilder returns a new instruction builder, positioned before the
+// terminator of the given basic block.
+func NewBuilder(block *BasicBlock) *Builder {
+	b := &Builder{}
+	b.PositionBeforeTerm(block)
+	return b
+}
+
+// --- [ Insertion point ] -----------------------------------------------------
+
+// PositionAtStart positions the insertion point at the start of the given basic
+// block.
+func (b *Builder) PositionAtStart(block *BasicBlock) {
+	b.Block = block
+	b.Index = 0
+}
+
+// PositionAfterPhis positions the insertion point after the phi instructions at
+// the start of the given basic block.
+func (b *Builder) PositionAfterPhis(block *BasicBlock) {
+	b.Block = block
+	b.Index = 0
+	for _, inst := range block.Insts {
+		if _, ok := inst.(*InstPhi); !ok {
+			break
+		}
+		b.Index++
+	}
+}
+
+// PositionBeforeTerm positions the insertion point before the terminator of the
+// given basic block; i.e. at the end of its instructions.
+func (b *Builder) PositionBeforeTerm(block *BasicBlock) {
+	b.Block = block
+	b.Index = len(block.Insts)
+}
+
+// PositionBefore positions the insertion point before the given instruction of
+// the basic block.
+func (b *Builder) PositionBefore(block *BasicBlock, inst Instruction) {
+	b.Block = block
+	b.Index = indexOf(block, inst)
+}
+
+// PositionAfter positions the insertion point after the given instruction of
+// the basic block.
+func (b *Builder) PositionAfter(block *BasicBlock, inst Instruction) {
+	b.Block = block
+	b.Index = indexOf(block, inst) + 1
+}
+
+// --- [ Instruction manipulation ] --------------------------------------------
+
+// Insert inserts the given instruction at the insertion point, and advances the
+// insertion point past the instruction. The instruction is returned.
+func (b *Builder) Insert(inst Instruction) Instruction {
+	block := b.Block
+	block.Insts = append(block.Insts, nil)
+	copy(block.Insts[b.Index+1:], block.Insts[b.Index:])
+	block.Insts[b.Index] = inst
+	b.Index++
+	return inst
+}
+
+// Erase removes the given instruction from the basic block. Uses of the
+// instruction are not updated.
+func (b *Builder) Erase(block *BasicBlock, inst Instruction) {
+	i := indexOf(block, inst)
+	block.Insts = append(block.Insts[:i], block.Insts[i+1:]...)
+	if block == b.Block && i < b.Index {
+		b.Index--
+	}
+}
+
+// Move moves the given instruction of the basic block to the insertion point,
+// and advances the insertion point past the instruction.
+func (b *Builder) Move(block *BasicBlock, inst Instruction) {
+	b.Erase(block, inst)
+	b.Insert(inst)
+}
+
+// Replace replaces the given instruction of the basic block with the new
+// instruction. Uses of the old instruction are not updated.
+func (b *Builder) Replace(block *BasicBlock, old, new Instruction) {
+	block.Insts[indexOf(block, old)] = new
+}
+
+// --- [ Basic block manipulation ] --------------------------------------------
+
+// NewBlockAfter inserts a new basic block after the basic block of the
+// insertion point, based on the given label name. An empty label name indicates
+// an unnamed basic block. The insertion point is left unchanged.
+func (b *Builder) NewBlockAfter(name string) *BasicBlock {
+	if b.Block.Parent == nil {
+		panic(fmt.Errorf("unable to insert basic block after %s; missing parent function", b.Block.Ident()))
+	}
+	return b.Block.Parent.NewBlockAfter(b.Block, name)
+}
+
+// ### [ Helper functions ] ####################################################
+
+// indexOf returns the index of the given instruction in the basic block.
+func indexOf(block *BasicBlock, inst Instruction) int {
+	for i, v := range block.Insts {
+		if v == inst {
+			return i
+		}
+	}
+	panic(fmt.Errorf("unable to locate instruction %T in basic block %s", inst, block.Ident()))
+}
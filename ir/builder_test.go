@@ -0,0 +1,65 @@
+package ir
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestBuilder(t *testing.T) {
+	x := NewParam("x", types.I32)
+	f := NewFunc("f", types.I32, x)
+	entry := f.NewBlock("entry")
+	mul := entry.NewMul(x, constant.NewInt(types.I32, 2))
+	mul.SetName("mul")
+	entry.NewRet(mul)
+
+	// Replace `x*2` by `x<<1`.
+	b := NewBuilder(entry)
+	b.PositionBefore(entry, mul)
+	shl := NewShl(x, constant.NewInt(types.I32, 1))
+	shl.SetName("shl")
+	b.Insert(shl)
+	b.Erase(entry, mul)
+	entry.Term.(*TermRet).X = shl
+	if b.Index != 1 {
+		t.Errorf("insertion point mismatch; expected 1, got %d", b.Index)
+	}
+
+	// Hoist a new instruction to the start of the basic block.
+	add := entry.NewAdd(x, x)
+	add.SetName("add")
+	b.PositionAtStart(entry)
+	b.Move(entry, add)
+	sub := NewSub(add, x)
+	sub.SetName("sub")
+	b.Replace(entry, shl, sub)
+	entry.Term.(*TermRet).X = sub
+
+	exit := b.NewBlockAfter("exit")
+	exit.NewRet(add)
+	b.PositionBeforeTerm(exit)
+	b.Insert(NewFence(enum.AtomicOrderingSeqCst))
+
+	const want = `
+define i32 @f(i32 %x) {
+entry:
+	%add = add i32 %x, %x
+	%sub = sub i32 %add, %x
+	ret i32 %sub
+
+exit:
+	fence seq_cst
+	ret i32 %add
+}`
+	got := f.Def()
+	if got != strings.TrimSpace(want) {
+		t.Errorf("function mismatch; expected %q, got %q", strings.TrimSpace(want), got)
+	}
+	if exit.Parent != f || f.Blocks[1] != exit {
+		t.Errorf("basic block %s not inserted into function %s", exit.Ident(), f.Ident())
+	}
+}
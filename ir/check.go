@@ -0,0 +1,843 @@
+package ir
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Type checking ] =======================================================
+
+// CheckInst type checks the operands of the given instruction, and returns an
+// error describing the first type mismatch found.
+//
+// The operands of binary, bitwise, conversion and compare instructions, the
+// pointer operands of load, store and getelementptr instructions, and the
+// arguments of call instructions (against the function signature of the
+// callee) are checked. Other instructions are accepted as is.
+//
+// CheckInst does not rely on the cached result type of the instruction, and may
+// thus be used to validate instructions before their type is computed.
+func CheckInst(inst Instruction) error {
+	switch inst := inst.(type) {
+	// Binary instructions.
+	case *InstAdd:
+		return checkIntBinary("add", inst.X, inst.Y)
+	case *InstFAdd:
+		return checkFloatBinary("fadd", inst.X, inst.Y)
+	case *InstSub:
+		return checkIntBinary("sub", inst.X, inst.Y)
+	case *InstFSub:
+		return checkFloatBinary("fsub", inst.X, inst.Y)
+	case *InstMul:
+		return checkIntBinary("mul", inst.X, inst.Y)
+	case *InstFMul:
+		return checkFloatBinary("fmul", inst.X, inst.Y)
+	case *InstUDiv:
+		return checkIntBinary("udiv", inst.X, inst.Y)
+	case *InstSDiv:
+		return checkIntBinary("sdiv", inst.X, inst.Y)
+	case *InstFDiv:
+		return checkFloatBinary("fdiv", inst.X, inst.Y)
+	case *InstURem:
+		return checkIntBinary("urem", inst.X, inst.Y)
+	case *InstSRem:
+		return checkIntBinary("srem", inst.X, inst.Y)
+	case *InstFRem:
+		return checkFloatBinary("frem", inst.X, inst.Y)
+	// Bitwise instructions.
+	case *InstShl:
+		return checkIntBinary("shl", inst.X, inst.Y)
+	case *InstLShr:
+		return checkIntBinary("lshr", inst.X, inst.Y)
+	case *InstAShr:
+		return checkIntBinary("ashr", inst.X, inst.Y)
+	case *InstAnd:
+		return checkIntBinary("and", inst.X, inst.Y)
+	case *InstOr:
+		return checkIntBinary("or", inst.X, inst.Y)
+	case *InstXor:
+		return checkIntBinary("xor", inst.X, inst.Y)
+	// Memory instructions.
+	case *InstLoad:
+		return checkLoad(inst)
+	case *InstStore:
+		return checkStore(inst)
+	case *InstGetElementPtr:
+		return checkGetElementPtr(inst)
+	// Conversion instructions.
+	case *InstTrunc:
+		return checkConversion("trunc", inst.From, inst.To)
+	case *InstZExt:
+		return checkConversion("zext", inst.From, inst.To)
+	case *InstSExt:
+		return checkConversion("sext", inst.From, inst.To)
+	case *InstFPTrunc:
+		return checkConversion("fptrunc", inst.From, inst.To)
+	case *InstFPExt:
+		return checkConversion("fpext", inst.From, inst.To)
+	case *InstFPToUI:
+		return checkConversion("fptoui", inst.From, inst.To)
+	case *InstFPToSI:
+		return checkConversion("fptosi", inst.From, inst.To)
+	case *InstUIToFP:
+		return checkConversion("uitofp", inst.From, inst.To)
+	case *InstSIToFP:
+		return checkConversion("sitofp", inst.From, inst.To)
+	case *InstPtrToInt:
+		return checkConversion("ptrtoint", inst.From, inst.To)
+	case *InstIntToPtr:
+		return checkConversion("inttoptr", inst.From, inst.To)
+	case *InstBitCast:
+		return checkConversion("bitcast", inst.From, inst.To)
+	case *InstAddrSpaceCast:
+		return checkConversion("addrspacecast", inst.From, inst.To)
+	// Other instructions.
+	case *InstICmp:
+		return checkICmp(inst.X, inst.Y)
+	case *InstFCmp:
+		return checkFCmp(inst.X, inst.Y)
+	case *InstCall:
+		return checkCall(inst.Callee, inst.Args)
+	}
+	return nil
+}
+
+// --- [ Checked constructors ] ------------------------------------------------
+
+// The checked constructors below behave as their unchecked counterparts (e.g.
+// NewAdd), but type check the operands (see CheckInst) before computing the
+// type of the instruction, and return an error instead of producing an invalid
+// instruction.
+
+// ~~~ [ Binary instructions ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// NewAddChecked returns a new add instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewAddChecked(x, y value.Value) (*InstAdd, error) {
+	inst := &InstAdd{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFAddChecked returns a new fadd instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewFAddChecked(x, y value.Value) (*InstFAdd, error) {
+	inst := &InstFAdd{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewSubChecked returns a new sub instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewSubChecked(x, y value.Value) (*InstSub, error) {
+	inst := &InstSub{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFSubChecked returns a new fsub instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewFSubChecked(x, y value.Value) (*InstFSub, error) {
+	inst := &InstFSub{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewMulChecked returns a new mul instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewMulChecked(x, y value.Value) (*InstMul, error) {
+	inst := &InstMul{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFMulChecked returns a new fmul instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewFMulChecked(x, y value.Value) (*InstFMul, error) {
+	inst := &InstFMul{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewUDivChecked returns a new udiv instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewUDivChecked(x, y value.Value) (*InstUDiv, error) {
+	inst := &InstUDiv{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewSDivChecked returns a new sdiv instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewSDivChecked(x, y value.Value) (*InstSDiv, error) {
+	inst := &InstSDiv{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFDivChecked returns a new fdiv instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewFDivChecked(x, y value.Value) (*InstFDiv, error) {
+	inst := &InstFDiv{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewURemChecked returns a new urem instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewURemChecked(x, y value.Value) (*InstURem, error) {
+	inst := &InstURem{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewSRemChecked returns a new srem instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewSRemChecked(x, y value.Value) (*InstSRem, error) {
+	inst := &InstSRem{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFRemChecked returns a new frem instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewFRemChecked(x, y value.Value) (*InstFRem, error) {
+	inst := &InstFRem{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// ~~~ [ Bitwise instructions ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// NewShlChecked returns a new shl instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewShlChecked(x, y value.Value) (*InstShl, error) {
+	inst := &InstShl{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewLShrChecked returns a new lshr instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewLShrChecked(x, y value.Value) (*InstLShr, error) {
+	inst := &InstLShr{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewAShrChecked returns a new ashr instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewAShrChecked(x, y value.Value) (*InstAShr, error) {
+	inst := &InstAShr{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewAndChecked returns a new and instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewAndChecked(x, y value.Value) (*InstAnd, error) {
+	inst := &InstAnd{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewOrChecked returns a new or instruction based on the given operands, or an
+// error if the operand types are invalid.
+func NewOrChecked(x, y value.Value) (*InstOr, error) {
+	inst := &InstOr{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewXorChecked returns a new xor instruction based on the given operands, or
+// an error if the operand types are invalid.
+func NewXorChecked(x, y value.Value) (*InstXor, error) {
+	inst := &InstXor{X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// ~~~ [ Memory instructions ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// NewLoadChecked returns a new load instruction based on the given source
+// address, or an error if the source address is not of pointer type.
+func NewLoadChecked(src value.Value) (*InstLoad, error) {
+	inst := &InstLoad{Src: src}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewStoreChecked returns a new store instruction based on the given source
+// value and destination address, or an error if the destination address is
+// not a pointer to the type of the source value.
+func NewStoreChecked(src, dst value.Value) (*InstStore, error) {
+	inst := &InstStore{Src: src, Dst: dst}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	return inst, nil
+}
+
+// NewGetElementPtrChecked returns a new getelementptr instruction based on the
+// given source address and element indices, or an error if the source address
+// or indices are invalid.
+func NewGetElementPtrChecked(src value.Value, indices ...value.Value) (*InstGetElementPtr, error) {
+	inst := &InstGetElementPtr{Src: src, Indices: indices}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// ~~~ [ Conversion instructions ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// NewTruncChecked returns a new trunc instruction based on the given source
+// value and target type, or an error if the conversion is invalid.
+func NewTruncChecked(from value.Value, to types.Type) (*InstTrunc, error) {
+	inst := &InstTrunc{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewZExtChecked returns a new zext instruction based on the given source value
+// and target type, or an error if the conversion is invalid.
+func NewZExtChecked(from value.Value, to types.Type) (*InstZExt, error) {
+	inst := &InstZExt{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewSExtChecked returns a new sext instruction based on the given source value
+// and target type, or an error if the conversion is invalid.
+func NewSExtChecked(from value.Value, to types.Type) (*InstSExt, error) {
+	inst := &InstSExt{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFPTruncChecked returns a new fptrunc instruction based on the given source
+// value and target type, or an error if the conversion is invalid.
+func NewFPTruncChecked(from value.Value, to types.Type) (*InstFPTrunc, error) {
+	inst := &InstFPTrunc{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFPExtChecked returns a new fpext instruction based on the given source
+// value and target type, or an error if the conversion is invalid.
+func NewFPExtChecked(from value.Value, to types.Type) (*InstFPExt, error) {
+	inst := &InstFPExt{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFPToUIChecked returns a new fptoui instruction based on the given source
+// value and target type, or an error if the conversion is invalid.
+func NewFPToUIChecked(from value.Value, to types.Type) (*InstFPToUI, error) {
+	inst := &InstFPToUI{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFPToSIChecked returns a new fptosi instruction based on the given source
+// value and target type, or an error if the conversion is invalid.
+func NewFPToSIChecked(from value.Value, to types.Type) (*InstFPToSI, error) {
+	inst := &InstFPToSI{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewUIToFPChecked returns a new uitofp instruction based on the given source
+// value and target type, or an error if the conversion is invalid.
+func NewUIToFPChecked(from value.Value, to types.Type) (*InstUIToFP, error) {
+	inst := &InstUIToFP{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewSIToFPChecked returns a new sitofp instruction based on the given source
+// value and target type, or an error if the conversion is invalid.
+func NewSIToFPChecked(from value.Value, to types.Type) (*InstSIToFP, error) {
+	inst := &InstSIToFP{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewPtrToIntChecked returns a new ptrtoint instruction based on the given
+// source value and target type, or an error if the conversion is invalid.
+func NewPtrToIntChecked(from value.Value, to types.Type) (*InstPtrToInt, error) {
+	inst := &InstPtrToInt{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewIntToPtrChecked returns a new inttoptr instruction based on the given
+// source value and target type, or an error if the conversion is invalid.
+func NewIntToPtrChecked(from value.Value, to types.Type) (*InstIntToPtr, error) {
+	inst := &InstIntToPtr{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewBitCastChecked returns a new bitcast instruction based on the given source
+// value and target type, or an error if the conversion is invalid.
+func NewBitCastChecked(from value.Value, to types.Type) (*InstBitCast, error) {
+	inst := &InstBitCast{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewAddrSpaceCastChecked returns a new addrspacecast instruction based on the
+// given source value and target type, or an error if the conversion is
+// invalid.
+func NewAddrSpaceCastChecked(from value.Value, to types.Type) (*InstAddrSpaceCast, error) {
+	inst := &InstAddrSpaceCast{From: from, To: to}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// ~~~ [ Other instructions ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// NewICmpChecked returns a new icmp instruction based on the given integer
+// comparison predicate and operands, or an error if the operand types are
+// invalid.
+func NewICmpChecked(pred enum.IPred, x, y value.Value) (*InstICmp, error) {
+	inst := &InstICmp{Pred: pred, X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewFCmpChecked returns a new fcmp instruction based on the given
+// floating-point comparison predicate and operands, or an error if the operand
+// types are invalid.
+func NewFCmpChecked(pred enum.FPred, x, y value.Value) (*InstFCmp, error) {
+	inst := &InstFCmp{Pred: pred, X: x, Y: y}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// NewCallChecked returns a new call instruction based on the given callee and
+// function arguments, or an error if the arguments do not match the function
+// signature of the callee.
+func NewCallChecked(callee value.Value, args ...value.Value) (*InstCall, error) {
+	inst := &InstCall{Callee: callee, Args: args}
+	if err := CheckInst(inst); err != nil {
+		return nil, err
+	}
+	inst.Type()
+	return inst, nil
+}
+
+// ### [ Helper functions ] ####################################################
+
+// checkIntBinary type checks the operands of the given integer binary or
+// bitwise instruction.
+func checkIntBinary(op string, x, y value.Value) error {
+	xt, yt := x.Type(), y.Type()
+	if !isIntOrIntVector(xt) {
+		return fmt.Errorf("invalid operand type of %s instruction; expected integer or integer vector type, got %s", op, xt)
+	}
+	if !xt.Equal(yt) {
+		return fmt.Errorf("operand type mismatch of %s instruction; %s != %s", op, xt, yt)
+	}
+	return nil
+}
+
+// checkFloatBinary type checks the operands of the given floating-point binary
+// instruction.
+func checkFloatBinary(op string, x, y value.Value) error {
+	xt, yt := x.Type(), y.Type()
+	if !isFloatOrFloatVector(xt) {
+		return fmt.Errorf("invalid operand type of %s instruction; expected floating-point or floating-point vector type, got %s", op, xt)
+	}
+	if !xt.Equal(yt) {
+		return fmt.Errorf("operand type mismatch of %s instruction; %s != %s", op, xt, yt)
+	}
+	return nil
+}
+
+// checkICmp type checks the operands of an icmp instruction.
+func checkICmp(x, y value.Value) error {
+	xt, yt := x.Type(), y.Type()
+	if !isIntOrIntVector(xt) && !isPtrOrPtrVector(xt) {
+		return fmt.Errorf("invalid operand type of icmp instruction; expected integer, pointer or vector type, got %s", xt)
+	}
+	if !xt.Equal(yt) {
+		return fmt.Errorf("operand type mismatch of icmp instruction; %s != %s", xt, yt)
+	}
+	return nil
+}
+
+// checkFCmp type checks the operands of an fcmp instruction.
+func checkFCmp(x, y value.Value) error {
+	xt, yt := x.Type(), y.Type()
+	if !isFloatOrFloatVector(xt) {
+		return fmt.Errorf("invalid operand type of fcmp instruction; expected floating-point or floating-point vector type, got %s", xt)
+	}
+	if !xt.Equal(yt) {
+		return fmt.Errorf("operand type mismatch of fcmp instruction; %s != %s", xt, yt)
+	}
+	return nil
+}
+
+// checkConversion type checks the source value and target type of the given
+// conversion instruction.
+func checkConversion(op string, from value.Value, to types.Type) error {
+	if to == nil {
+		return fmt.Errorf("invalid %s instruction; missing target type", op)
+	}
+	ft := from.Type()
+	invalid := func(expected string) error {
+		return fmt.Errorf("invalid %s from %s to %s; expected %s", op, ft, to, expected)
+	}
+	if op != "bitcast" {
+		fv, fok := ft.(*types.VectorType)
+		tv, tok := to.(*types.VectorType)
+		if fok != tok || (fok && (fv.Len != tv.Len || fv.Scalable != tv.Scalable)) {
+			return invalid("scalar types or vector types of equal length")
+		}
+	}
+	fs, ts := scalarType(ft), scalarType(to)
+	switch op {
+	case "trunc", "zext", "sext":
+		fi, fok := fs.(*types.IntType)
+		ti, tok := ts.(*types.IntType)
+		if !fok || !tok {
+			return invalid("integer types")
+		}
+		if op == "trunc" && fi.BitSize <= ti.BitSize {
+			return invalid("source type larger than target type")
+		}
+		if op != "trunc" && fi.BitSize >= ti.BitSize {
+			return invalid("source type smaller than target type")
+		}
+	case "fptrunc", "fpext":
+		fsize, tsize := floatBitSize(fs), floatBitSize(ts)
+		if fsize == 0 || tsize == 0 {
+			return invalid("floating-point types")
+		}
+		if op == "fptrunc" && fsize <= tsize {
+			return invalid("source type larger than target type")
+		}
+		if op == "fpext" && fsize >= tsize {
+			return invalid("source type smaller than target type")
+		}
+	case "fptoui", "fptosi":
+		if floatBitSize(fs) == 0 || !types.IsInt(ts) {
+			return invalid("floating-point source type and integer target type")
+		}
+	case "uitofp", "sitofp":
+		if !types.IsInt(fs) || floatBitSize(ts) == 0 {
+			return invalid("integer source type and floating-point target type")
+		}
+	case "ptrtoint":
+		if !types.IsPointer(fs) || !types.IsInt(ts) {
+			return invalid("pointer source type and integer target type")
+		}
+	case "inttoptr":
+		if !types.IsInt(fs) || !types.IsPointer(ts) {
+			return invalid("integer source type and pointer target type")
+		}
+	case "addrspacecast":
+		fp, fok := fs.(*types.PointerType)
+		tp, tok := ts.(*types.PointerType)
+		if !fok || !tok {
+			return invalid("pointer types")
+		}
+		if fp.AddrSpace == tp.AddrSpace {
+			return invalid("pointer types of different address spaces")
+		}
+	case "bitcast":
+		if isPtrOrPtrVector(ft) || isPtrOrPtrVector(to) {
+			fp, fok := fs.(*types.PointerType)
+			tp, tok := ts.(*types.PointerType)
+			if !fok || !tok || vectorLen(ft) != vectorLen(to) {
+				return invalid("pointer types or vectors of pointer types of equal length")
+			}
+			if fp.AddrSpace != tp.AddrSpace {
+				return invalid("pointer types of the same address space")
+			}
+			return nil
+		}
+		fsize, tsize := bitSize(ft), bitSize(to)
+		if fsize == 0 || tsize == 0 {
+			return invalid("non-aggregate first class types")
+		}
+		if fsize != tsize {
+			return invalid("types of equal size")
+		}
+	default:
+		panic(fmt.Errorf("support for conversion instruction %q not yet implemented", op))
+	}
+	return nil
+}
+
+// checkLoad type checks the source address of the given load instruction.
+func checkLoad(inst *InstLoad) error {
+	t, ok := inst.Src.Type().(*types.PointerType)
+	if !ok {
+		return fmt.Errorf("invalid source address type of load instruction; expected pointer type, got %s", inst.Src.Type())
+	}
+	if inst.Typ != nil && !inst.Typ.Equal(t.ElemType) {
+		return fmt.Errorf("load type mismatch; expected %s, got %s", t.ElemType, inst.Typ)
+	}
+	return nil
+}
+
+// checkStore type checks the destination address of the given store
+// instruction.
+func checkStore(inst *InstStore) error {
+	t, ok := inst.Dst.Type().(*types.PointerType)
+	if !ok {
+		return fmt.Errorf("invalid destination address type of store instruction; expected pointer type, got %s", inst.Dst.Type())
+	}
+	if !t.ElemType.Equal(inst.Src.Type()) {
+		return fmt.Errorf("store type mismatch; unable to store %s to %s", inst.Src.Type(), t)
+	}
+	return nil
+}
+
+// checkGetElementPtr type checks the source address and element indices of the
+// given getelementptr instruction.
+func checkGetElementPtr(inst *InstGetElementPtr) error {
+	src := inst.Src.Type()
+	t, ok := scalarType(src).(*types.PointerType)
+	if !ok {
+		return fmt.Errorf("invalid source address type of getelementptr instruction; expected pointer or vector of pointers type, got %s", src)
+	}
+	if inst.ElemType != nil && !inst.ElemType.Equal(t.ElemType) {
+		return fmt.Errorf("getelementptr element type mismatch; expected %s, got %s", t.ElemType, inst.ElemType)
+	}
+	e := t.ElemType
+	for i, index := range inst.Indices {
+		if !isIntOrIntVector(index.Type()) {
+			return fmt.Errorf("invalid index type of getelementptr instruction; expected integer or integer vector type, got %s", index.Type())
+		}
+		if i == 0 {
+			continue
+		}
+		switch t := e.(type) {
+		case *types.ArrayType:
+			e = t.ElemType
+		case *types.VectorType:
+			e = t.ElemType
+		case *types.StructType:
+			idx, ok := index.(*constant.Int)
+			if !ok {
+				return fmt.Errorf("invalid structure index of getelementptr instruction; expected integer constant, got %s", index.Ident())
+			}
+			if !idx.X.IsInt64() || idx.X.Int64() < 0 || idx.X.Int64() >= int64(len(t.Fields)) {
+				return fmt.Errorf("structure index %s of getelementptr instruction out of bounds for type %s", idx.X, t)
+			}
+			e = t.Fields[idx.X.Int64()]
+		default:
+			return fmt.Errorf("invalid getelementptr instruction; unable to index into element of type %s", e)
+		}
+	}
+	return nil
+}
+
+// checkCall type checks the given function arguments against the function
+// signature of the callee.
+func checkCall(callee value.Value, args []value.Value) error {
+	t, ok := callee.Type().(*types.PointerType)
+	if !ok {
+		return fmt.Errorf("invalid callee type; expected pointer to function type, got %s", callee.Type())
+	}
+	sig, ok := t.ElemType.(*types.FuncType)
+	if !ok {
+		return fmt.Errorf("invalid callee type; expected pointer to function type, got %s", t)
+	}
+	if len(args) < len(sig.Params) || (!sig.Variadic && len(args) > len(sig.Params)) {
+		return fmt.Errorf("argument count mismatch of call to %s; expected %d, got %d", callee.Ident(), len(sig.Params), len(args))
+	}
+	for i, param := range sig.Params {
+		if at := args[i].Type(); !at.Equal(param) {
+			return fmt.Errorf("type mismatch of argument %d of call to %s; expected %s, got %s", i, callee.Ident(), param, at)
+		}
+	}
+	return nil
+}
+
+// scalarType returns the element type of the given vector type, or the type
+// itself if not a vector type.
+func scalarType(t types.Type) types.Type {
+	if t, ok := t.(*types.VectorType); ok {
+		return t.ElemType
+	}
+	return t
+}
+
+// vectorLen returns the length of the given vector type, or 0 if not a vector
+// type.
+func vectorLen(t types.Type) uint64 {
+	if t, ok := t.(*types.VectorType); ok {
+		return t.Len
+	}
+	return 0
+}
+
+// isIntOrIntVector reports whether the given type is an integer type or a
+// vector of integers type.
+func isIntOrIntVector(t types.Type) bool {
+	return types.IsInt(scalarType(t))
+}
+
+// isFloatOrFloatVector reports whether the given type is a floating-point type
+// or a vector of floating-point type.
+func isFloatOrFloatVector(t types.Type) bool {
+	return types.IsFloat(scalarType(t))
+}
+
+// isPtrOrPtrVector reports whether the given type is a pointer type or a vector
+// of pointers type.
+func isPtrOrPtrVector(t types.Type) bool {
+	return types.IsPointer(scalarType(t))
+}
+
+// floatBitSize returns the size in bits of the given floating-point type, or 0
+// if not a floating-point type.
+func floatBitSize(t types.Type) int64 {
+	ft, ok := t.(*types.FloatType)
+	if !ok {
+		return 0
+	}
+	switch ft.Kind {
+	case types.FloatKindHalf, types.FloatKindBFloat:
+		return 16
+	case types.FloatKindFloat:
+		return 32
+	case types.FloatKindDouble:
+		return 64
+	case types.FloatKindX86FP80:
+		return 80
+	case types.FloatKindFP128, types.FloatKindPPCFP128:
+		return 128
+	default:
+		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", ft.Kind))
+	}
+}
+
+// bitSize returns the size in bits of the given non-aggregate first class type
+// (except pointer types), or 0 if the size is not known.
+func bitSize(t types.Type) int64 {
+	switch t := t.(type) {
+	case *types.IntType:
+		return int64(t.BitSize)
+	case *types.FloatType:
+		return floatBitSize(t)
+	case *types.MMXType:
+		return 64
+	case *types.VectorType:
+		return int64(t.Len) * bitSize(t.ElemType)
+	default:
+		return 0
+	}
+}
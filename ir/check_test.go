@@ -0,0 +1,105 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestCheckInst(t *testing.T) {
+	i32 := NewParam("i", types.I32)
+	i64 := NewParam("l", types.I64)
+	f64 := NewParam("d", types.Double)
+	p := NewParam("p", types.I32Ptr)
+	v := NewParam("v", types.NewVector(2, types.I32))
+	st := types.NewStruct(types.I32, types.Double)
+	ps := NewParam("s", types.NewPointer(st))
+	callee := NewFunc("f", types.Void, NewParam("x", types.I32))
+	variadic := NewFunc("printf", types.I32, NewParam("format", types.I8Ptr))
+	variadic.Sig.Variadic = true
+	format := NewParam("format", types.I8Ptr)
+	golden := []struct {
+		inst Instruction
+		ok   bool
+	}{
+		// Binary instructions.
+		{inst: &InstAdd{X: i32, Y: i32}, ok: true},
+		{inst: &InstAdd{X: v, Y: v}, ok: true},
+		{inst: &InstAdd{X: i32, Y: i64}, ok: false},
+		{inst: &InstAdd{X: f64, Y: f64}, ok: false},
+		{inst: &InstFMul{X: f64, Y: f64}, ok: true},
+		{inst: &InstFMul{X: i32, Y: i32}, ok: false},
+		// Bitwise instructions.
+		{inst: &InstShl{X: i64, Y: i64}, ok: true},
+		{inst: &InstXor{X: p, Y: p}, ok: false},
+		// Conversion instructions.
+		{inst: &InstTrunc{From: i64, To: types.I32}, ok: true},
+		{inst: &InstTrunc{From: i32, To: types.I64}, ok: false},
+		{inst: &InstZExt{From: i32, To: types.I64}, ok: true},
+		{inst: &InstZExt{From: v, To: types.I64}, ok: false},
+		{inst: &InstFPTrunc{From: f64, To: types.Float}, ok: true},
+		{inst: &InstSIToFP{From: i32, To: types.Double}, ok: true},
+		{inst: &InstFPToUI{From: i32, To: types.I32}, ok: false},
+		{inst: &InstPtrToInt{From: p, To: types.I64}, ok: true},
+		{inst: &InstIntToPtr{From: f64, To: types.I8Ptr}, ok: false},
+		{inst: &InstBitCast{From: f64, To: types.I64}, ok: true},
+		{inst: &InstBitCast{From: v, To: types.I64}, ok: true},
+		{inst: &InstBitCast{From: f64, To: types.I32}, ok: false},
+		{inst: &InstBitCast{From: p, To: types.I8Ptr}, ok: true},
+		{inst: &InstBitCast{From: p, To: types.I64}, ok: false},
+		{inst: &InstAddrSpaceCast{From: p, To: &types.PointerType{ElemType: types.I32, AddrSpace: 1}}, ok: true},
+		// Compare instructions.
+		{inst: &InstICmp{Pred: enum.IPredEQ, X: p, Y: p}, ok: true},
+		{inst: &InstICmp{Pred: enum.IPredEQ, X: i32, Y: i64}, ok: false},
+		{inst: &InstFCmp{Pred: enum.FPredOEQ, X: f64, Y: f64}, ok: true},
+		{inst: &InstFCmp{Pred: enum.FPredOEQ, X: i32, Y: i32}, ok: false},
+		// Memory instructions.
+		{inst: &InstLoad{Src: p}, ok: true},
+		{inst: &InstLoad{Src: i32}, ok: false},
+		{inst: &InstLoad{Typ: types.I64, Src: p}, ok: false},
+		{inst: &InstStore{Src: i32, Dst: p}, ok: true},
+		{inst: &InstStore{Src: i64, Dst: p}, ok: false},
+		{inst: &InstStore{Src: i32, Dst: i32}, ok: false},
+		{inst: &InstGetElementPtr{Src: ps, Indices: []value.Value{constant.NewInt(types.I64, 0), constant.NewInt(types.I32, 1)}}, ok: true},
+		{inst: &InstGetElementPtr{Src: ps, Indices: []value.Value{constant.NewInt(types.I64, 0), constant.NewInt(types.I32, 2)}}, ok: false},
+		{inst: &InstGetElementPtr{Src: ps, Indices: []value.Value{constant.NewInt(types.I64, 0), i32}}, ok: false},
+		{inst: &InstGetElementPtr{Src: i32, Indices: []value.Value{constant.NewInt(types.I64, 0)}}, ok: false},
+		// Call instructions.
+		{inst: &InstCall{Callee: callee, Args: []value.Value{i32}}, ok: true},
+		{inst: &InstCall{Callee: callee, Args: []value.Value{i64}}, ok: false},
+		{inst: &InstCall{Callee: callee}, ok: false},
+		{inst: &InstCall{Callee: variadic, Args: []value.Value{format, i32, f64}}, ok: true},
+		{inst: &InstCall{Callee: variadic}, ok: false},
+		{inst: &InstCall{Callee: i32}, ok: false},
+	}
+	for i, g := range golden {
+		err := CheckInst(g.inst)
+		if g.ok && err != nil {
+			t.Errorf("i=%d: unexpected error; %v", i, err)
+		}
+		if !g.ok && err == nil {
+			t.Errorf("i=%d: expected error for %T instruction, got nil", i, g.inst)
+		}
+	}
+}
+
+func TestNewChecked(t *testing.T) {
+	x := NewParam("x", types.I32)
+	y := NewParam("y", types.Double)
+	if _, err := NewAddChecked(x, y); err == nil {
+		t.Errorf("expected error for add of %s and %s, got nil", x.Type(), y.Type())
+	}
+	if _, err := NewLoadChecked(x); err == nil {
+		t.Errorf("expected error for load from %s, got nil", x.Type())
+	}
+	inst, err := NewSIToFPChecked(x, types.Double)
+	if err != nil {
+		t.Fatalf("unexpected error; %v", err)
+	}
+	if !inst.Type().Equal(types.Double) {
+		t.Errorf("type mismatch; expected %s, got %s", types.Double, inst.Type())
+	}
+}
@@ -0,0 +1,82 @@
+package ir
+
+import (
+	"strings"
+)
+
+// === [ Comments ] ============================================================
+
+// CommentMap maps from LLVM IR entities to their associated comments. The
+// following entities may have associated comments.
+//
+//    *ir.Module                    // comments at the start and end of the module
+//    *string                       // source filename, data layout, target triple and module-level inline assembly; identified by the address of the corresponding field of the module (e.g. &m.SourceFilename or &m.ModuleAsms[i])
+//    types.Type                    // type definitions
+//    *ir.ComdatDef                 // Comdat definitions
+//    *ir.Global                    // global variable declarations and definitions
+//    *ir.Alias                     // alias definitions
+//    *ir.IFunc                     // IFunc definitions
+//    *ir.Function                  // function declarations and definitions
+//    *ir.AttrGroupDef              // attribute group definitions
+//    *metadata.NamedMetadataDef    // named metadata definitions
+//    *metadata.MetadataDef         // metadata definitions
+//    *ir.BasicBlock                // basic blocks
+//    ir.Instruction                // instructions
+//    ir.Terminator                 // terminators
+type CommentMap map[interface{}]*Comments
+
+// Comments are the comments associated with an LLVM IR entity.
+type Comments struct {
+	// Comment lines preceding the entity, each including the ';' prefix.
+	Leading []string
+	// (optional) Comment following the entity on the same line, including the
+	// ';' prefix; or empty if not present. The trailing comment of a function
+	// definition follows its opening brace, and the trailing comment of a basic
+	// block follows its label.
+	Trailing string
+	// (optional) Comment lines at the end of a function body or module, each
+	// including the ';' prefix.
+	Final []string
+}
+
+// leading returns the leading comment lines of the given entity, or nil if not
+// present.
+func (c CommentMap) leading(n interface{}) []string {
+	if comments, ok := c[n]; ok {
+		return comments.Leading
+	}
+	return nil
+}
+
+// writeLeading writes the leading comment lines of the given entity to buf,
+// each prefixed by indent.
+func (c CommentMap) writeLeading(buf *strings.Builder, n interface{}, indent string) {
+	for _, line := range c.leading(n) {
+		buf.WriteString(indent)
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+}
+
+// trailing returns the trailing comment of the given entity preceded by a
+// space, or an empty string if not present.
+func (c CommentMap) trailing(n interface{}) string {
+	if comments, ok := c[n]; ok && len(comments.Trailing) > 0 {
+		return " " + comments.Trailing
+	}
+	return ""
+}
+
+// writeFinal writes the final comment lines of the given function or module to
+// buf, each prefixed by indent.
+func (c CommentMap) writeFinal(buf *strings.Builder, n interface{}, indent string) {
+	comments, ok := c[n]
+	if !ok {
+		return
+	}
+	for _, line := range comments.Final {
+		buf.WriteString(indent)
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+}
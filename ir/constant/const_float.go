@@ -2,16 +2,12 @@ package constant
 
 import (
 	"fmt"
-	"log"
 	"math"
 	"math/big"
 	"strconv"
 	"strings"
 
 	"github.com/llir/llvm/ir/types"
-	"github.com/mewmew/float"
-	"github.com/mewmew/float/binary16"
-	"github.com/mewmew/float/float80x86"
 	"github.com/pkg/errors"
 )
 
@@ -21,18 +17,33 @@ import (
 type Float struct {
 	// Floating-point type.
 	Typ *types.FloatType
-	// Floating-point constant.
+	// Floating-point constant. The sign of a NaN is stored as the sign of X.
 	X *big.Float
 	// NaN specifies whether the floating-point constant is Not-a-Number.
 	NaN bool
+	// NaN payload; the trailing significand bits of the NaN (including the
+	// quiet bit), or nil to use the default quiet NaN of the floating-point
+	// type.
+	Payload *big.Int
+
+	// Bit pattern of x86_fp80 and ppc_fp128 hexadecimal literals; retained to
+	// print non-canonical encodings (e.g. unnormals and overlapping
+	// double-double pairs) exactly. Ignored if X, NaN or Payload no longer
+	// match the bit pattern.
+	raw *big.Int
 }
 
 // NewFloat returns a new floating-point constant based on the given
 // floating-point type and double precision floating-point value.
 func NewFloat(typ *types.FloatType, x float64) *Float {
 	if math.IsNaN(x) {
-		// TODO: store sign of NaN?
-		return &Float{Typ: typ, NaN: true}
+		payload := new(big.Int).SetUint64(math.Float64bits(x) & (1<<52 - 1))
+		return &Float{
+			Typ:     typ,
+			X:       big.NewFloat(math.Copysign(0, x)),
+			NaN:     true,
+			Payload: convertNaNPayload(payload, formatDouble, formatOf(typ.Kind)),
+		}
 	}
 	return &Float{Typ: typ, X: big.NewFloat(x)}
 }
@@ -52,151 +63,20 @@ func NewFloat(typ *types.FloatType, x float64) *Float {
 //         0xL[0-9A-Fa-f]{32} // HexFP128
 //         0xM[0-9A-Fa-f]{32} // HexPPC128
 //         0xH[0-9A-Fa-f]{4}  // HexHalf
+//         0xR[0-9A-Fa-f]{4}  // HexBFloat
+//
+// As in LLVM, decimal and HexFP literals denote double precision values, which
+// are converted (with rounding to nearest, ties to even) to the given
+// floating-point type.
 func NewFloatFromString(typ *types.FloatType, s string) (*Float, error) {
-	// TODO: implement NewFloatFromString. return 0 for now.
 	if strings.HasPrefix(s, "0x") {
-		switch {
-		case strings.HasPrefix(s, "0xK"):
-			hex := s[len("0xK"):]
-			part1 := hex[:4]
-			part2 := hex[4:]
-			se, err := strconv.ParseUint(part1, 16, 16)
-			if err != nil {
-				return nil, errors.WithStack(err)
-			}
-			m, err := strconv.ParseUint(part2, 16, 64)
-			if err != nil {
-				return nil, errors.WithStack(err)
-			}
-			f := float80x86.NewFromBits(uint16(se), m)
-			x, nan := f.Big()
-			return &Float{
-				Typ: typ,
-				X:   x,
-				NaN: nan,
-			}, nil
-		case strings.HasPrefix(s, "0xL"):
-			//s = s[len("0xL"):]
-		case strings.HasPrefix(s, "0xM"):
-			//s = s[len("0xM"):]
-		case strings.HasPrefix(s, "0xH"):
-			hex := s[len("0xK"):]
-			bits, err := strconv.ParseUint(hex, 16, 16)
-			if err != nil {
-				return nil, errors.WithStack(err)
-			}
-			f := binary16.NewFromBits(uint16(bits))
-			x, nan := f.Big()
-			return &Float{
-				Typ: typ,
-				X:   x,
-				NaN: nan,
-			}, nil
-		default:
-			hex := s[len("0x"):]
-			bits, err := strconv.ParseUint(hex, 16, 64)
-			if err != nil {
-				return nil, errors.WithStack(err)
-			}
-			switch typ.Kind {
-			case types.FloatKindHalf:
-				// TODO: verify if this is a correct implementation. We should
-				// probably be using binary16.NewFromBits.
-				f := math.Float64frombits(bits)
-				if math.IsNaN(f) {
-					return &Float{
-						Typ: typ,
-						NaN: true,
-					}, nil
-				}
-				c := big.NewFloat(f)
-				const precision = 11
-				c.SetPrec(precision)
-				return &Float{
-					Typ: typ,
-					X:   c,
-				}, nil
-			case types.FloatKindFloat:
-				// ref: https://groups.google.com/d/msg/llvm-dev/IlqV3TbSk6M/27dAggZOMb0J
-				//
-				// The exact bit representation of the float is laid out with the
-				// corresponding bitwise representation of a double:  the sign bit is
-				// copied over, the exponent is encoded in the larger width, and the 23
-				// bits of significand fills in the top 23 bits of significand in the
-				// double.  A double has 52 bits of significand, so this means that the
-				// last 29 bits of significand will always be ignored.  As an
-				// error-detection measure, the IR parser requires them to be zero.
-				f := math.Float64frombits(bits)
-				if math.IsNaN(f) {
-					return &Float{
-						Typ: typ,
-						NaN: true,
-					}, nil
-				}
-				c := big.NewFloat(f)
-				const precision = 24
-				c.SetPrec(precision)
-				return &Float{
-					Typ: typ,
-					X:   c,
-				}, nil
-			case types.FloatKindDouble:
-				f := math.Float64frombits(bits)
-				if math.IsNaN(f) {
-					return &Float{
-						Typ: typ,
-						NaN: true,
-					}, nil
-				}
-				return &Float{
-					Typ: typ,
-					X:   big.NewFloat(f),
-				}, nil
-			default:
-				panic(fmt.Errorf("support for hexadecimal floating-point literal %q of kind %v not yet implemented", s, typ.Kind))
-			}
-		}
-		log.Printf("constant.NewFloatFromString(%q): not yet implemented", s)
-		return NewFloat(typ, 0), nil
-		//panic(fmt.Errorf("support for hexadecimal floating-point constant %q not yet implemented", s))
+		return newFloatFromHex(typ, s)
 	}
-	switch typ.Kind {
-	case types.FloatKindHalf:
-		const precision = 11
-		x, _, err := big.ParseFloat(s, 10, precision, big.ToNearestEven)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		c := &Float{
-			Typ: typ,
-			X:   x,
-		}
-		return c, nil
-	case types.FloatKindFloat:
-		const precision = 24
-		x, _, err := big.ParseFloat(s, 10, precision, big.ToNearestEven)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		c := &Float{
-			Typ: typ,
-			X:   x,
-		}
-		return c, nil
-	case types.FloatKindDouble:
-		const precision = 53
-		x, _, err := big.ParseFloat(s, 10, precision, big.ToNearestEven)
-		if err != nil {
-			return nil, errors.WithStack(err)
-		}
-		c := &Float{
-			Typ: typ,
-			X:   x,
-		}
-		return c, nil
-	default:
-		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", typ.Kind))
+	x, err := parseDecimal(s, formatDouble)
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
+	return &Float{Typ: typ, X: roundFloat(typ.Kind, x)}, nil
 }
 
 // String returns the LLVM syntax representation of the constant as a type-value
@@ -213,115 +93,310 @@ func (c *Float) Type() types.Type {
 // Ident returns the identifier associated with the constant.
 func (c *Float) Ident() string {
 	// FloatLit
-	// TODO: add support for hexadecimal format.
-	// TODO: add support for NaN, +-Inf.
-
 	switch c.Typ.Kind {
-	case types.FloatKindHalf:
-		if c.NaN || c.X.IsInf() || !float.IsExact16(c.X) {
-			var bits uint16
-			if c.NaN {
-				if c.X.Signbit() {
-					bits = binary16.NegNaN.Bits()
-				} else {
-					bits = binary16.NaN.Bits()
+	case types.FloatKindHalf, types.FloatKindBFloat, types.FloatKindFloat, types.FloatKindDouble:
+		// Use decimal notation for finite values which may be represented
+		// exactly in decimal notation.
+		if !c.NaN {
+			x := roundFloat(c.Typ.Kind, c.X)
+			if !x.IsInf() {
+				if s, ok := exactDecimal(x); ok {
+					return s
 				}
-			} else {
-				f, acc := binary16.NewFromBig(c.X)
-				// TODO: check acc.
-				_ = acc
-				bits = f.Bits()
 			}
-			return fmt.Sprintf("0xH%04X", bits)
 		}
+	}
+	return c.hex()
+}
+
+// hex returns the hexadecimal floating-point literal of the constant.
+func (c *Float) hex() string {
+	const mask64 = 1<<64 - 1
+	switch c.Typ.Kind {
+	case types.FloatKindHalf:
+		// 0xH[0-9A-F]{4}
+		return fmt.Sprintf("0xH%04X", c.bits())
+	case types.FloatKindBFloat:
+		// 0xR[0-9A-F]{4}
+		return fmt.Sprintf("0xR%04X", c.bits())
 	case types.FloatKindFloat:
-		// ref: https://groups.google.com/d/msg/llvm-dev/IlqV3TbSk6M/27dAggZOMb0J
-		//
-		// The exact bit representation of the float is laid out with the
-		// corresponding bitwise representation of a double:  the sign bit is
-		// copied over, the exponent is encoded in the larger width, and the 23
-		// bits of significand fills in the top 23 bits of significand in the
-		// double.  A double has 52 bits of significand, so this means that the
-		// last 29 bits of significand will always be ignored.  As an
-		// error-detection measure, the IR parser requires them to be zero.
-		if c.NaN || c.X.IsInf() || !float.IsExact32(c.X) {
-			// Single precision.
-			//
-			//     1 bit:  sign
-			//     8 bits: exponent
-			//    23 bits: mantissa
-			//
-			//    bias: 127
-			var bits32 uint32
-			if c.NaN {
-				// TODO: handle sign bit.
-				bits32 = 0xFFFFFFFF
-			} else {
-				f, _ := c.X.Float32()
-				bits32 = math.Float32bits(f)
-			}
-			// 0b10000000000000000000000000000000
-			sign := uint64(bits32 & 0x80000000 >> 31)
-			// 0b01111111100000000000000000000000
-			const bias32 = 127
-			exp32 := (bits32 & 0x7F800000 >> 23)
-			// 0b00000000011111111111111111111111
-			mant := uint64(bits32 & 0x7FFFFF)
-			// Double precision.
-			//
-			//     1 bit:  sign
-			//    11 bits: exponent
-			//    52 bits: mantissa
-			//
-			//    bias: 1023
-			var bits64 uint64
-			bits64 |= sign << 63
-			const bias64 = 1023
-			var exp64 uint64
-			if exp32 == 0xFF {
-				// Keep every bit set in the exponent if such was the case for
-				// float32.
-				exp64 = 0x7FF
-			} else {
-				exp := uint64(exp32 - bias32)
-				exp64 = exp + bias64
-			}
-			bits64 |= exp64 << 52
-			bits64 |= mant << (52 - 23)
-			return fmt.Sprintf("0x%016X", bits64)
+		// Single precision floating-point values are represented by the
+		// corresponding double precision value in hexadecimal notation.
+		var bits *big.Int
+		if c.NaN {
+			payload := convertNaNPayload(c.Payload, formatFloat, formatDouble)
+			bits = formatDouble.nanBits(c.neg(), payload)
+		} else {
+			bits = formatDouble.encode(roundFloat(c.Typ.Kind, c.X))
 		}
+		// Note, to match Clang output we do not zero-pad the hexadecimal
+		// output.
+		return fmt.Sprintf("0x%X", bits)
 	case types.FloatKindDouble:
-		if c.NaN {
-			f := math.NaN()
-			if c.X != nil && c.X.Signbit() {
-				f = math.Copysign(f, -1)
+		// Note, to match Clang output we do not zero-pad the hexadecimal
+		// output.
+		return fmt.Sprintf("0x%X", c.bits())
+	case types.FloatKindX86FP80:
+		// 0xK[0-9A-F]{20}
+		//
+		// Sign and exponent followed by the significand.
+		bits := c.bits()
+		se := new(big.Int).Rsh(bits, 64)
+		m := new(big.Int).And(bits, new(big.Int).SetUint64(mask64))
+		return fmt.Sprintf("0xK%04X%016X", se, m)
+	case types.FloatKindFP128, types.FloatKindPPCFP128:
+		// 0xL[0-9A-F]{32}
+		// 0xM[0-9A-F]{32}
+		//
+		// Low-order 64 bits followed by the high-order 64 bits. The low-order
+		// 64 bits of a ppc_fp128 hold the high-order double.
+		bits := c.bits()
+		hi := new(big.Int).Rsh(bits, 64)
+		lo := new(big.Int).And(bits, new(big.Int).SetUint64(mask64))
+		prefix := "0xL"
+		if c.Typ.Kind == types.FloatKindPPCFP128 {
+			prefix = "0xM"
+		}
+		return fmt.Sprintf("%s%016X%016X", prefix, lo, hi)
+	default:
+		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", c.Typ.Kind))
+	}
+}
+
+// bits returns the bit pattern of the floating-point constant, as encoded by
+// the binary format of its floating-point type. The bit pattern of a ppc_fp128
+// holds the high-order double in the low-order 64 bits.
+func (c *Float) bits() *big.Int {
+	if raw, ok := c.rawBits(); ok {
+		return new(big.Int).Set(raw)
+	}
+	f := formatOf(c.Typ.Kind)
+	if c.Typ.Kind == types.FloatKindPPCFP128 {
+		var hi, lo *big.Int
+		switch {
+		case c.NaN:
+			hi, lo = f.nanBits(c.neg(), c.Payload), new(big.Int)
+		default:
+			hi, lo = ppcSplit(c.X)
+		}
+		return lo.Lsh(lo, 64).Or(lo, hi)
+	}
+	if c.NaN {
+		return f.nanBits(c.neg(), c.Payload)
+	}
+	return f.encode(c.X)
+}
+
+// rawBits returns the bit pattern of the hexadecimal literal from which the
+// floating-point constant was parsed, and reports whether it still denotes the
+// value of the constant.
+func (c *Float) rawBits() (*big.Int, bool) {
+	if c.raw == nil || c.X == nil {
+		return nil, false
+	}
+	d := newFloatFromBits(c.Typ, c.raw)
+	if d.NaN != c.NaN || d.neg() != c.neg() {
+		return nil, false
+	}
+	if c.NaN {
+		if d.Payload == nil || c.Payload == nil || d.Payload.Cmp(c.Payload) != 0 {
+			return nil, false
+		}
+		return c.raw, true
+	}
+	if d.X.Cmp(c.X) != 0 {
+		return nil, false
+	}
+	return c.raw, true
+}
+
+// neg reports whether the sign bit of the floating-point constant is set.
+func (c *Float) neg() bool {
+	return c.X != nil && c.X.Signbit()
+}
+
+// newFloatFromHex returns a new floating-point constant based on the given
+// floating-point type and hexadecimal floating-point literal.
+func newFloatFromHex(typ *types.FloatType, s string) (*Float, error) {
+	hex := s[len("0x"):]
+	digits := hex
+	if len(hex) > 0 && strings.IndexByte("KLMHR", hex[0]) != -1 {
+		digits = hex[1:]
+	}
+	if len(digits) == 0 {
+		return nil, errors.Errorf("invalid hexadecimal floating-point literal %q; missing hexadecimal digits", s)
+	}
+	var want types.FloatKind
+	var bits *big.Int
+	switch {
+	case strings.HasPrefix(hex, "K"):
+		// Sign and exponent in the first 4 hex digits, significand in the
+		// remaining 16 hex digits.
+		want = types.FloatKindX86FP80
+		hex = hex[len("K"):]
+		n := 4
+		if len(hex) < n {
+			n = len(hex)
+		}
+		se, err := parseHex(hex[:n], 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hexadecimal floating-point literal %q", s)
+		}
+		m, err := parseHex(hex[n:], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hexadecimal floating-point literal %q", s)
+		}
+		bits = new(big.Int).SetUint64(se)
+		bits.Lsh(bits, 64).Or(bits, new(big.Int).SetUint64(m))
+	case strings.HasPrefix(hex, "L"), strings.HasPrefix(hex, "M"):
+		// Low-order 64 bits in the first 16 hex digits, high-order 64 bits in
+		// the remaining 16 hex digits.
+		want = types.FloatKindFP128
+		if hex[0] == 'M' {
+			want = types.FloatKindPPCFP128
+		}
+		hex = hex[len("L"):]
+		n := 16
+		if len(hex) < n {
+			n = len(hex)
+		}
+		lo, err := parseHex(hex[:n], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hexadecimal floating-point literal %q", s)
+		}
+		hi, err := parseHex(hex[n:], 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hexadecimal floating-point literal %q", s)
+		}
+		bits = new(big.Int).SetUint64(hi)
+		bits.Lsh(bits, 64).Or(bits, new(big.Int).SetUint64(lo))
+	case strings.HasPrefix(hex, "H"), strings.HasPrefix(hex, "R"):
+		want = types.FloatKindHalf
+		if hex[0] == 'R' {
+			want = types.FloatKindBFloat
+		}
+		x, err := parseHex(hex[len("H"):], 16)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hexadecimal floating-point literal %q", s)
+		}
+		bits = new(big.Int).SetUint64(x)
+	default:
+		// HexFP literals denote double precision values, which are converted
+		// to the floating-point type.
+		x, err := parseHex(hex, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid hexadecimal floating-point literal %q", s)
+		}
+		v, nan, payload := formatDouble.decode(new(big.Int).SetUint64(x))
+		if nan {
+			c := &Float{
+				Typ:     typ,
+				X:       v,
+				NaN:     true,
+				Payload: convertNaNPayload(payload, formatDouble, formatOf(typ.Kind)),
 			}
-			bits := math.Float64bits(f)
-			return fmt.Sprintf("0x%X", bits)
+			return c, nil
+		}
+		return &Float{Typ: typ, X: roundFloat(typ.Kind, v)}, nil
+	}
+	if typ.Kind != want {
+		return nil, errors.Errorf("invalid hexadecimal floating-point literal %q for floating-point type %v; expected %v", s, typ.Kind, want)
+	}
+	c := newFloatFromBits(typ, bits)
+	switch typ.Kind {
+	case types.FloatKindX86FP80, types.FloatKindPPCFP128:
+		// Retain bit pattern, as the encoding of x86_fp80 and ppc_fp128 values
+		// is not unique.
+		c.raw = bits
+	}
+	return c, nil
+}
+
+// newFloatFromBits returns a new floating-point constant based on the given
+// floating-point type and bit pattern, as encoded by the binary format of the
+// floating-point type.
+func newFloatFromBits(typ *types.FloatType, bits *big.Int) *Float {
+	if typ.Kind == types.FloatKindPPCFP128 {
+		return newPPCFloatFromBits(typ, bits)
+	}
+	x, nan, payload := formatOf(typ.Kind).decode(bits)
+	c := &Float{Typ: typ, X: x, NaN: nan}
+	if nan {
+		c.Payload = payload
+	}
+	return c
+}
+
+// newPPCFloatFromBits returns a new ppc_fp128 floating-point constant based on
+// the given bit pattern, the low-order 64 bits of which hold the high-order
+// double.
+func newPPCFloatFromBits(typ *types.FloatType, bits *big.Int) *Float {
+	const mask64 = 1<<64 - 1
+	hi := new(big.Int).And(bits, new(big.Int).SetUint64(mask64))
+	lo := new(big.Int).Rsh(bits, 64)
+	x, nan, payload := formatDouble.decode(hi)
+	if nan {
+		return &Float{Typ: typ, X: x, NaN: true, Payload: payload}
+	}
+	if x.IsInf() {
+		return &Float{Typ: typ, X: x}
+	}
+	y, nan, _ := formatDouble.decode(lo)
+	if nan || y.IsInf() || y.Sign() == 0 {
+		return &Float{Typ: typ, X: x.SetPrec(2 * formatDouble.prec())}
+	}
+	// Use sufficient precision to represent the sum exactly.
+	prec := 2 * formatDouble.prec()
+	if x.Sign() != 0 {
+		d := x.MantExp(nil) - y.MantExp(nil)
+		if d < 0 {
+			d = -d
 		}
-		if c.X.IsInf() || !float.IsExact64(c.X) {
-			f, _ := c.X.Float64()
-			bits := math.Float64bits(f)
-			// Note, to match Clang output we do not zero-pad the hexadecimal
-			// output.
-			return fmt.Sprintf("0x%X", bits)
-			//return fmt.Sprintf("0x%016X", bits)
+		if n := uint(d) + formatDouble.prec(); n > prec {
+			prec = n
 		}
-	case types.FloatKindX86FP80:
-		// TODO: handle NaN.
-		f, acc := float80x86.NewFromBig(c.X)
-		// TODO: check acc.
-		_ = acc
-		se, m := f.Bits()
-		return fmt.Sprintf("0xK%04X%016X", se, m)
-		//case types.FloatKindFP128:
-		//case types.FloatKindPPCFP128:
 	}
+	sum := new(big.Float).SetPrec(prec).Add(x, y)
+	return &Float{Typ: typ, X: sum}
+}
+
+// ppcSplit splits the given value into the high- and low-order doubles of a
+// ppc_fp128 value, and returns their bit patterns.
+func ppcSplit(x *big.Float) (hi, lo *big.Int) {
+	hi = formatDouble.encode(x)
+	h, _, _ := formatDouble.decode(hi)
+	if h.IsInf() || x.Sign() == 0 {
+		return hi, new(big.Int)
+	}
+	l := new(big.Float).SetPrec(x.Prec()+formatDouble.prec()).Sub(x, h)
+	return hi, formatDouble.encode(l)
+}
 
+// roundFloat returns x rounded to the nearest representable value of the given
+// floating-point kind, with ties to even.
+func roundFloat(kind types.FloatKind, x *big.Float) *big.Float {
+	if kind == types.FloatKindPPCFP128 {
+		hi, lo := ppcSplit(x)
+		bits := lo.Lsh(lo, 64).Or(lo, hi)
+		return newPPCFloatFromBits(types.PPCFP128, bits).X
+	}
+	f := formatOf(kind)
+	y, _, _ := f.decode(f.encode(x))
+	return y
+}
+
+// exactDecimal returns the shortest decimal representation of x, and reports
+// whether it represents x exactly.
+func exactDecimal(x *big.Float) (string, bool) {
+	s := x.Text('g', -1)
+	want, _ := x.Rat(nil)
+	got, ok := new(big.Rat).SetString(s)
+	if !ok || got.Cmp(want) != 0 {
+		return "", false
+	}
 	// Insert decimal point if not present.
 	//    3e4 -> 3.0e4
 	//    42  -> 42.0
-	s := c.X.Text('g', -1)
 	if !strings.ContainsRune(s, '.') {
 		if pos := strings.IndexByte(s, 'e'); pos != -1 {
 			s = s[:pos] + ".0" + s[pos:]
@@ -329,5 +404,263 @@ func (c *Float) Ident() string {
 			s += ".0"
 		}
 	}
-	return s
+	return s, true
+}
+
+// parseDecimal parses the given decimal floating-point literal, rounded to the
+// nearest representable value of the given binary format.
+func parseDecimal(s string, f floatFormat) (*big.Float, error) {
+	x, _, err := big.ParseFloat(s, 10, f.prec(), big.ToNearestEven)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Subnormal values have less precision; parse again with the precision of
+	// the subnormal to prevent double rounding.
+	if x.Sign() != 0 && !x.IsInf() {
+		minExp := 2 - f.bias()
+		if exp := x.MantExp(nil); exp < minExp {
+			if prec := int(f.prec()) - (minExp - exp); prec > 0 {
+				x, _, err = big.ParseFloat(s, 10, uint(prec), big.ToNearestEven)
+				if err != nil {
+					return nil, errors.WithStack(err)
+				}
+			}
+		}
+	}
+	y, _, _ := f.decode(f.encode(x))
+	return y, nil
+}
+
+// parseHex parses the given hexadecimal string as an unsigned integer of the
+// specified bit size. The empty string is parsed as zero.
+func parseHex(s string, bitSize int) (uint64, error) {
+	if len(s) == 0 {
+		return 0, nil
+	}
+	x, err := strconv.ParseUint(s, 16, bitSize)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return x, nil
+}
+
+// ~~~ [ Binary floating-point formats ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
+
+// floatFormat is a binary floating-point format.
+type floatFormat struct {
+	// Number of exponent bits.
+	expBits uint
+	// Number of trailing significand bits, excluding the leading integer bit.
+	fracBits uint
+	// The leading integer bit of the significand is stored explicitly.
+	explicitLead bool
+}
+
+// Binary floating-point formats.
+var (
+	formatHalf    = floatFormat{expBits: 5, fracBits: 10}
+	formatBFloat  = floatFormat{expBits: 8, fracBits: 7}
+	formatFloat   = floatFormat{expBits: 8, fracBits: 23}
+	formatDouble  = floatFormat{expBits: 11, fracBits: 52}
+	formatX86FP80 = floatFormat{expBits: 15, fracBits: 63, explicitLead: true}
+	formatFP128   = floatFormat{expBits: 15, fracBits: 112}
+)
+
+// formatOf returns the binary format of the given floating-point kind. The
+// format of ppc_fp128 is the format of its high-order double.
+func formatOf(kind types.FloatKind) floatFormat {
+	switch kind {
+	case types.FloatKindHalf:
+		return formatHalf
+	case types.FloatKindBFloat:
+		return formatBFloat
+	case types.FloatKindFloat:
+		return formatFloat
+	case types.FloatKindDouble, types.FloatKindPPCFP128:
+		return formatDouble
+	case types.FloatKindX86FP80:
+		return formatX86FP80
+	case types.FloatKindFP128:
+		return formatFP128
+	default:
+		panic(fmt.Errorf("support for floating-point kind %v not yet implemented", kind))
+	}
+}
+
+// prec returns the precision in bits of the significand.
+func (f floatFormat) prec() uint {
+	return f.fracBits + 1
+}
+
+// bias returns the exponent bias.
+func (f floatFormat) bias() int {
+	return 1<<(f.expBits-1) - 1
+}
+
+// maxExp returns the biased exponent of infinities and NaNs.
+func (f floatFormat) maxExp() uint64 {
+	return 1<<f.expBits - 1
+}
+
+// sigBits returns the number of bits used to store the significand.
+func (f floatFormat) sigBits() uint {
+	if f.explicitLead {
+		return f.fracBits + 1
+	}
+	return f.fracBits
+}
+
+// signBit returns the bit position of the sign bit.
+func (f floatFormat) signBit() int {
+	return int(f.expBits + f.sigBits())
+}
+
+// decode returns the value of the given bit pattern. If the bit pattern is a
+// NaN, the returned value is a signed zero and payload holds the trailing
+// significand bits of the NaN.
+func (f floatFormat) decode(bits *big.Int) (x *big.Float, nan bool, payload *big.Int) {
+	frac := new(big.Int).And(bits, mask(f.fracBits))
+	sig := new(big.Int).And(bits, mask(f.sigBits()))
+	exp := new(big.Int).Rsh(bits, f.sigBits())
+	e := exp.And(exp, mask(f.expBits)).Uint64()
+	neg := bits.Bit(f.signBit()) == 1
+	x = new(big.Float).SetPrec(f.prec())
+	switch {
+	case e == f.maxExp() && frac.Sign() == 0:
+		return x.SetInf(neg), false, nil
+	case e == f.maxExp():
+		if neg {
+			x.Neg(x)
+		}
+		return x, true, frac
+	case e == 0:
+		// Zero and subnormal numbers share the exponent of the smallest normal
+		// number.
+		e = 1
+	default:
+		if !f.explicitLead {
+			sig.SetBit(sig, int(f.fracBits), 1)
+		}
+	}
+	x.SetInt(sig)
+	x.SetMantExp(x, int(e)-f.bias()-int(f.fracBits))
+	if neg {
+		x.Neg(x)
+	}
+	return x, false, nil
+}
+
+// encode returns the bit pattern of x rounded to the nearest representable
+// value, with ties to even. Values of too large magnitude are rounded to
+// infinity.
+func (f floatFormat) encode(x *big.Float) *big.Int {
+	bits := new(big.Int)
+	if x.Signbit() {
+		bits.SetBit(bits, f.signBit(), 1)
+	}
+	if x.Sign() == 0 {
+		return bits
+	}
+	if x.IsInf() {
+		return bits.Or(bits, f.infBits())
+	}
+	abs := new(big.Float).Abs(x)
+	var e int
+	var sig *big.Int
+	if exp := abs.MantExp(nil); exp-1+f.bias() >= 1 {
+		// Normal number.
+		r := new(big.Float).SetPrec(f.prec()).SetMode(big.ToNearestEven).Set(abs)
+		exp = r.MantExp(nil)
+		e = exp - 1 + f.bias()
+		if uint64(e) >= f.maxExp() {
+			return bits.Or(bits, f.infBits())
+		}
+		r.SetMantExp(r, int(f.fracBits)-(exp-1))
+		sig, _ = r.Int(nil)
+		if !f.explicitLead {
+			sig.SetBit(sig, int(f.fracBits), 0)
+		}
+	} else {
+		// Subnormal number; round to a multiple of the smallest subnormal.
+		r := new(big.Float).SetMantExp(abs, int(f.fracBits)+f.bias()-1)
+		sig = roundToEven(r)
+		if sig.BitLen() > int(f.fracBits) {
+			// Rounded up to the smallest normal number.
+			e = 1
+			if !f.explicitLead {
+				sig.SetBit(sig, int(f.fracBits), 0)
+			}
+		}
+	}
+	bits.Or(bits, new(big.Int).Lsh(big.NewInt(int64(e)), f.sigBits()))
+	return bits.Or(bits, sig)
+}
+
+// infBits returns the bit pattern of positive infinity.
+func (f floatFormat) infBits() *big.Int {
+	bits := new(big.Int).SetUint64(f.maxExp())
+	bits.Lsh(bits, f.sigBits())
+	if f.explicitLead {
+		bits.SetBit(bits, int(f.fracBits), 1)
+	}
+	return bits
+}
+
+// nanBits returns the bit pattern of the NaN with the given sign and payload.
+// The default quiet NaN is used if payload is nil.
+func (f floatFormat) nanBits(neg bool, payload *big.Int) *big.Int {
+	bits := f.infBits()
+	if neg {
+		bits.SetBit(bits, f.signBit(), 1)
+	}
+	if payload == nil {
+		// Quiet bit.
+		return bits.SetBit(bits, int(f.fracBits-1), 1)
+	}
+	return bits.Or(bits, new(big.Int).And(payload, mask(f.fracBits)))
+}
+
+// convertNaNPayload converts the given NaN payload from one binary format to
+// another, by truncating or extending the payload in its least significant
+// bits. The quiet bit is retained, and signaling NaNs remain signaling.
+func convertNaNPayload(payload *big.Int, from, to floatFormat) *big.Int {
+	if payload == nil {
+		return nil
+	}
+	p := new(big.Int)
+	if to.fracBits >= from.fracBits {
+		p.Lsh(payload, to.fracBits-from.fracBits)
+	} else {
+		p.Rsh(payload, from.fracBits-to.fracBits)
+	}
+	if p.Sign() == 0 {
+		// Prevent a truncated signaling NaN from turning into infinity.
+		p.SetBit(p, int(to.fracBits-2), 1)
+	}
+	return p
+}
+
+// roundToEven returns the non-negative value x rounded to the nearest integer,
+// with ties to even.
+func roundToEven(x *big.Float) *big.Int {
+	i, acc := x.Int(nil)
+	if acc == big.Exact {
+		return i
+	}
+	frac := new(big.Float).Sub(x, new(big.Float).SetInt(i))
+	switch frac.Cmp(big.NewFloat(0.5)) {
+	case 1:
+		i.Add(i, big.NewInt(1))
+	case 0:
+		if i.Bit(0) == 1 {
+			i.Add(i, big.NewInt(1))
+		}
+	}
+	return i
+}
+
+// mask returns a bit mask of the n least significant bits.
+func mask(n uint) *big.Int {
+	m := new(big.Int).Lsh(big.NewInt(1), n)
+	return m.Sub(m, big.NewInt(1))
 }
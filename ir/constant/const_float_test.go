@@ -0,0 +1,124 @@
+package constant
+
+import (
+	"math"
+	"testing"
+
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestFloatIdent(t *testing.T) {
+	golden := []struct {
+		typ  *types.FloatType
+		s    string
+		want string
+	}{
+		// half
+		{typ: types.Half, s: "0xH4400", want: "4.0"},
+		{typ: types.Half, s: "0xH2E66", want: "0xH2E66"},
+		{typ: types.Half, s: "0xHFD01", want: "0xHFD01"},
+		{typ: types.Half, s: "1.0e+10", want: "0xH7C00"},
+		// bfloat
+		{typ: types.BFloat, s: "0xR3F80", want: "1.0"},
+		{typ: types.BFloat, s: "0xR3DCD", want: "0xR3DCD"},
+		{typ: types.BFloat, s: "0xRFFC1", want: "0xRFFC1"},
+		{typ: types.BFloat, s: "0.1", want: "0xR3DCD"},
+		// float
+		{typ: types.Float, s: "0.1", want: "0x3FB99999A0000000"},
+		{typ: types.Float, s: "0xFFF4000000000000", want: "0xFFF4000000000000"},
+		{typ: types.Float, s: "0x36A0000000000000", want: "0x36A0000000000000"},
+		// double
+		{typ: types.Double, s: "42.0", want: "42.0"},
+		{typ: types.Double, s: "3e4", want: "30000.0"},
+		{typ: types.Double, s: "0xFFF0000000000001", want: "0xFFF0000000000001"},
+		{typ: types.Double, s: "4.9406564584124654e-324", want: "0x1"},
+		// x86_fp80
+		{typ: types.X86FP80, s: "0xKFFFF8000000000000000", want: "0xKFFFF8000000000000000"},
+		{typ: types.X86FP80, s: "0xK7FFFC000000000000123", want: "0xK7FFFC000000000000123"},
+		{typ: types.X86FP80, s: "1.5", want: "0xK3FFFC000000000000000"},
+		// x86_fp80 unnormal, pseudo-denormal, pseudo-infinity and pseudo-NaN
+		{typ: types.X86FP80, s: "0xK40000000000000000000", want: "0xK40000000000000000000"},
+		{typ: types.X86FP80, s: "0xK00008000000000000001", want: "0xK00008000000000000001"},
+		{typ: types.X86FP80, s: "0xK7FFF0000000000000000", want: "0xK7FFF0000000000000000"},
+		{typ: types.X86FP80, s: "0xKFFFF4000000000000000", want: "0xKFFFF4000000000000000"},
+		// fp128
+		{typ: types.FP128, s: "0xL00000000000000004000800000000000", want: "0xL00000000000000004000800000000000"},
+		{typ: types.FP128, s: "0xL0000000000000001FFFF000000000000", want: "0xL0000000000000001FFFF000000000000"},
+		// ppc_fp128
+		{typ: types.PPCFP128, s: "0xM3FF0000000000000BC90000000000000", want: "0xM3FF0000000000000BC90000000000000"},
+		{typ: types.PPCFP128, s: "0xM80000000000000000000000000000000", want: "0xM80000000000000000000000000000000"},
+		// ppc_fp128 non-canonical double-double pairs
+		{typ: types.PPCFP128, s: "0xM3FF00000000000003FF0000000000000", want: "0xM3FF00000000000003FF0000000000000"},
+		{typ: types.PPCFP128, s: "0xM3FF00000000000003CB0000000000001", want: "0xM3FF00000000000003CB0000000000001"},
+		{typ: types.PPCFP128, s: "0xM7FF80000000000003FF0000000000000", want: "0xM7FF80000000000003FF0000000000000"},
+	}
+	for _, g := range golden {
+		c, err := NewFloatFromString(g.typ, g.s)
+		if err != nil {
+			t.Errorf("unable to parse %q as %v; %v", g.s, g.typ, err)
+			continue
+		}
+		got := c.Ident()
+		if g.want != got {
+			t.Errorf("floating-point literal mismatch of %v %s; expected %q, got %q", g.typ, g.s, g.want, got)
+		}
+	}
+}
+
+func TestNewFloatNaN(t *testing.T) {
+	golden := []struct {
+		typ  *types.FloatType
+		x    float64
+		want string
+	}{
+		{typ: types.Double, x: math.Float64frombits(0x7FF8000000000000), want: "0x7FF8000000000000"},
+		{typ: types.Double, x: math.Float64frombits(0xFFF8000000000123), want: "0xFFF8000000000123"},
+		{typ: types.Float, x: math.Float64frombits(0xFFF8000000000000), want: "0xFFF8000000000000"},
+		{typ: types.Half, x: math.Float64frombits(0xFFF8000000000000), want: "0xHFE00"},
+		{typ: types.X86FP80, x: math.Float64frombits(0x7FF8000000000000), want: "0xK7FFFC000000000000000"},
+		{typ: types.Double, x: math.Inf(-1), want: "0xFFF0000000000000"},
+	}
+	for _, g := range golden {
+		got := NewFloat(g.typ, g.x).Ident()
+		if g.want != got {
+			t.Errorf("floating-point literal mismatch of %v 0x%X; expected %q, got %q", g.typ, math.Float64bits(g.x), g.want, got)
+		}
+	}
+}
+
+func TestFloatInvalidKind(t *testing.T) {
+	if _, err := NewFloatFromString(types.Double, "0xK4000C000000000000000"); err == nil {
+		t.Errorf("expected error for x86_fp80 literal of double type")
+	}
+}
+
+func TestFloatRawModified(t *testing.T) {
+	c, err := NewFloatFromString(types.X86FP80, "0xK40000000000000000000")
+	if err != nil {
+		t.Fatalf("unable to parse x86_fp80 literal; %v", err)
+	}
+	c.X.SetFloat64(1.5)
+	want := "0xK3FFFC000000000000000"
+	if got := c.Ident(); want != got {
+		t.Errorf("floating-point literal mismatch; expected %q, got %q", want, got)
+	}
+}
+
+func TestFloatMissingDigits(t *testing.T) {
+	golden := []struct {
+		typ *types.FloatType
+		s   string
+	}{
+		{typ: types.Double, s: "0x"},
+		{typ: types.Half, s: "0xH"},
+		{typ: types.BFloat, s: "0xR"},
+		{typ: types.X86FP80, s: "0xK"},
+		{typ: types.FP128, s: "0xL"},
+		{typ: types.PPCFP128, s: "0xM"},
+	}
+	for _, g := range golden {
+		if _, err := NewFloatFromString(g.typ, g.s); err == nil {
+			t.Errorf("expected error for hexadecimal floating-point literal %q without digits", g.s)
+		}
+	}
+}
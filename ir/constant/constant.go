@@ -56,7 +56,8 @@ var (
 //
 // https://llvm.org/docs/LangRef.html#undefined-values
 //
-//    *constant.Undef   // https://godoc.org/github.com/llir/llvm/ir/constant#Undef
+//    *constant.Undef    // https://godoc.org/github.com/llir/llvm/ir/constant#Undef
+//    *constant.Poison   // https://godoc.org/github.com/llir/llvm/ir/constant#Poison
 //
 // Addresses of basic blocks
 //
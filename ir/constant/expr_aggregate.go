@@ -66,6 +66,12 @@ func (e *ExprExtractValue) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprExtractValue) Operands() []*Constant {
+	return []*Constant{&e.X}
+}
+
 // ~~~ [ insertvalue ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprInsertValue is an LLVM IR insertvalue expression.
@@ -126,6 +132,12 @@ func (e *ExprInsertValue) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprInsertValue) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Elem}
+}
+
 // ### [ Helper functions ] ####################################################
 
 // aggregateElemType returns the element type at the position in the aggregate
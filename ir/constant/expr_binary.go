@@ -66,6 +66,12 @@ func (e *ExprAdd) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprAdd) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ fadd ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFAdd is an LLVM IR fadd expression.
@@ -114,6 +120,12 @@ func (e *ExprFAdd) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFAdd) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ sub ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprSub is an LLVM IR sub expression.
@@ -170,6 +182,12 @@ func (e *ExprSub) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprSub) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ fsub ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFSub is an LLVM IR fsub expression.
@@ -218,6 +236,12 @@ func (e *ExprFSub) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFSub) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ mul ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprMul is an LLVM IR mul expression.
@@ -274,6 +298,12 @@ func (e *ExprMul) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprMul) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ fmul ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFMul is an LLVM IR fmul expression.
@@ -322,6 +352,12 @@ func (e *ExprFMul) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFMul) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ udiv ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprUDiv is an LLVM IR udiv expression.
@@ -378,6 +414,12 @@ func (e *ExprUDiv) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprUDiv) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ sdiv ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprSDiv is an LLVM IR sdiv expression.
@@ -434,6 +476,12 @@ func (e *ExprSDiv) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprSDiv) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ fdiv ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFDiv is an LLVM IR fdiv expression.
@@ -482,6 +530,12 @@ func (e *ExprFDiv) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFDiv) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ urem ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprURem is an LLVM IR urem expression.
@@ -530,6 +584,12 @@ func (e *ExprURem) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprURem) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ srem ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprSRem is an LLVM IR srem expression.
@@ -578,6 +638,12 @@ func (e *ExprSRem) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprSRem) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ frem ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFRem is an LLVM IR frem expression.
@@ -625,3 +691,9 @@ func (e *ExprFRem) Ident() string {
 func (e *ExprFRem) Simplify() Constant {
 	panic("not yet implemented")
 }
+
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFRem) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
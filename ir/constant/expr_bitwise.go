@@ -66,6 +66,12 @@ func (e *ExprShl) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprShl) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ lshr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprLShr is an LLVM IR lshr expression.
@@ -123,6 +129,12 @@ func (e *ExprLShr) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprLShr) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ ashr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprAShr is an LLVM IR ashr expression.
@@ -180,6 +192,12 @@ func (e *ExprAShr) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprAShr) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ and ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprAnd is an LLVM IR and expression.
@@ -228,6 +246,12 @@ func (e *ExprAnd) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprAnd) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ or ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprOr is an LLVM IR or expression.
@@ -276,6 +300,12 @@ func (e *ExprOr) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprOr) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ xor ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprXor is an LLVM IR xor expression.
@@ -323,3 +353,9 @@ func (e *ExprXor) Ident() string {
 func (e *ExprXor) Simplify() Constant {
 	panic("not yet implemented")
 }
+
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprXor) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
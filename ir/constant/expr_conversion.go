@@ -50,6 +50,12 @@ func (e *ExprTrunc) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprTrunc) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ zext ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprZExt is an LLVM IR zext expression.
@@ -92,6 +98,12 @@ func (e *ExprZExt) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprZExt) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ sext ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprSExt is an LLVM IR sext expression.
@@ -134,6 +146,12 @@ func (e *ExprSExt) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprSExt) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ fptrunc ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFPTrunc is an LLVM IR fptrunc expression.
@@ -176,6 +194,12 @@ func (e *ExprFPTrunc) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFPTrunc) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ fpext ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFPExt is an LLVM IR fpext expression.
@@ -218,6 +242,12 @@ func (e *ExprFPExt) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFPExt) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ fptoui ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFPToUI is an LLVM IR fptoui expression.
@@ -260,6 +290,12 @@ func (e *ExprFPToUI) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFPToUI) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ fptosi ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFPToSI is an LLVM IR fptosi expression.
@@ -302,6 +338,12 @@ func (e *ExprFPToSI) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFPToSI) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ uitofp ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprUIToFP is an LLVM IR uitofp expression.
@@ -344,6 +386,12 @@ func (e *ExprUIToFP) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprUIToFP) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ sitofp ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprSIToFP is an LLVM IR sitofp expression.
@@ -386,6 +434,12 @@ func (e *ExprSIToFP) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprSIToFP) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ ptrtoint ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprPtrToInt is an LLVM IR ptrtoint expression.
@@ -428,6 +482,12 @@ func (e *ExprPtrToInt) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprPtrToInt) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ inttoptr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprIntToPtr is an LLVM IR inttoptr expression.
@@ -470,6 +530,12 @@ func (e *ExprIntToPtr) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprIntToPtr) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ bitcast ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprBitCast is an LLVM IR bitcast expression.
@@ -512,6 +578,12 @@ func (e *ExprBitCast) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprBitCast) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
+
 // ~~~ [ addrspacecast ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprAddrSpaceCast is an LLVM IR addrspacecast expression.
@@ -553,3 +625,9 @@ func (e *ExprAddrSpaceCast) Ident() string {
 func (e *ExprAddrSpaceCast) Simplify() Constant {
 	panic("not yet implemented")
 }
+
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprAddrSpaceCast) Operands() []*Constant {
+	return []*Constant{&e.From}
+}
@@ -91,6 +91,17 @@ func (e *ExprGetElementPtr) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprGetElementPtr) Operands() []*Constant {
+	ops := make([]*Constant, 0, 1+len(e.Indices))
+	ops = append(ops, &e.Src)
+	for _, index := range e.Indices {
+		ops = append(ops, &index.Index)
+	}
+	return ops
+}
+
 // ___ [ gep indices ] _________________________________________________________
 
 // Index is an index of a getelementptr constant expression.
@@ -184,7 +195,7 @@ func gepType(elemType types.Type, indices []*Index) types.Type {
 	//    store <2 x %struct.fileinfo*> %113, <2 x %struct.fileinfo*>* %116, align 8, !dbg !4738, !tbaa !1793
 	if len(indices) > 0 {
 		if t, ok := indices[0].Index.Type().(*types.VectorType); ok {
-			return types.NewVector(t.Len, types.NewPointer(e))
+			return &types.VectorType{Scalable: t.Scalable, Len: t.Len, ElemType: types.NewPointer(e)}
 		}
 	}
 	return types.NewPointer(e)
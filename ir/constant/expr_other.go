@@ -47,7 +47,7 @@ func (e *ExprICmp) Type() types.Type {
 		case *types.IntType, *types.PointerType:
 			e.Typ = types.I1
 		case *types.VectorType:
-			e.Typ = types.NewVector(xType.Len, types.I1)
+			e.Typ = &types.VectorType{Scalable: xType.Scalable, Len: xType.Len, ElemType: types.I1}
 		default:
 			panic(fmt.Errorf("invalid icmp operand type; expected *types.IntType, *types.PointerType or *types.VectorType, got %T", xType))
 		}
@@ -67,6 +67,12 @@ func (e *ExprICmp) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprICmp) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ fcmp ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprFCmp is an LLVM IR fcmp expression.
@@ -105,7 +111,7 @@ func (e *ExprFCmp) Type() types.Type {
 		case *types.FloatType:
 			e.Typ = types.I1
 		case *types.VectorType:
-			e.Typ = types.NewVector(xType.Len, types.I1)
+			e.Typ = &types.VectorType{Scalable: xType.Scalable, Len: xType.Len, ElemType: types.I1}
 		default:
 			panic(fmt.Errorf("invalid fcmp operand type; expected *types.FloatType or *types.VectorType, got %T", xType))
 		}
@@ -125,6 +131,12 @@ func (e *ExprFCmp) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprFCmp) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y}
+}
+
 // ~~~ [ select ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprSelect is an LLVM IR select expression.
@@ -175,3 +187,9 @@ func (e *ExprSelect) Ident() string {
 func (e *ExprSelect) Simplify() Constant {
 	panic("not yet implemented")
 }
+
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprSelect) Operands() []*Constant {
+	return []*Constant{&e.Cond, &e.X, &e.Y}
+}
@@ -63,6 +63,12 @@ func (e *ExprExtractElement) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprExtractElement) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Index}
+}
+
 // ~~~ [ insertelement ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprInsertElement is an LLVM IR insertelement expression.
@@ -120,6 +126,12 @@ func (e *ExprInsertElement) Simplify() Constant {
 	panic("not yet implemented")
 }
 
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprInsertElement) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Elem, &e.Index}
+}
+
 // ~~~ [ shufflevector ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // ExprShuffleVector is an LLVM IR shufflevector expression.
@@ -162,7 +174,7 @@ func (e *ExprShuffleVector) Type() types.Type {
 		if !ok {
 			panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", e.Mask.Type()))
 		}
-		e.Typ = types.NewVector(maskType.Len, xType.ElemType)
+		e.Typ = &types.VectorType{Scalable: maskType.Scalable, Len: maskType.Len, ElemType: xType.ElemType}
 	}
 	return e.Typ
 }
@@ -178,3 +190,9 @@ func (e *ExprShuffleVector) Ident() string {
 func (e *ExprShuffleVector) Simplify() Constant {
 	panic("not yet implemented")
 }
+
+// Operands returns a mutable list of operands of the given constant
+// expression.
+func (e *ExprShuffleVector) Operands() []*Constant {
+	return []*Constant{&e.X, &e.Y, &e.Mask}
+}
@@ -85,4 +85,6 @@ type Expression interface {
 	// Simplify returns an equivalent (and potentially simplified) constant to
 	// the constant expression.
 	Simplify() Constant
+	// Operands returns a mutable list of operands of the constant expression.
+	Operands() []*Constant
 }
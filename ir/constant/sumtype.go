@@ -42,6 +42,10 @@ func (*ZeroInitializer) IsConstant() {}
 // constant.Constant interface.
 func (*Undef) IsConstant() {}
 
+// IsConstant ensures that only constants can be assigned to the
+// constant.Constant interface.
+func (*Poison) IsConstant() {}
+
 // IsConstant ensures that only constants can be assigned to the
 // constant.Constant interface.
 func (*BlockAddress) IsConstant() {}
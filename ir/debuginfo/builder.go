@@ -0,0 +1,644 @@
+// Package debuginfo provides construction and querying of LLVM IR debug
+// information.
+package debuginfo
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// Debug info version emitted in the "Debug Info Version" module flag.
+const DebugInfoVersion = 3
+
+// DWARF version emitted in the "Dwarf Version" module flag.
+const DwarfVersion = 4
+
+// moduleFlagWarning is the module flag behaviour which emits a warning when
+// linking modules with conflicting flag values.
+const moduleFlagWarning = 2
+
+// === [ Builder ] =============================================================
+
+// Builder is a debug information builder, which creates debug information
+// metadata for a given LLVM IR module.
+//
+// Metadata definitions created by the builder are assigned unique IDs and
+// appended to the module. Non-distinct metadata nodes are uniqued, so that
+// requesting the same node twice (e.g. the same DILocation) returns the same
+// metadata definition.
+//
+// The builder keeps track of a current source location, which is attached as
+// !dbg metadata to the instructions and terminators added to the current
+// function while the location is set. Finalize must be invoked once all debug
+// information has been created.
+type Builder struct {
+	// LLVM IR module.
+	m *ir.Module
+	// Next metadata ID.
+	nextID int64
+	// Uniqued non-distinct metadata definitions, keyed by node.
+	uniqued map[string]*metadata.MetadataDef
+	// Compile unit; nil if not yet created.
+	cu *metadata.MetadataDef
+	// Enum types, retained types and global variable expressions of the compile
+	// unit.
+	enums, retainedTypes, globals []metadata.MDField
+	// llvm.dbg.declare and llvm.dbg.value intrinsics; nil if not yet declared.
+	declareFn, valueFn *ir.Function
+
+	// Current function; nil if not present.
+	cur *ir.Function
+	// Current source location; nil if not present.
+	loc *metadata.MetadataDef
+	// Tracks the instructions and terminators of the current function which
+	// have already been considered for location attachment.
+	seen map[*ir.BasicBlock]*blockState
+}
+
+// blockState records how much of a basic block has been considered for
+// location attachment.
+type blockState struct {
+	// Number of instructions considered.
+	ninsts int
+	// Terminator considered; nil if not present.
+	term ir.Terminator
+}
+
+// NewBuilder returns a new debug information builder for the given module.
+func NewBuilder(m *ir.Module) *Builder {
+	b := &Builder{
+		m:       m,
+		uniqued: make(map[string]*metadata.MetadataDef),
+		seen:    make(map[*ir.BasicBlock]*blockState),
+	}
+	for _, def := range m.MetadataDefs {
+		if def.ID >= b.nextID {
+			b.nextID = def.ID + 1
+		}
+		if !def.Distinct {
+			b.uniqued[def.Node.String()] = def
+		}
+	}
+	return b
+}
+
+// Finalize attaches pending source locations and completes the compile unit
+// and module flags. Finalize must be invoked after all debug information has
+// been created.
+func (b *Builder) Finalize() {
+	b.flush()
+	if b.cu != nil {
+		cu := b.cu.Node.(*metadata.DICompileUnit)
+		if len(b.enums) > 0 {
+			cu.Enums = b.tuple(b.enums)
+		}
+		if len(b.retainedTypes) > 0 {
+			cu.RetainedTypes = b.tuple(b.retainedTypes)
+		}
+		if len(b.globals) > 0 {
+			cu.Globals = b.tuple(b.globals)
+		}
+	}
+	b.addModuleFlag("Dwarf Version", DwarfVersion)
+	b.addModuleFlag("Debug Info Version", DebugInfoVersion)
+}
+
+// --- [ Compile units and files ] ---------------------------------------------
+
+// CreateCompileUnit creates a new compile unit based on the given source
+// language, file, producer and optimization flag, and adds it to the
+// !llvm.dbg.cu named metadata of the module.
+func (b *Builder) CreateCompileUnit(lang enum.DwarfLang, file metadata.MDField, producer string, isOptimized bool) *metadata.MetadataDef {
+	cu := &metadata.DICompileUnit{
+		Language:     lang,
+		File:         file,
+		Producer:     producer,
+		IsOptimized:  isOptimized,
+		EmissionKind: enum.EmissionKindFullDebug,
+	}
+	def := b.newDistinct(cu)
+	b.cu = def
+	named := b.namedMetadata("llvm.dbg.cu")
+	named.Nodes = append(named.Nodes, def)
+	return def
+}
+
+// CreateFile creates a new file based on the given file name and directory.
+func (b *Builder) CreateFile(filename, dir string) *metadata.MetadataDef {
+	file := &metadata.DIFile{
+		Filename:  filename,
+		Directory: dir,
+	}
+	return b.unique(file)
+}
+
+// --- [ Types ] ---------------------------------------------------------------
+
+// CreateBasicType creates a new basic type based on the given type name, size
+// in bits and DWARF encoding.
+func (b *Builder) CreateBasicType(name string, size uint64, encoding enum.DwarfAttEncoding) *metadata.MetadataDef {
+	t := &metadata.DIBasicType{
+		Tag:      enum.DwarfTagBaseType,
+		Name:     name,
+		Size:     size,
+		Encoding: encoding,
+	}
+	return b.unique(t)
+}
+
+// CreatePointerType creates a new pointer type based on the given pointee type
+// and size in bits.
+func (b *Builder) CreatePointerType(base metadata.MDField, size uint64) *metadata.MetadataDef {
+	t := &metadata.DIDerivedType{
+		Tag:      enum.DwarfTagPointerType,
+		BaseType: base,
+		Size:     size,
+	}
+	return b.unique(t)
+}
+
+// CreateQualifiedType creates a new qualified type (e.g. DW_TAG_const_type)
+// based on the given tag and base type.
+func (b *Builder) CreateQualifiedType(tag enum.DwarfTag, base metadata.MDField) *metadata.MetadataDef {
+	t := &metadata.DIDerivedType{
+		Tag:      tag,
+		BaseType: base,
+	}
+	return b.unique(t)
+}
+
+// CreateTypedef creates a new typedef of the given base type, declared at the
+// specified source location.
+func (b *Builder) CreateTypedef(base metadata.MDField, name string, file metadata.MDField, line int64, scope metadata.MDField) *metadata.MetadataDef {
+	t := &metadata.DIDerivedType{
+		Tag:      enum.DwarfTagTypedef,
+		Name:     name,
+		Scope:    scope,
+		File:     file,
+		Line:     line,
+		BaseType: base,
+	}
+	return b.unique(t)
+}
+
+// CreateMemberType creates a new member of a structure or union type based on
+// the given scope, member name, source location, size, alignment and offset in
+// bits, and member type.
+func (b *Builder) CreateMemberType(scope metadata.MDField, name string, file metadata.MDField, line int64, size, align, offset uint64, typ metadata.MDField) *metadata.MetadataDef {
+	t := &metadata.DIDerivedType{
+		Tag:      enum.DwarfTagMember,
+		Name:     name,
+		Scope:    scope,
+		File:     file,
+		Line:     line,
+		BaseType: typ,
+		Size:     size,
+		Align:    align,
+		Offset:   offset,
+	}
+	return b.unique(t)
+}
+
+// CreateStructType creates a new structure type based on the given scope,
+// type name, source location, size and alignment in bits, and members.
+//
+// The members of a structure type commonly refer back to the structure type
+// as their scope; to this end, elements may be updated through the Elements
+// field of the returned DICompositeType.
+func (b *Builder) CreateStructType(scope metadata.MDField, name string, file metadata.MDField, line int64, size, align uint64, elements []metadata.MDField) *metadata.MetadataDef {
+	t := &metadata.DICompositeType{
+		Tag:      enum.DwarfTagStructureType,
+		Name:     name,
+		Scope:    scope,
+		File:     file,
+		Line:     line,
+		Size:     size,
+		Align:    align,
+		Elements: b.tuple(elements),
+	}
+	return b.newDistinct(t)
+}
+
+// CreateArrayType creates a new array type based on the given size and
+// alignment in bits, element type and subscripts (see CreateSubrange).
+func (b *Builder) CreateArrayType(size, align uint64, elemType metadata.MDField, subscripts []metadata.MDField) *metadata.MetadataDef {
+	t := &metadata.DICompositeType{
+		Tag:      enum.DwarfTagArrayType,
+		BaseType: elemType,
+		Size:     size,
+		Align:    align,
+		Elements: b.tuple(subscripts),
+	}
+	return b.unique(t)
+}
+
+// CreateSubrange creates a new array subscript range based on the given lower
+// bound and element count.
+func (b *Builder) CreateSubrange(lowerBound, count int64) *metadata.MetadataDef {
+	r := &metadata.DISubrange{
+		Count:      metadata.IntLit(count),
+		LowerBound: lowerBound,
+	}
+	return b.unique(r)
+}
+
+// CreateEnumerator creates a new enumerator based on the given name and value.
+func (b *Builder) CreateEnumerator(name string, v int64) *metadata.MetadataDef {
+	e := &metadata.DIEnumerator{
+		Name:  name,
+		Value: v,
+	}
+	return b.unique(e)
+}
+
+// CreateEnumerationType creates a new enumeration type based on the given
+// scope, type name, source location, size and alignment in bits, enumerators
+// (see CreateEnumerator) and underlying type. The enumeration type is recorded
+// in the compile unit.
+func (b *Builder) CreateEnumerationType(scope metadata.MDField, name string, file metadata.MDField, line int64, size, align uint64, enumerators []metadata.MDField, underlying metadata.MDField) *metadata.MetadataDef {
+	t := &metadata.DICompositeType{
+		Tag:      enum.DwarfTagEnumerationType,
+		Name:     name,
+		Scope:    scope,
+		File:     file,
+		Line:     line,
+		BaseType: underlying,
+		Size:     size,
+		Align:    align,
+		Elements: b.tuple(enumerators),
+	}
+	def := b.unique(t)
+	b.enums = append(b.enums, def)
+	return def
+}
+
+// CreateSubroutineType creates a new subroutine type based on the given return
+// type and parameter types. A nil return type denotes void.
+func (b *Builder) CreateSubroutineType(retType metadata.MDField, paramTypes ...metadata.MDField) *metadata.MetadataDef {
+	if retType == nil {
+		retType = metadata.Null
+	}
+	ts := append([]metadata.MDField{retType}, paramTypes...)
+	t := &metadata.DISubroutineType{
+		Types: b.tuple(ts),
+	}
+	return b.unique(t)
+}
+
+// RetainType records the given type in the compile unit, to ensure that debug
+// information is emitted for the type even if it is not referenced.
+func (b *Builder) RetainType(t metadata.MDField) {
+	b.retainedTypes = append(b.retainedTypes, t)
+}
+
+// --- [ Scopes ] --------------------------------------------------------------
+
+// CreateFunction creates a new subprogram describing the given function
+// definition, based on the given scope, source-level function name, source
+// location and subroutine type (see CreateSubroutineType). The subprogram is
+// attached to the function as !dbg metadata and the function becomes the
+// current function of the builder.
+func (b *Builder) CreateFunction(f *ir.Function, scope metadata.MDField, name string, file metadata.MDField, line int64, typ metadata.MDField) *metadata.MetadataDef {
+	sp := &metadata.DISubprogram{
+		Scope:        scope,
+		Name:         name,
+		File:         file,
+		Line:         line,
+		Type:         typ,
+		IsLocal:      isLocal(f.Linkage),
+		IsDefinition: true,
+		ScopeLine:    line,
+		Flags:        enum.DIFlagPrototyped,
+	}
+	if f.Name() != name {
+		sp.LinkageName = f.Name()
+	}
+	if b.cu != nil {
+		sp.Unit = b.cu
+		sp.IsOptimized = b.cu.Node.(*metadata.DICompileUnit).IsOptimized
+	}
+	def := b.newDistinct(sp)
+	ir.SetMDAttachment(f, "dbg", def)
+	b.SetFunction(f)
+	return def
+}
+
+// CreateLexicalBlock creates a new lexical block based on the given parent
+// scope and source location.
+func (b *Builder) CreateLexicalBlock(scope, file metadata.MDField, line, col int64) *metadata.MetadataDef {
+	block := &metadata.DILexicalBlock{
+		Scope:  scope,
+		File:   file,
+		Line:   line,
+		Column: col,
+	}
+	return b.newDistinct(block)
+}
+
+// --- [ Variables ] -----------------------------------------------------------
+
+// CreateAutoVariable creates a new local variable based on the given scope,
+// variable name, source location and type.
+func (b *Builder) CreateAutoVariable(scope metadata.MDField, name string, file metadata.MDField, line int64, typ metadata.MDField) *metadata.MetadataDef {
+	return b.CreateParameterVariable(scope, name, 0, file, line, typ)
+}
+
+// CreateParameterVariable creates a new parameter variable based on the given
+// scope, parameter name, 1-based argument number, source location and type.
+func (b *Builder) CreateParameterVariable(scope metadata.MDField, name string, arg uint64, file metadata.MDField, line int64, typ metadata.MDField) *metadata.MetadataDef {
+	v := &metadata.DILocalVariable{
+		Name:  name,
+		Arg:   arg,
+		Scope: scope,
+		File:  file,
+		Line:  line,
+		Type:  typ,
+	}
+	return b.unique(v)
+}
+
+// CreateGlobalVariableExpression creates a new global variable describing the
+// given global variable, based on the given scope, source-level variable name,
+// source location and type. The global variable expression is attached to the
+// global variable as !dbg metadata and recorded in the compile unit.
+func (b *Builder) CreateGlobalVariableExpression(g *ir.Global, scope metadata.MDField, name string, file metadata.MDField, line int64, typ metadata.MDField) *metadata.MetadataDef {
+	v := &metadata.DIGlobalVariable{
+		Name:         name,
+		Scope:        scope,
+		File:         file,
+		Line:         line,
+		Type:         typ,
+		IsLocal:      isLocal(g.Linkage),
+		IsDefinition: g.Init != nil,
+	}
+	if g.Name() != name {
+		v.LinkageName = g.Name()
+	}
+	expr := &metadata.DIGlobalVariableExpression{
+		Var:  b.newDistinct(v),
+		Expr: b.CreateExpression(),
+	}
+	def := b.unique(expr)
+	ir.SetMDAttachment(g, "dbg", def)
+	b.globals = append(b.globals, def)
+	return def
+}
+
+// CreateExpression creates a new DWARF expression based on the given
+// operators and operands.
+func (b *Builder) CreateExpression(fields ...metadata.DIExpressionField) *metadata.DIExpression {
+	return &metadata.DIExpression{Fields: fields}
+}
+
+// --- [ Locations ] -----------------------------------------------------------
+
+// CreateLocation creates a new source location based on the given line,
+// column, scope and optional inlined-at location (nil if not present).
+func (b *Builder) CreateLocation(line, col int64, scope, inlinedAt metadata.MDField) *metadata.MetadataDef {
+	loc := &metadata.DILocation{
+		Line:      line,
+		Column:    col,
+		Scope:     scope,
+		InlinedAt: inlinedAt,
+	}
+	return b.unique(loc)
+}
+
+// SetFunction sets the current function of the builder. Instructions and
+// terminators added to the current function are attached the current source
+// location.
+func (b *Builder) SetFunction(f *ir.Function) {
+	b.flush()
+	b.cur = f
+	b.loc = nil
+	b.seen = make(map[*ir.BasicBlock]*blockState)
+	b.skip()
+}
+
+// SetLocation sets the current source location of the builder to the given
+// line and column in scope. Instructions and terminators subsequently added to
+// the current function are attached the location as !dbg metadata, until the
+// location is changed.
+func (b *Builder) SetLocation(line, col int64, scope metadata.MDField) *metadata.MetadataDef {
+	loc := b.CreateLocation(line, col, scope, nil)
+	b.flush()
+	b.loc = loc
+	return loc
+}
+
+// ClearLocation clears the current source location of the builder.
+// Instructions and terminators subsequently added to the current function are
+// not attached any location.
+func (b *Builder) ClearLocation() {
+	b.flush()
+	b.loc = nil
+}
+
+// --- [ Intrinsics ] ----------------------------------------------------------
+
+// InsertDeclare appends a call to llvm.dbg.declare to the given basic block,
+// describing the storage address of the local variable (see
+// CreateAutoVariable). A nil expression denotes an empty DWARF expression.
+func (b *Builder) InsertDeclare(block *ir.BasicBlock, storage value.Value, v *metadata.MetadataDef, expr *metadata.DIExpression) *ir.InstCall {
+	if b.declareFn == nil {
+		b.declareFn = b.intrinsic("llvm.dbg.declare")
+	}
+	return b.insertDbgCall(block, b.declareFn, storage, v, expr)
+}
+
+// InsertValue appends a call to llvm.dbg.value to the given basic block,
+// describing the new value of the local variable (see CreateAutoVariable). A
+// nil expression denotes an empty DWARF expression.
+func (b *Builder) InsertValue(block *ir.BasicBlock, val value.Value, v *metadata.MetadataDef, expr *metadata.DIExpression) *ir.InstCall {
+	if b.valueFn == nil {
+		b.valueFn = b.intrinsic("llvm.dbg.value")
+	}
+	return b.insertDbgCall(block, b.valueFn, val, v, expr)
+}
+
+// insertDbgCall appends a call to the given debug intrinsic to the basic
+// block. The call is attached the current source location, or a location
+// derived from the variable if no current location is set.
+func (b *Builder) insertDbgCall(block *ir.BasicBlock, callee *ir.Function, val value.Value, v *metadata.MetadataDef, expr *metadata.DIExpression) *ir.InstCall {
+	// Attach the current location to previously added instructions before
+	// appending the call.
+	b.flush()
+	if expr == nil {
+		expr = b.CreateExpression()
+	}
+	args := []value.Value{
+		&metadata.Value{Value: val},
+		&metadata.Value{Value: v},
+		&metadata.Value{Value: expr},
+	}
+	inst := block.NewCall(callee, args...)
+	loc := b.loc
+	if loc == nil {
+		if lv, ok := v.Node.(*metadata.DILocalVariable); ok {
+			loc = b.CreateLocation(lv.Line, 0, lv.Scope, nil)
+		}
+	}
+	if loc != nil {
+		ir.SetMDAttachment(inst, "dbg", loc)
+	}
+	if state, ok := b.seen[block]; ok {
+		state.ninsts = len(block.Insts)
+	}
+	return inst
+}
+
+// intrinsic returns the debug intrinsic of the given name, declaring it in the
+// module if not already present.
+func (b *Builder) intrinsic(name string) *ir.Function {
+	for _, f := range b.m.Funcs {
+		if f.Name() == name {
+			return f
+		}
+	}
+	params := []*ir.Param{
+		ir.NewParam("", types.Metadata),
+		ir.NewParam("", types.Metadata),
+		ir.NewParam("", types.Metadata),
+	}
+	f := b.m.NewFunc(name, types.Void, params...)
+	f.FuncAttrs = append(f.FuncAttrs, enum.FuncAttrNoUnwind, enum.FuncAttrReadNone, enum.FuncAttrSpeculatable)
+	return f
+}
+
+// ### [ Helper functions ] ####################################################
+
+// flush attaches the current source location to the instructions and
+// terminators of the current function which have been added since the last
+// flush.
+func (b *Builder) flush() {
+	if b.cur == nil {
+		return
+	}
+	for _, block := range b.cur.Blocks {
+		state, ok := b.seen[block]
+		if !ok {
+			state = &blockState{}
+			b.seen[block] = state
+		}
+		for _, inst := range block.Insts[state.ninsts:] {
+			b.attach(inst)
+		}
+		state.ninsts = len(block.Insts)
+		if block.Term != nil && block.Term != state.term {
+			b.attach(block.Term)
+			state.term = block.Term
+		}
+	}
+}
+
+// skip marks the existing instructions and terminators of the current function
+// as already considered for location attachment.
+func (b *Builder) skip() {
+	if b.cur == nil {
+		return
+	}
+	for _, block := range b.cur.Blocks {
+		b.seen[block] = &blockState{ninsts: len(block.Insts), term: block.Term}
+	}
+}
+
+// attach attaches the current source location to the given instruction or
+// terminator, unless it already has a location.
+func (b *Builder) attach(v interface{}) {
+	if b.loc == nil || ir.MDAttachment(v, "dbg") != nil {
+		return
+	}
+	ir.SetMDAttachment(v, "dbg", b.loc)
+}
+
+// newDistinct returns a new distinct metadata definition of the given node,
+// and appends it to the module.
+func (b *Builder) newDistinct(node metadata.MDNode) *metadata.MetadataDef {
+	def := &metadata.MetadataDef{
+		ID:       b.nextID,
+		Node:     node,
+		Distinct: true,
+	}
+	b.nextID++
+	b.m.MetadataDefs = append(b.m.MetadataDefs, def)
+	return def
+}
+
+// unique returns the uniqued metadata definition of the given node, creating
+// and appending a new metadata definition to the module if not already
+// present.
+func (b *Builder) unique(node metadata.MDNode) *metadata.MetadataDef {
+	key := node.String()
+	if def, ok := b.uniqued[key]; ok {
+		return def
+	}
+	def := &metadata.MetadataDef{
+		ID:   b.nextID,
+		Node: node,
+	}
+	b.nextID++
+	b.m.MetadataDefs = append(b.m.MetadataDefs, def)
+	b.uniqued[key] = def
+	return def
+}
+
+// tuple returns the uniqued metadata tuple of the given fields.
+func (b *Builder) tuple(fields []metadata.MDField) *metadata.MetadataDef {
+	return b.unique(&metadata.MDTuple{Fields: fields})
+}
+
+// namedMetadata returns the named metadata definition of the given name,
+// appending a new named metadata definition to the module if not already
+// present.
+func (b *Builder) namedMetadata(name string) *metadata.NamedMetadataDef {
+	for _, md := range b.m.NamedMetadataDefs {
+		if md.Name == name {
+			return md
+		}
+	}
+	md := &metadata.NamedMetadataDef{Name: name}
+	b.m.NamedMetadataDefs = append(b.m.NamedMetadataDefs, md)
+	return md
+}
+
+// addModuleFlag adds a module flag with the given key and value to the
+// !llvm.module.flags named metadata of the module, unless a flag of the same
+// key is already present. The flag uses the "warning" merge behaviour, as
+// emitted by Clang for debug info flags.
+func (b *Builder) addModuleFlag(key string, val int64) {
+	flags := b.namedMetadata("llvm.module.flags")
+	for _, node := range flags.Nodes {
+		def, ok := node.(*metadata.MetadataDef)
+		if !ok {
+			continue
+		}
+		tuple, ok := def.Node.(*metadata.MDTuple)
+		if !ok || len(tuple.Fields) != 3 {
+			continue
+		}
+		if s, ok := tuple.Fields[1].(*metadata.MDString); ok && s.Value == key {
+			return
+		}
+	}
+	flag := &metadata.MDTuple{
+		Fields: []metadata.MDField{
+			constant.NewInt(types.I32, moduleFlagWarning),
+			&metadata.MDString{Value: key},
+			constant.NewInt(types.I32, val),
+		},
+	}
+	flags.Nodes = append(flags.Nodes, b.unique(flag))
+}
+
+// isLocal reports whether the given linkage is local to the compile unit.
+func isLocal(linkage enum.Linkage) bool {
+	switch linkage {
+	case enum.LinkageInternal, enum.LinkagePrivate:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,27 @@
+package debuginfo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+)
+
+func TestCreateFunctionWithoutCompileUnit(t *testing.T) {
+	m := ir.NewModule()
+	db := NewBuilder(m)
+	file := db.CreateFile("f.c", "/tmp")
+	sig := db.CreateSubroutineType(nil)
+	f := m.NewFunc("f", types.Void)
+	f.NewBlock("").NewRet(nil)
+	sp := db.CreateFunction(f, file, "f", file, 1, sig)
+	db.Finalize()
+	if unit := sp.Node.(*metadata.DISubprogram).Unit; unit != nil {
+		t.Errorf("unexpected compile unit of subprogram; expected nil, got %v", unit)
+	}
+	if s := m.String(); strings.Contains(s, "unit:") {
+		t.Errorf("unexpected compile unit field in output:\n%s", s)
+	}
+}
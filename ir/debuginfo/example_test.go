@@ -0,0 +1,87 @@
+package debuginfo_test
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/debuginfo"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/types"
+)
+
+func ExampleBuilder() {
+	// This example produces LLVM IR code with debug information equivalent to
+	// the following C code.
+	//
+	//    1: int sq(int x) {
+	//    2:    int y = x*x;
+	//    3:    return y;
+	//    4: }
+
+	// Create a new LLVM IR module and debug information builder.
+	m := ir.NewModule()
+	db := debuginfo.NewBuilder(m)
+	file := db.CreateFile("sq.c", "/tmp")
+	db.CreateCompileUnit(enum.DwarfLangC99, file, "llir", false)
+	intType := db.CreateBasicType("int", 32, enum.DwarfAttEncodingSigned)
+	sig := db.CreateSubroutineType(intType, intType)
+
+	// Create the function and its subprogram.
+	x := ir.NewParam("x", types.I32)
+	f := m.NewFunc("sq", types.I32, x)
+	sp := db.CreateFunction(f, file, "sq", file, 1, sig)
+	entry := f.NewBlock("")
+
+	// 1: int sq(int x) {
+	db.SetLocation(1, 0, sp)
+	xAddr := entry.NewAlloca(types.I32)
+	entry.NewStore(x, xAddr)
+	xVar := db.CreateParameterVariable(sp, "x", 1, file, 1, intType)
+	db.InsertDeclare(entry, xAddr, xVar, nil)
+
+	// 2: int y = x*x;
+	db.SetLocation(2, 8, sp)
+	yVar := db.CreateAutoVariable(sp, "y", file, 2, intType)
+	y := entry.NewMul(x, x)
+	db.InsertValue(entry, y, yVar, nil)
+
+	// 3: return y;
+	db.SetLocation(3, 4, sp)
+	entry.NewRet(y)
+
+	// Complete the debug information.
+	db.Finalize()
+	fmt.Println(m)
+
+	// Output:
+	// define i32 @sq(i32 %x) !dbg !5 {
+	// ; <label>:0
+	// 	%1 = alloca i32, !dbg !6
+	// 	store i32 %x, i32* %1, !dbg !6
+	// 	call void @llvm.dbg.declare(metadata i32* %1, metadata !7, metadata !DIExpression()), !dbg !6
+	// 	%2 = mul i32 %x, %x, !dbg !8
+	// 	call void @llvm.dbg.value(metadata i32 %2, metadata !9, metadata !DIExpression()), !dbg !8
+	// 	ret i32 %2, !dbg !10
+	// }
+	//
+	// declare void @llvm.dbg.declare(metadata, metadata, metadata) nounwind readnone speculatable
+	//
+	// declare void @llvm.dbg.value(metadata, metadata, metadata) nounwind readnone speculatable
+	//
+	// !llvm.dbg.cu = !{!1}
+	// !llvm.module.flags = !{!11, !12}
+	//
+	// !0 = !DIFile(filename: "sq.c", directory: "/tmp")
+	// !1 = distinct !DICompileUnit(language: DW_LANG_C99, file: !0, producer: "llir", emissionKind: FullDebug)
+	// !2 = !DIBasicType(tag: DW_TAG_base_type, name: "int", size: 32, encoding: DW_ATE_signed)
+	// !3 = !{!2, !2}
+	// !4 = !DISubroutineType(types: !3)
+	// !5 = distinct !DISubprogram(name: "sq", scope: !0, file: !0, line: 1, type: !4, isDefinition: true, scopeLine: 1, flags: DIFlagPrototyped, unit: !1)
+	// !6 = !DILocation(line: 1, scope: !5)
+	// !7 = !DILocalVariable(name: "x", arg: 1, scope: !5, file: !0, line: 1, type: !2)
+	// !8 = !DILocation(line: 2, column: 8, scope: !5)
+	// !9 = !DILocalVariable(name: "y", scope: !5, file: !0, line: 2, type: !2)
+	// !10 = !DILocation(line: 3, column: 4, scope: !5)
+	// !11 = !{i32 2, !"Dwarf Version", i32 4}
+	// !12 = !{i32 2, !"Debug Info Version", i32 3}
+}
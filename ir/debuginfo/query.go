@@ -0,0 +1,292 @@
+package debuginfo
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+)
+
+// === [ Source locations ] ====================================================
+
+// Position is a source position.
+type Position struct {
+	// Source file name.
+	Filename string
+	// Source file directory.
+	Directory string
+	// Line number (1-based); or 0 if unknown.
+	Line int64
+	// Column number (1-based); or 0 if unknown.
+	Column int64
+}
+
+// Location returns the source location attached as !dbg metadata to the given
+// instruction or terminator, or nil if not present.
+func Location(v interface{}) *metadata.DILocation {
+	loc, _ := Node(ir.MDAttachment(v, "dbg")).(*metadata.DILocation)
+	return loc
+}
+
+// Pos returns the source position of the given instruction or terminator, as
+// specified by its !dbg location. The boolean return value reports whether a
+// location was present.
+func Pos(v interface{}) (Position, bool) {
+	loc := Location(v)
+	if loc == nil {
+		return Position{}, false
+	}
+	return LocationPos(loc), true
+}
+
+// LocationPos returns the source position of the given location. The file of
+// the location is determined by its scope.
+func LocationPos(loc *metadata.DILocation) Position {
+	pos := Position{Line: loc.Line, Column: loc.Column}
+	if file := ScopeFile(loc.Scope); file != nil {
+		pos.Filename = file.Filename
+		pos.Directory = file.Directory
+	}
+	return pos
+}
+
+// InlineChain returns the chain of source locations of the given location
+// following inlinedAt links, starting with the location itself (innermost
+// inlined callee) and ending with the location in the function into which the
+// code was inlined.
+func InlineChain(loc *metadata.DILocation) []*metadata.DILocation {
+	var chain []*metadata.DILocation
+	for loc != nil {
+		chain = append(chain, loc)
+		if loc.InlinedAt == nil {
+			break
+		}
+		loc, _ = Node(loc.InlinedAt).(*metadata.DILocation)
+	}
+	return chain
+}
+
+// === [ Scopes ] ==============================================================
+
+// Subprogram returns the subprogram attached as !dbg metadata to the given
+// function, or nil if not present.
+func Subprogram(f *ir.Function) *metadata.DISubprogram {
+	sp, _ := Node(ir.MDAttachment(f, "dbg")).(*metadata.DISubprogram)
+	return sp
+}
+
+// ScopeChain returns the chain of enclosing scopes of the given scope, starting
+// with the scope itself. The chain follows the parent scopes of lexical blocks
+// (DILexicalBlock and DILexicalBlockFile) and ends at the first scope which is
+// not a lexical block (e.g. DISubprogram).
+func ScopeChain(scope metadata.MDField) []metadata.MDField {
+	var chain []metadata.MDField
+	for scope != nil {
+		chain = append(chain, scope)
+		switch s := Node(scope).(type) {
+		case *metadata.DILexicalBlock:
+			scope = s.Scope
+		case *metadata.DILexicalBlockFile:
+			scope = s.Scope
+		default:
+			return chain
+		}
+	}
+	return chain
+}
+
+// ScopeSubprogram returns the subprogram enclosing the given scope, or nil if
+// not present.
+func ScopeSubprogram(scope metadata.MDField) *metadata.DISubprogram {
+	chain := ScopeChain(scope)
+	if len(chain) == 0 {
+		return nil
+	}
+	sp, _ := Node(chain[len(chain)-1]).(*metadata.DISubprogram)
+	return sp
+}
+
+// ScopeFile returns the source file of the given scope, or nil if not present.
+// The file of the innermost scope in the scope chain which specifies a file is
+// used.
+func ScopeFile(scope metadata.MDField) *metadata.DIFile {
+	for _, s := range ScopeChain(scope) {
+		var file metadata.MDField
+		switch s := Node(s).(type) {
+		case *metadata.DIFile:
+			return s
+		case *metadata.DILexicalBlock:
+			file = s.File
+		case *metadata.DILexicalBlockFile:
+			file = s.File
+		case *metadata.DISubprogram:
+			file = s.File
+		case *metadata.DICompositeType:
+			file = s.File
+		case *metadata.DICompileUnit:
+			file = s.File
+		}
+		if f, ok := Node(file).(*metadata.DIFile); ok {
+			return f
+		}
+	}
+	return nil
+}
+
+// === [ Variables ] ===========================================================
+
+// LiveVariables returns the local variables of the given function which are
+// live at the given instruction or terminator; i.e. variables whose scope
+// encloses the scope of the instruction, and which are either described by a
+// dbg.declare call, or by a dbg.value call setting a defined value along some
+// path reaching the instruction.
+func LiveVariables(f *ir.Function, v interface{}) []*metadata.DILocalVariable {
+	loc := Location(v)
+	if loc == nil {
+		return nil
+	}
+	// Collect variables visible from the scope of the instruction, in order of
+	// first occurrence. Local variables are described per inlined instance, so
+	// only variables of the same inline instance as the instruction are
+	// considered.
+	inScope := make(map[metadata.MDNode]bool)
+	for _, scope := range ScopeChain(loc.Scope) {
+		inScope[Node(scope)] = true
+	}
+	var lvs []*metadata.DILocalVariable
+	visible := make(map[*metadata.DILocalVariable]bool)
+	declared := make(map[*metadata.DILocalVariable]bool)
+	for _, block := range f.Blocks {
+		for _, inst := range block.Insts {
+			call, lv, _ := dbgCall(inst)
+			if lv == nil || visible[lv] || !inScope[Node(lv.Scope)] {
+				continue
+			}
+			callLoc := Location(call)
+			if callLoc == nil || Node(callLoc.InlinedAt) != Node(loc.InlinedAt) {
+				continue
+			}
+			visible[lv] = true
+			lvs = append(lvs, lv)
+			if call.Callee.(*ir.Function).Name() == "llvm.dbg.declare" {
+				declared[lv] = true
+			}
+		}
+	}
+	// Compute the set of variables set by dbg.value at the entry of each basic
+	// block.
+	in := make(map[*ir.BasicBlock]map[*metadata.DILocalVariable]bool)
+	for _, block := range f.Blocks {
+		in[block] = make(map[*metadata.DILocalVariable]bool)
+	}
+	transfer := func(live map[*metadata.DILocalVariable]bool, inst ir.Instruction) {
+		if _, lv, undef := dbgCall(inst); lv != nil {
+			live[lv] = !undef
+		}
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, block := range f.Blocks {
+			live := copySet(in[block])
+			for _, inst := range block.Insts {
+				transfer(live, inst)
+			}
+			if block.Term == nil {
+				continue
+			}
+			for _, succ := range block.Term.Succs() {
+				for lv, ok := range live {
+					if ok && !in[succ][lv] {
+						in[succ][lv] = true
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	// Compute the live variables at the position of the instruction.
+	for _, block := range f.Blocks {
+		live := copySet(in[block])
+		found := v == block.Term
+		for _, inst := range block.Insts {
+			if inst == v {
+				found = true
+				break
+			}
+			transfer(live, inst)
+		}
+		if !found {
+			continue
+		}
+		var res []*metadata.DILocalVariable
+		for _, lv := range lvs {
+			if declared[lv] || live[lv] {
+				res = append(res, lv)
+			}
+		}
+		return res
+	}
+	return nil
+}
+
+// dbgCall returns the given instruction as a llvm.dbg.declare or llvm.dbg.value
+// call together with the local variable it describes, and reports whether the
+// described value is undefined. A nil variable is returned if inst is not such
+// a call.
+func dbgCall(inst ir.Instruction) (*ir.InstCall, *metadata.DILocalVariable, bool) {
+	call, ok := inst.(*ir.InstCall)
+	if !ok || len(call.Args) < 2 {
+		return nil, nil, false
+	}
+	callee, ok := call.Callee.(*ir.Function)
+	if !ok {
+		return nil, nil, false
+	}
+	switch callee.Name() {
+	case "llvm.dbg.declare", "llvm.dbg.value":
+	default:
+		return nil, nil, false
+	}
+	arg, ok := call.Args[1].(*metadata.Value)
+	if !ok {
+		return nil, nil, false
+	}
+	lv, ok := Node(arg.Value).(*metadata.DILocalVariable)
+	if !ok {
+		return nil, nil, false
+	}
+	undef := false
+	if val, ok := call.Args[0].(*metadata.Value); ok {
+		switch val.Value.(type) {
+		case *constant.Undef, *constant.Poison, *metadata.NullLit:
+			undef = true
+		}
+	}
+	return call, lv, undef
+}
+
+// ### [ Helper functions ] ####################################################
+
+// Node returns the metadata node referred to by the given metadata, resolving
+// metadata definitions (e.g. !42) to their nodes. A nil node is returned for
+// nil metadata.
+func Node(md metadata.Metadata) metadata.MDNode {
+	for {
+		switch m := md.(type) {
+		case nil:
+			return nil
+		case *metadata.MetadataDef:
+			md = m.Node
+		default:
+			return m
+		}
+	}
+}
+
+// copySet returns a copy of the given set of local variables.
+func copySet(s map[*metadata.DILocalVariable]bool) map[*metadata.DILocalVariable]bool {
+	t := make(map[*metadata.DILocalVariable]bool, len(s))
+	for k, v := range s {
+		t[k] = v
+	}
+	return t
+}
@@ -0,0 +1,193 @@
+package debuginfo
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+)
+
+const queryInput = `
+%struct.node = type { i32, %struct.node* }
+
+define i32 @sum(%struct.node* %list) !dbg !10 {
+	%sum = alloca i32
+	call void @llvm.dbg.declare(metadata i32* %sum, metadata !20, metadata !DIExpression()), !dbg !30
+	br label %loop
+
+loop:
+	%n = phi %struct.node* [ %list, %0 ], [ %next, %loop ]
+	call void @llvm.dbg.value(metadata %struct.node* %n, metadata !21, metadata !DIExpression()), !dbg !31
+	%next = load %struct.node*, %struct.node** null, !dbg !32
+	%cmp = icmp eq %struct.node* %next, null, !dbg !33
+	br i1 %cmp, label %exit, label %loop, !dbg !33
+
+exit:
+	call void @llvm.dbg.value(metadata %struct.node* undef, metadata !21, metadata !DIExpression()), !dbg !31
+	%x = load i32, i32* %sum, !dbg !34
+	ret i32 %x, !dbg !34
+}
+
+declare void @llvm.dbg.declare(metadata, metadata, metadata)
+
+declare void @llvm.dbg.value(metadata, metadata, metadata)
+
+!llvm.dbg.cu = !{!0}
+
+!0 = distinct !DICompileUnit(language: DW_LANG_C99, file: !1, producer: "clang", emissionKind: FullDebug)
+!1 = !DIFile(filename: "sum.c", directory: "/src")
+!2 = !DIFile(filename: "list.h", directory: "/src/include")
+!3 = !DIBasicType(name: "int", size: 32, encoding: DW_ATE_signed)
+!4 = distinct !DICompositeType(tag: DW_TAG_structure_type, name: "node", file: !2, line: 1, size: 128, elements: !5)
+!5 = !{!6, !7}
+!6 = !DIDerivedType(tag: DW_TAG_member, name: "val", scope: !4, file: !2, line: 2, baseType: !3, size: 32)
+!7 = !DIDerivedType(tag: DW_TAG_member, name: "next", scope: !4, file: !2, line: 3, baseType: !8, size: 64, offset: 64)
+!8 = !DIDerivedType(tag: DW_TAG_pointer_type, baseType: !4, size: 64)
+!9 = !DISubroutineType(types: !{!3, !8})
+!10 = distinct !DISubprogram(name: "sum", scope: !1, file: !1, line: 5, type: !9, isDefinition: true, unit: !0)
+!11 = distinct !DILexicalBlock(scope: !10, file: !1, line: 7, column: 2)
+!12 = !DILexicalBlockFile(scope: !11, file: !2, discriminator: 0)
+!13 = distinct !DISubprogram(name: "next", scope: !2, file: !2, line: 10, type: !9, isDefinition: true, unit: !0)
+!20 = !DILocalVariable(name: "sum", scope: !10, file: !1, line: 6, type: !3)
+!21 = !DILocalVariable(name: "n", scope: !11, file: !1, line: 7, type: !8)
+!22 = !DICompositeType(tag: DW_TAG_array_type, baseType: !3, size: 320, elements: !{!23})
+!23 = !DISubrange(count: 10)
+!30 = !DILocation(line: 6, column: 6, scope: !10)
+!31 = !DILocation(line: 7, column: 15, scope: !11)
+!32 = !DILocation(line: 11, column: 3, scope: !13, inlinedAt: !35)
+!33 = !DILocation(line: 7, column: 20, scope: !12)
+!34 = !DILocation(line: 9, column: 9, scope: !10)
+!35 = !DILocation(line: 8, column: 10, scope: !11)
+`
+
+func TestQuery(t *testing.T) {
+	m, err := asm.ParseString("query.ll", queryInput)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	f := m.Funcs[0]
+	if sp := Subprogram(f); sp == nil || sp.Name != "sum" {
+		t.Fatalf("subprogram mismatch; expected %q, got %v", "sum", sp)
+	}
+	loop, exit := f.Blocks[1], f.Blocks[2]
+	load := loop.Insts[2]
+	cmp := loop.Insts[3]
+	ret := exit.Term
+
+	// Test source positions.
+	golden := []struct {
+		v    interface{}
+		want Position
+	}{
+		{v: f.Blocks[0].Insts[1], want: Position{Filename: "sum.c", Directory: "/src", Line: 6, Column: 6}},
+		{v: load, want: Position{Filename: "list.h", Directory: "/src/include", Line: 11, Column: 3}},
+		{v: cmp, want: Position{Filename: "list.h", Directory: "/src/include", Line: 7, Column: 20}},
+		{v: ret, want: Position{Filename: "sum.c", Directory: "/src", Line: 9, Column: 9}},
+	}
+	for _, g := range golden {
+		got, ok := Pos(g.v)
+		if !ok {
+			t.Errorf("missing position of %v", g.v)
+			continue
+		}
+		if got != g.want {
+			t.Errorf("position mismatch; expected %v, got %v", g.want, got)
+		}
+	}
+	if _, ok := Pos(f.Blocks[0].Insts[0]); ok {
+		t.Errorf("unexpected position of instruction without !dbg")
+	}
+
+	// Test inlinedAt chains and scopes.
+	chain := InlineChain(Location(load))
+	if len(chain) != 2 || chain[1].Line != 8 {
+		t.Errorf("inline chain mismatch; expected 2 locations ending at line 8, got %v", chain)
+	}
+	if sp := ScopeSubprogram(chain[0].Scope); sp == nil || sp.Name != "next" {
+		t.Errorf("inlined subprogram mismatch; expected %q, got %v", "next", sp)
+	}
+	if sp := ScopeSubprogram(Location(cmp).Scope); sp == nil || sp.Name != "sum" {
+		t.Errorf("scope subprogram mismatch; expected %q, got %v", "sum", sp)
+	}
+
+	// Test live variables.
+	liveGolden := []struct {
+		v    interface{}
+		want []string
+	}{
+		{v: cmp, want: []string{"sum", "n"}},
+		{v: load, want: nil}, // inlined code.
+		{v: ret, want: []string{"sum"}},
+	}
+	for _, g := range liveGolden {
+		got := names(LiveVariables(f, g.v))
+		if !equalStrings(got, g.want) {
+			t.Errorf("live variables mismatch at %v; expected %q, got %q", g.v, g.want, got)
+		}
+	}
+}
+
+func TestResolveType(t *testing.T) {
+	m, err := asm.ParseString("query.ll", queryInput)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	golden := []struct {
+		id   int64
+		want string
+	}{
+		{id: 3, want: "int"},
+		{id: 4, want: "struct node"},
+		{id: 8, want: "struct node*"},
+		{id: 9, want: "int (struct node*)"},
+		{id: 22, want: "int[10]"},
+	}
+	for _, g := range golden {
+		typ := ResolveType(metadataDef(m, g.id))
+		if got := typ.String(); got != g.want {
+			t.Errorf("type mismatch of !%d; expected %q, got %q", g.id, g.want, got)
+		}
+	}
+	// Test cyclic structure type.
+	node := ResolveType(metadataDef(m, 4))
+	if len(node.Members) != 2 {
+		t.Fatalf("members mismatch; expected 2, got %d", len(node.Members))
+	}
+	next := node.Members[1]
+	if next.Name != "next" || next.Offset != 64 || next.Type.Base != node {
+		t.Errorf("member mismatch; expected next at offset 64 pointing to node, got %q at offset %d", next.Name, next.Offset)
+	}
+}
+
+// metadataDef returns the metadata definition of the given ID.
+func metadataDef(m *ir.Module, id int64) *metadata.MetadataDef {
+	for _, def := range m.MetadataDefs {
+		if def.ID == id {
+			return def
+		}
+	}
+	return nil
+}
+
+// names returns the names of the given local variables.
+func names(lvs []*metadata.DILocalVariable) []string {
+	var ss []string
+	for _, lv := range lvs {
+		ss = append(ss, lv.Name)
+	}
+	return ss
+}
+
+// equalStrings reports whether the given string slices are equal.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
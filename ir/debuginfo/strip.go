@@ -0,0 +1,150 @@
+package debuginfo
+
+import (
+	"strings"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/metadata"
+)
+
+// === [ Strip debug information ] =============================================
+
+// Strip removes all debug information from the given module, and reports
+// whether the module was changed.
+//
+// The following debug information is removed:
+//
+//    * !dbg metadata attachments of global variables, functions, instructions
+//      and terminators.
+//    * calls to llvm.dbg.* intrinsics, and declarations of llvm.dbg.*
+//      intrinsics.
+//    * source locations of !llvm.loop metadata attachments.
+//    * llvm.dbg.* named metadata definitions (e.g. !llvm.dbg.cu).
+//    * the "Debug Info Version" and "Dwarf Version" module flags.
+//    * metadata definitions no longer reachable from the module.
+func Strip(m *ir.Module) bool {
+	changed := false
+	removeDbg := func(v interface{}) {
+		if ir.RemoveMDAttachment(v, "dbg") {
+			changed = true
+		}
+	}
+	// Remove !dbg attachments and llvm.dbg.* intrinsic calls.
+	for _, g := range m.Globals {
+		removeDbg(g)
+	}
+	for _, f := range m.Funcs {
+		removeDbg(f)
+		for _, block := range f.Blocks {
+			var insts []ir.Instruction
+			for _, inst := range block.Insts {
+				if isDbgIntrinsicCall(inst) {
+					changed = true
+					continue
+				}
+				removeDbg(inst)
+				insts = append(insts, inst)
+			}
+			block.Insts = insts
+			removeDbg(block.Term)
+			if stripLoopLocations(block.Term) {
+				changed = true
+			}
+		}
+	}
+	// Remove llvm.dbg.* intrinsic declarations.
+	var funcs []*ir.Function
+	for _, f := range m.Funcs {
+		if len(f.Blocks) == 0 && isDbgIntrinsic(f) {
+			changed = true
+			continue
+		}
+		funcs = append(funcs, f)
+	}
+	m.Funcs = funcs
+	// Remove debug info named metadata and module flags.
+	var named []*metadata.NamedMetadataDef
+	for _, md := range m.NamedMetadataDefs {
+		switch {
+		case strings.HasPrefix(md.Name, "llvm.dbg."):
+			changed = true
+			continue
+		case md.Name == "llvm.module.flags":
+			var nodes []metadata.MetadataNode
+			for _, node := range md.Nodes {
+				if isDebugModuleFlag(node) {
+					changed = true
+					continue
+				}
+				nodes = append(nodes, node)
+			}
+			md.Nodes = nodes
+			if len(md.Nodes) == 0 {
+				continue
+			}
+		}
+		named = append(named, md)
+	}
+	m.NamedMetadataDefs = named
+	// Remove unreachable metadata definitions.
+	if irutil.StripUnreachableMetadata(m) > 0 {
+		changed = true
+	}
+	return changed
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isDbgIntrinsic reports whether the given function is a llvm.dbg.* intrinsic.
+func isDbgIntrinsic(f *ir.Function) bool {
+	return strings.HasPrefix(f.Name(), "llvm.dbg.")
+}
+
+// isDbgIntrinsicCall reports whether the given instruction is a call to a
+// llvm.dbg.* intrinsic.
+func isDbgIntrinsicCall(inst ir.Instruction) bool {
+	call, ok := inst.(*ir.InstCall)
+	if !ok {
+		return false
+	}
+	callee, ok := call.Callee.(*ir.Function)
+	return ok && isDbgIntrinsic(callee)
+}
+
+// stripLoopLocations removes the source locations of the !llvm.loop metadata
+// attached to the given terminator, and reports whether any were removed.
+func stripLoopLocations(term ir.Terminator) bool {
+	loop, ok := Node(ir.MDAttachment(term, "llvm.loop")).(*metadata.MDTuple)
+	if !ok {
+		return false
+	}
+	var fields []metadata.MDField
+	for _, field := range loop.Fields {
+		if _, ok := Node(field).(*metadata.DILocation); ok {
+			continue
+		}
+		fields = append(fields, field)
+	}
+	changed := len(fields) != len(loop.Fields)
+	loop.Fields = fields
+	return changed
+}
+
+// isDebugModuleFlag reports whether the given node is a debug information
+// module flag; i.e. "Debug Info Version" or "Dwarf Version".
+func isDebugModuleFlag(node metadata.MetadataNode) bool {
+	tuple, ok := Node(node).(*metadata.MDTuple)
+	if !ok || len(tuple.Fields) != 3 {
+		return false
+	}
+	key, ok := tuple.Fields[1].(*metadata.MDString)
+	if !ok {
+		return false
+	}
+	switch key.Value {
+	case "Debug Info Version", "Dwarf Version":
+		return true
+	}
+	return false
+}
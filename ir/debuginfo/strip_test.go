@@ -0,0 +1,79 @@
+package debuginfo
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestStrip(t *testing.T) {
+	const input = `
+@g = global i32 0, !dbg !10
+
+define void @f(i32 %x) !dbg !4 {
+	call void @llvm.dbg.value(metadata i32 %x, metadata !7, metadata !DIExpression()), !dbg !8
+	%y = add i32 %x, 1, !dbg !8, !tbaa !12
+	br label %loop, !llvm.loop !13
+
+loop:
+	ret void, !dbg !9
+}
+
+declare void @llvm.dbg.value(metadata, metadata, metadata)
+
+!llvm.dbg.cu = !{!0}
+!llvm.module.flags = !{!2, !3, !15}
+!llvm.ident = !{!16}
+
+!0 = distinct !DICompileUnit(language: DW_LANG_C99, file: !1, producer: "clang", emissionKind: FullDebug, globals: !17)
+!1 = !DIFile(filename: "f.c", directory: "/home/secret/src")
+!2 = !{i32 2, !"Dwarf Version", i32 4}
+!3 = !{i32 2, !"Debug Info Version", i32 3}
+!4 = distinct !DISubprogram(name: "f", scope: !1, file: !1, line: 1, unit: !0)
+!5 = !DIBasicType(name: "int", size: 32, encoding: DW_ATE_signed)
+!7 = !DILocalVariable(name: "x", arg: 1, scope: !4, file: !1, line: 1, type: !5)
+!8 = !DILocation(line: 2, column: 3, scope: !4)
+!9 = !DILocation(line: 3, column: 1, scope: !4)
+!10 = !DIGlobalVariableExpression(var: !11, expr: !DIExpression())
+!11 = distinct !DIGlobalVariable(name: "g", scope: !0, file: !1, line: 1, type: !5, isDefinition: true)
+!12 = !{!"int", !14}
+!13 = distinct !{!13, !8, !9}
+!14 = !{!"tbaa root"}
+!15 = !{i32 1, !"wchar_size", i32 4}
+!16 = !{!"clang version 7.0.0"}
+!17 = !{!10}
+`
+	const want = `@g = global i32 0
+
+define void @f(i32 %x) {
+; <label>:0
+	%y = add i32 %x, 1, !tbaa !12
+	br label %loop, !llvm.loop !13
+
+loop:
+	ret void
+}
+
+!llvm.module.flags = !{!15}
+!llvm.ident = !{!16}
+
+!12 = !{!"int", !14}
+!13 = distinct !{!13}
+!14 = !{!"tbaa root"}
+!15 = !{i32 1, !"wchar_size", i32 4}
+!16 = !{!"clang version 7.0.0"}
+`
+	m, err := asm.ParseString("strip.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	if !Strip(m) {
+		t.Errorf("expected module to be changed")
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if Strip(m) {
+		t.Errorf("expected stripped module to be unchanged")
+	}
+}
@@ -0,0 +1,216 @@
+package debuginfo
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/metadata"
+)
+
+// === [ Source-level types ] ==================================================
+
+// Type is a source-level type reconstructed from debug information type nodes
+// (DIBasicType, DIDerivedType, DICompositeType and DISubroutineType).
+type Type struct {
+	// DWARF tag of the type; e.g. DW_TAG_base_type or DW_TAG_pointer_type.
+	// Subroutine types use DW_TAG_subroutine_type.
+	Tag enum.DwarfTag
+	// Type name; or empty if anonymous.
+	Name string
+	// Size in bits; or 0 if unknown.
+	Size uint64
+	// Encoding of basic types.
+	Encoding enum.DwarfAttEncoding
+	// Base type of derived types (e.g. pointee type, typedef base type, member
+	// type), element type of array types, underlying type of enumeration types
+	// and return type of subroutine types; or nil for void.
+	Base *Type
+	// Members of structure, union and class types.
+	Members []*Member
+	// Element counts of array dimensions; -1 if unknown.
+	Dims []int64
+	// Enumerators of enumeration types.
+	Enumerators []*metadata.DIEnumerator
+	// Parameter types of subroutine types; a nil parameter type denotes
+	// variadic arguments.
+	Params []*Type
+}
+
+// Member is a member of a structure, union or class type.
+type Member struct {
+	// Member name.
+	Name string
+	// Offset in bits.
+	Offset uint64
+	// Member type.
+	Type *Type
+}
+
+// ResolveType returns the source-level type of the given debug information type
+// node, or nil (void) if t is nil or null. Types which refer to themselves (e.g.
+// linked list structures) are resolved to cyclic Type values.
+func ResolveType(t metadata.MDField) *Type {
+	r := &typeResolver{types: make(map[metadata.MDNode]*Type)}
+	return r.resolve(t)
+}
+
+// typeResolver resolves debug information type nodes to source-level types.
+type typeResolver struct {
+	// Resolved types, keyed by type node.
+	types map[metadata.MDNode]*Type
+}
+
+// resolve returns the source-level type of the given type node.
+func (r *typeResolver) resolve(t metadata.MDField) *Type {
+	node := Node(t)
+	if typ, ok := r.types[node]; ok {
+		return typ
+	}
+	typ := &Type{}
+	switch node := node.(type) {
+	case nil, *metadata.NullLit:
+		return nil
+	case *metadata.DIBasicType:
+		r.types[node] = typ
+		typ.Tag = node.Tag
+		if typ.Tag == 0 {
+			typ.Tag = enum.DwarfTagBaseType
+		}
+		typ.Name = node.Name
+		typ.Size = node.Size
+		typ.Encoding = node.Encoding
+	case *metadata.DIDerivedType:
+		r.types[node] = typ
+		typ.Tag = node.Tag
+		typ.Name = node.Name
+		typ.Size = node.Size
+		typ.Base = r.resolve(node.BaseType)
+	case *metadata.DICompositeType:
+		r.types[node] = typ
+		typ.Tag = node.Tag
+		typ.Name = node.Name
+		typ.Size = node.Size
+		typ.Base = r.resolve(node.BaseType)
+		for _, elem := range tupleFields(node.Elements) {
+			switch elem := Node(elem).(type) {
+			case *metadata.DIDerivedType:
+				if elem.Tag != enum.DwarfTagMember {
+					continue
+				}
+				m := &Member{
+					Name:   elem.Name,
+					Offset: elem.Offset,
+					Type:   r.resolve(elem.BaseType),
+				}
+				typ.Members = append(typ.Members, m)
+			case *metadata.DISubrange:
+				count := int64(-1)
+				if n, ok := elem.Count.(metadata.IntLit); ok {
+					count = int64(n)
+				}
+				typ.Dims = append(typ.Dims, count)
+			case *metadata.DIEnumerator:
+				typ.Enumerators = append(typ.Enumerators, elem)
+			}
+		}
+	case *metadata.DISubroutineType:
+		r.types[node] = typ
+		typ.Tag = enum.DwarfTagSubroutineType
+		for i, field := range tupleFields(node.Types) {
+			if i == 0 {
+				typ.Base = r.resolve(field)
+				continue
+			}
+			typ.Params = append(typ.Params, r.resolve(field))
+		}
+	default:
+		panic(fmt.Errorf("support for debug information type %T not yet implemented", node))
+	}
+	return typ
+}
+
+// String returns a C-like string representation of the source-level type.
+func (t *Type) String() string {
+	if t == nil {
+		return "void"
+	}
+	switch t.Tag {
+	case enum.DwarfTagPointerType:
+		return fmt.Sprintf("%s*", t.Base)
+	case enum.DwarfTagReferenceType:
+		return fmt.Sprintf("%s&", t.Base)
+	case enum.DwarfTagRvalueReferenceType:
+		return fmt.Sprintf("%s&&", t.Base)
+	case enum.DwarfTagConstType:
+		return fmt.Sprintf("const %s", t.Base)
+	case enum.DwarfTagVolatileType:
+		return fmt.Sprintf("volatile %s", t.Base)
+	case enum.DwarfTagRestrictType:
+		return fmt.Sprintf("%s restrict", t.Base)
+	case enum.DwarfTagAtomicType:
+		return fmt.Sprintf("_Atomic %s", t.Base)
+	case enum.DwarfTagArrayType:
+		buf := &strings.Builder{}
+		buf.WriteString(t.Base.String())
+		for _, n := range t.Dims {
+			if n < 0 {
+				buf.WriteString("[]")
+			} else {
+				fmt.Fprintf(buf, "[%d]", n)
+			}
+		}
+		return buf.String()
+	case enum.DwarfTagStructureType:
+		return compositeName("struct", t.Name)
+	case enum.DwarfTagUnionType:
+		return compositeName("union", t.Name)
+	case enum.DwarfTagClassType:
+		return compositeName("class", t.Name)
+	case enum.DwarfTagEnumerationType:
+		return compositeName("enum", t.Name)
+	case enum.DwarfTagSubroutineType:
+		buf := &strings.Builder{}
+		fmt.Fprintf(buf, "%s (", t.Base)
+		for i, param := range t.Params {
+			if i != 0 {
+				buf.WriteString(", ")
+			}
+			if param == nil {
+				buf.WriteString("...")
+				continue
+			}
+			buf.WriteString(param.String())
+		}
+		buf.WriteString(")")
+		return buf.String()
+	}
+	// Basic types, typedefs and other named types.
+	if len(t.Name) > 0 {
+		return t.Name
+	}
+	if t.Base != nil {
+		return t.Base.String()
+	}
+	return "void"
+}
+
+// ### [ Helper functions ] ####################################################
+
+// tupleFields returns the fields of the given metadata tuple, or nil if md is
+// not a tuple.
+func tupleFields(md metadata.MDField) []metadata.MDField {
+	if tuple, ok := Node(md).(*metadata.MDTuple); ok {
+		return tuple.Fields
+	}
+	return nil
+}
+
+// compositeName returns the name of a composite type with the given keyword
+// (e.g. "struct") and type name.
+func compositeName(keyword, name string) string {
+	if len(name) == 0 {
+		return fmt.Sprintf("%s <anonymous>", keyword)
+	}
+	return fmt.Sprintf("%s %s", keyword, name)
+}
@@ -106,6 +106,13 @@ func (f *Function) Type() types.Type {
 // Def returns the LLVM syntax representation of the function definition or
 // declaration.
 func (f *Function) Def() string {
+	return f.def(nil)
+}
+
+// def returns the LLVM syntax representation of the function definition or
+// declaration, including the given comments of the function, its basic blocks
+// and instructions.
+func (f *Function) def(comments CommentMap) string {
 	// Function declaration.
 	//
 	//    'declare' Metadata=MetadataAttachment* Header=FuncHeader
@@ -124,6 +131,7 @@ func (f *Function) Def() string {
 			fmt.Fprintf(buf, " %s", f.Linkage)
 		}
 		buf.WriteString(headerString(f))
+		buf.WriteString(comments.trailing(f))
 		return buf.String()
 	}
 	// Function definition.
@@ -138,7 +146,7 @@ func (f *Function) Def() string {
 	for _, md := range f.Metadata {
 		fmt.Fprintf(buf, " %s", md)
 	}
-	fmt.Fprintf(buf, " %s", bodyString(f))
+	fmt.Fprintf(buf, " %s", bodyString(f, comments))
 	return buf.String()
 }
 
@@ -274,15 +282,15 @@ func headerString(f *Function) string {
 }
 
 // bodyString returns the string representation of the function body.
-func bodyString(body *Function) string {
+func bodyString(body *Function, comments CommentMap) string {
 	// '{' Blocks=BasicBlock+ UseListOrders=UseListOrder* '}'
 	buf := &strings.Builder{}
-	buf.WriteString("{\n")
+	fmt.Fprintf(buf, "{%s\n", comments.trailing(body))
 	for i, block := range body.Blocks {
 		if i != 0 {
 			buf.WriteString("\n")
 		}
-		fmt.Fprintf(buf, "%s\n", block.Def())
+		fmt.Fprintf(buf, "%s\n", block.def(comments))
 	}
 	if len(body.UseListOrders) > 0 {
 		buf.WriteString("\n")
@@ -290,6 +298,7 @@ func bodyString(body *Function) string {
 	for _, u := range body.UseListOrders {
 		fmt.Fprintf(buf, "\t%s\n", u)
 	}
+	comments.writeFinal(buf, body, "\t")
 	buf.WriteString("}")
 	return buf.String()
 }
@@ -1,5 +1,9 @@
 package ir
 
+import (
+	"fmt"
+)
+
 // NewBlock appends a new basic block to the function based on the given label
 // name. An empty label name indicates an unnamed basic block.
 func (f *Function) NewBlock(name string) *BasicBlock {
@@ -8,3 +12,21 @@ func (f *Function) NewBlock(name string) *BasicBlock {
 	f.Blocks = append(f.Blocks, block)
 	return block
 }
+
+// NewBlockAfter inserts a new basic block after the given basic block of the
+// function based on the given label name. An empty label name indicates an
+// unnamed basic block.
+func (f *Function) NewBlockAfter(prev *BasicBlock, name string) *BasicBlock {
+	for i, b := range f.Blocks {
+		if b != prev {
+			continue
+		}
+		block := NewBlock(name)
+		block.Parent = f
+		f.Blocks = append(f.Blocks, nil)
+		copy(f.Blocks[i+2:], f.Blocks[i+1:])
+		f.Blocks[i+1] = block
+		return block
+	}
+	panic(fmt.Errorf("unable to locate basic block %s in function %s", prev.Ident(), f.Ident()))
+}
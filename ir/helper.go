@@ -387,3 +387,46 @@ func tlsModelString(model enum.TLSModel) string {
 	}
 	return fmt.Sprintf("thread_local(%s)", model)
 }
+
+// uses returns the operand slots of the given value fields.
+func uses(ps ...*value.Value) []*value.Use {
+	ops := make([]*value.Use, len(ps))
+	for i, p := range ps {
+		ops[i] = value.NewUse(p)
+	}
+	return ops
+}
+
+// sliceUses returns the operand slots of the given slice of values.
+func sliceUses(vs []value.Value) []*value.Use {
+	ops := make([]*value.Use, len(vs))
+	for i := range vs {
+		ops[i] = value.NewUse(&vs[i])
+	}
+	return ops
+}
+
+// bundleUses returns the operand slots of the inputs of the given operand
+// bundles.
+func bundleUses(bundles []*OperandBundle) []*value.Use {
+	var ops []*value.Use
+	for _, bundle := range bundles {
+		ops = append(ops, sliceUses(bundle.Inputs)...)
+	}
+	return ops
+}
+
+// scopeUse returns the operand slot of the given exception scope field.
+func scopeUse(p *ExceptionScope) *value.Use {
+	return &value.Use{
+		Get: func() value.Value {
+			if *p == nil {
+				return nil
+			}
+			return *p
+		},
+		Set: func(v value.Value) {
+			*p = v
+		},
+	}
+}
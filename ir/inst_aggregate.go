@@ -69,6 +69,11 @@ func (inst *InstExtractValue) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstExtractValue) Operands() []*value.Use {
+	return uses(&inst.X)
+}
+
 // ~~~ [ insertvalue ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstInsertValue is an LLVM IR insertvalue instruction.
@@ -129,6 +134,11 @@ func (inst *InstInsertValue) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstInsertValue) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Elem)
+}
+
 // ### [ Helper functions ] ####################################################
 
 // aggregateElemType returns the element type at the position in the aggregate
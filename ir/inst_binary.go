@@ -71,6 +71,11 @@ func (inst *InstAdd) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstAdd) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ fadd ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFAdd is an LLVM IR fadd instruction.
@@ -130,6 +135,11 @@ func (inst *InstFAdd) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFAdd) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ sub ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstSub is an LLVM IR sub instruction.
@@ -189,6 +199,11 @@ func (inst *InstSub) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstSub) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ fsub ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFSub is an LLVM IR fsub instruction.
@@ -248,6 +263,11 @@ func (inst *InstFSub) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFSub) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ mul ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstMul is an LLVM IR mul instruction.
@@ -307,6 +327,11 @@ func (inst *InstMul) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstMul) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ fmul ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFMul is an LLVM IR fmul instruction.
@@ -366,6 +391,11 @@ func (inst *InstFMul) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFMul) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ udiv ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstUDiv is an LLVM IR udiv instruction.
@@ -425,6 +455,11 @@ func (inst *InstUDiv) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstUDiv) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ sdiv ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstSDiv is an LLVM IR sdiv instruction.
@@ -484,6 +519,11 @@ func (inst *InstSDiv) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstSDiv) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ fdiv ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFDiv is an LLVM IR fdiv instruction.
@@ -543,6 +583,11 @@ func (inst *InstFDiv) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFDiv) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ urem ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstURem is an LLVM IR urem instruction.
@@ -595,6 +640,11 @@ func (inst *InstURem) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstURem) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ srem ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstSRem is an LLVM IR srem instruction.
@@ -647,6 +697,11 @@ func (inst *InstSRem) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstSRem) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ frem ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFRem is an LLVM IR frem instruction.
@@ -705,3 +760,8 @@ func (inst *InstFRem) Def() string {
 	}
 	return buf.String()
 }
+
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFRem) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
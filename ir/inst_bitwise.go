@@ -71,6 +71,11 @@ func (inst *InstShl) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstShl) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ lshr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstLShr is an LLVM IR lshr instruction.
@@ -130,6 +135,11 @@ func (inst *InstLShr) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstLShr) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ ashr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstAShr is an LLVM IR ashr instruction.
@@ -189,6 +199,11 @@ func (inst *InstAShr) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstAShr) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ and ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstAnd is an LLVM IR and instruction.
@@ -241,6 +256,11 @@ func (inst *InstAnd) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstAnd) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ or ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstOr is an LLVM IR or instruction.
@@ -293,6 +313,11 @@ func (inst *InstOr) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstOr) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ xor ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstXor is an LLVM IR xor instruction.
@@ -344,3 +369,8 @@ func (inst *InstXor) Def() string {
 	}
 	return buf.String()
 }
+
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstXor) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
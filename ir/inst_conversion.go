@@ -57,6 +57,11 @@ func (inst *InstTrunc) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstTrunc) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ zext ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstZExt is an LLVM IR zext instruction.
@@ -103,6 +108,11 @@ func (inst *InstZExt) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstZExt) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ sext ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstSExt is an LLVM IR sext instruction.
@@ -149,6 +159,11 @@ func (inst *InstSExt) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstSExt) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ fptrunc ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFPTrunc is an LLVM IR fptrunc instruction.
@@ -195,6 +210,11 @@ func (inst *InstFPTrunc) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFPTrunc) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ fpext ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFPExt is an LLVM IR fpext instruction.
@@ -241,6 +261,11 @@ func (inst *InstFPExt) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFPExt) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ fptoui ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFPToUI is an LLVM IR fptoui instruction.
@@ -287,6 +312,11 @@ func (inst *InstFPToUI) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFPToUI) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ fptosi ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFPToSI is an LLVM IR fptosi instruction.
@@ -333,6 +363,11 @@ func (inst *InstFPToSI) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFPToSI) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ uitofp ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstUIToFP is an LLVM IR uitofp instruction.
@@ -379,6 +414,11 @@ func (inst *InstUIToFP) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstUIToFP) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ sitofp ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstSIToFP is an LLVM IR sitofp instruction.
@@ -425,6 +465,11 @@ func (inst *InstSIToFP) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstSIToFP) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ ptrtoint ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstPtrToInt is an LLVM IR ptrtoint instruction.
@@ -471,6 +516,11 @@ func (inst *InstPtrToInt) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstPtrToInt) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ inttoptr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstIntToPtr is an LLVM IR inttoptr instruction.
@@ -517,6 +567,11 @@ func (inst *InstIntToPtr) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstIntToPtr) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ bitcast ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstBitCast is an LLVM IR bitcast instruction.
@@ -563,6 +618,11 @@ func (inst *InstBitCast) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstBitCast) Operands() []*value.Use {
+	return uses(&inst.From)
+}
+
 // ~~~ [ addrspacecast ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstAddrSpaceCast is an LLVM IR addrspacecast instruction.
@@ -609,3 +669,8 @@ func (inst *InstAddrSpaceCast) Def() string {
 	}
 	return buf.String()
 }
+
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstAddrSpaceCast) Operands() []*value.Use {
+	return uses(&inst.From)
+}
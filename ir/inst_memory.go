@@ -91,6 +91,14 @@ func (inst *InstAlloca) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstAlloca) Operands() []*value.Use {
+	if inst.NElems == nil {
+		return nil
+	}
+	return uses(&inst.NElems)
+}
+
 // ~~~ [ load ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstLoad is an LLVM IR load instruction.
@@ -182,6 +190,11 @@ func (inst *InstLoad) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstLoad) Operands() []*value.Use {
+	return uses(&inst.Src)
+}
+
 // ~~~ [ store ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstStore is an LLVM IR store instruction.
@@ -249,6 +262,11 @@ func (inst *InstStore) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstStore) Operands() []*value.Use {
+	return uses(&inst.Src, &inst.Dst)
+}
+
 // ~~~ [ fence ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFence is an LLVM IR fence instruction.
@@ -285,6 +303,11 @@ func (inst *InstFence) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFence) Operands() []*value.Use {
+	return nil
+}
+
 // ~~~ [ cmpxchg ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstCmpXchg is an LLVM IR cmpxchg instruction.
@@ -369,6 +392,11 @@ func (inst *InstCmpXchg) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstCmpXchg) Operands() []*value.Use {
+	return uses(&inst.Ptr, &inst.Cmp, &inst.New)
+}
+
 // ~~~ [ atomicrmw ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstAtomicRMW is an LLVM IR atomicrmw instruction.
@@ -445,6 +473,11 @@ func (inst *InstAtomicRMW) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstAtomicRMW) Operands() []*value.Use {
+	return uses(&inst.Dst, &inst.X)
+}
+
 // ~~~ [ getelementptr ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstGetElementPtr is an LLVM IR getelementptr instruction.
@@ -527,6 +560,11 @@ func (inst *InstGetElementPtr) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstGetElementPtr) Operands() []*value.Use {
+	return append(uses(&inst.Src), sliceUses(inst.Indices)...)
+}
+
 // ### [ Helper functions ] ####################################################
 
 // gepType returns the pointer type or vector of pointers type to the element at
@@ -591,7 +629,7 @@ func gepType(elemType types.Type, indices []value.Value) types.Type {
 	//    store <2 x %struct.fileinfo*> %113, <2 x %struct.fileinfo*>* %116, align 8, !dbg !4738, !tbaa !1793
 	if len(indices) > 0 {
 		if t, ok := indices[0].Type().(*types.VectorType); ok {
-			return types.NewVector(t.Len, types.NewPointer(e))
+			return &types.VectorType{Scalable: t.Scalable, Len: t.Len, ElemType: types.NewPointer(e)}
 		}
 	}
 	return types.NewPointer(e)
@@ -54,7 +54,7 @@ func (inst *InstICmp) Type() types.Type {
 		case *types.IntType, *types.PointerType:
 			inst.Typ = types.I1
 		case *types.VectorType:
-			inst.Typ = types.NewVector(xType.Len, types.I1)
+			inst.Typ = &types.VectorType{Scalable: xType.Scalable, Len: xType.Len, ElemType: types.I1}
 		default:
 			panic(fmt.Errorf("invalid icmp operand type; expected *types.IntType, *types.PointerType or *types.VectorType, got %T", xType))
 		}
@@ -75,6 +75,11 @@ func (inst *InstICmp) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstICmp) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ fcmp ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstFCmp is an LLVM IR fcmp instruction.
@@ -119,7 +124,7 @@ func (inst *InstFCmp) Type() types.Type {
 		case *types.FloatType:
 			inst.Typ = types.I1
 		case *types.VectorType:
-			inst.Typ = types.NewVector(xType.Len, types.I1)
+			inst.Typ = &types.VectorType{Scalable: xType.Scalable, Len: xType.Len, ElemType: types.I1}
 		default:
 			panic(fmt.Errorf("invalid fcmp operand type; expected *types.FloatType or *types.VectorType, got %T", xType))
 		}
@@ -144,6 +149,11 @@ func (inst *InstFCmp) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstFCmp) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y)
+}
+
 // ~~~ [ phi ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstPhi is an LLVM IR phi instruction.
@@ -203,6 +213,15 @@ func (inst *InstPhi) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstPhi) Operands() []*value.Use {
+	ops := make([]*value.Use, 0, len(inst.Incs))
+	for _, inc := range inst.Incs {
+		ops = append(ops, value.NewUse(&inc.X))
+	}
+	return ops
+}
+
 // ___ [ Incoming value ] ______________________________________________________
 
 // Incoming is an incoming value of a phi instruction.
@@ -281,6 +300,11 @@ func (inst *InstSelect) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstSelect) Operands() []*value.Use {
+	return uses(&inst.Cond, &inst.X, &inst.Y)
+}
+
 // ~~~ [ call ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstCall is an LLVM IR call instruction.
@@ -419,6 +443,12 @@ func (inst *InstCall) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstCall) Operands() []*value.Use {
+	ops := append(uses(&inst.Callee), sliceUses(inst.Args)...)
+	return append(ops, bundleUses(inst.OperandBundles)...)
+}
+
 // ~~~ [ va_arg ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstVAArg is an LLVM IR va_arg instruction.
@@ -466,6 +496,11 @@ func (inst *InstVAArg) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstVAArg) Operands() []*value.Use {
+	return uses(&inst.ArgList)
+}
+
 // ~~~ [ landingpad ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstLandingPad is an LLVM IR landingpad instruction.
@@ -522,6 +557,15 @@ func (inst *InstLandingPad) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstLandingPad) Operands() []*value.Use {
+	ops := make([]*value.Use, 0, len(inst.Clauses))
+	for _, clause := range inst.Clauses {
+		ops = append(ops, value.NewUse(&clause.X))
+	}
+	return ops
+}
+
 // ___ [ Landingpad clause ] ___________________________________________________
 
 // Clause is a landingpad catch or filter clause.
@@ -601,6 +645,26 @@ func (inst *InstCatchPad) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstCatchPad) Operands() []*value.Use {
+	scope := &value.Use{
+		Get: func() value.Value {
+			if inst.Scope == nil {
+				return nil
+			}
+			return inst.Scope
+		},
+		Set: func(v value.Value) {
+			scope, ok := v.(*TermCatchSwitch)
+			if !ok {
+				panic(fmt.Errorf("invalid catchpad scope; expected *ir.TermCatchSwitch, got %T", v))
+			}
+			inst.Scope = scope
+		},
+	}
+	return append([]*value.Use{scope}, sliceUses(inst.Args)...)
+}
+
 // ~~~ [ cleanuppad ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstCleanupPad is an LLVM IR cleanuppad instruction.
@@ -658,3 +722,8 @@ func (inst *InstCleanupPad) Def() string {
 	}
 	return buf.String()
 }
+
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstCleanupPad) Operands() []*value.Use {
+	return append([]*value.Use{scopeUse(&inst.Scope)}, sliceUses(inst.Args)...)
+}
@@ -71,6 +71,11 @@ func (inst *InstExtractElement) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstExtractElement) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Index)
+}
+
 // ~~~ [ insertelement ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstInsertElement is an LLVM IR insertelement instruction.
@@ -133,6 +138,11 @@ func (inst *InstInsertElement) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstInsertElement) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Elem, &inst.Index)
+}
+
 // ~~~ [ shufflevector ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // InstShuffleVector is an LLVM IR shufflevector instruction.
@@ -179,7 +189,7 @@ func (inst *InstShuffleVector) Type() types.Type {
 		if !ok {
 			panic(fmt.Errorf("invalid vector type; expected *types.VectorType, got %T", inst.Mask.Type()))
 		}
-		inst.Typ = types.NewVector(maskType.Len, xType.ElemType)
+		inst.Typ = &types.VectorType{Scalable: maskType.Scalable, Len: maskType.Len, ElemType: xType.ElemType}
 	}
 	return inst.Typ
 }
@@ -196,3 +206,8 @@ func (inst *InstShuffleVector) Def() string {
 	}
 	return buf.String()
 }
+
+// Operands returns a mutable list of operands of the given instruction.
+func (inst *InstShuffleVector) Operands() []*value.Use {
+	return uses(&inst.X, &inst.Y, &inst.Mask)
+}
@@ -1,5 +1,9 @@
 package ir
 
+import (
+	"github.com/llir/llvm/ir/value"
+)
+
 // === [ Instructions ] ========================================================
 
 // Instruction is an LLVM IR instruction. All instructions (except store and
@@ -97,6 +101,8 @@ package ir
 type Instruction interface {
 	// Def returns the LLVM syntax representation of the instruction.
 	Def() string
+	// Operands returns a mutable list of operands of the instruction.
+	Operands() []*value.Use
 	// isInstruction ensures that only instructions can be assigned to the
 	// instruction.Instruction interface.
 	isInstruction()
@@ -0,0 +1,353 @@
+package irutil
+
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"strings"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Structural equality ] =================================================
+
+// EqualFuncs reports whether the given functions are structurally equal; i.e.
+// whether they have equal signatures, function attributes, calling
+// conventions, parameter attributes, prefix, prologue, personality and bodies.
+//
+// The comparison is insensitive to the names and IDs of local variables
+// (function parameters, basic blocks and instructions) and to the name of the
+// functions themselves, as recursive references to the function are treated as
+// equal. Linkage, visibility, DLL storage class, unnamed address, section,
+// Comdat, use-list orders and metadata attachments are not compared.
+func EqualFuncs(f, g *ir.Function) bool {
+	return encodeFunc(f, false) == encodeFunc(g, false)
+}
+
+// HashFunc returns a stable hash of the given function, which is equal for
+// structurally equal functions (see EqualFuncs).
+func HashFunc(f *ir.Function) uint64 {
+	return hash(encodeFunc(f, false))
+}
+
+// EqualModules reports whether the given modules are structurally equal; i.e.
+// whether they have equal type definitions, global variables, functions,
+// aliases, IFuncs, Comdat definitions, module-level inline assembly, data
+// layout and target triple.
+//
+// Global values are compared in order of occurrence, including their names and
+// linkage. The comparison is insensitive to the names and IDs of local
+// variables, and to the IDs of attribute groups. Source filename, metadata
+// definitions, metadata attachments and use-list orders are not compared.
+func EqualModules(a, b *ir.Module) bool {
+	return encodeModule(a) == encodeModule(b)
+}
+
+// HashModule returns a stable hash of the given module, which is equal for
+// structurally equal modules (see EqualModules).
+func HashModule(m *ir.Module) uint64 {
+	return hash(encodeModule(m))
+}
+
+// InstKey returns a canonical key of the given instruction or terminator, which
+// is equal for structurally equal instructions. Each operand (including basic
+// blocks) for which local reports ok is encoded by the string returned by
+// local; e.g. to identify corresponding local variables of different functions.
+// Other local operands are encoded by name, and metadata attachments are
+// ignored.
+func InstKey(inst interface{}, local func(v value.Value) (string, bool)) string {
+	e := &encoder{local: local}
+	e.inst(inst)
+	return e.buf.String()
+}
+
+// hash returns the 64-bit FNV-1a hash of the given canonical encoding.
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// --- [ Canonical encoding ] --------------------------------------------------
+
+// skipFields specifies the struct fields omitted from canonical encodings;
+// identifiers, cached or derived values, back references and metadata.
+var skipFields = map[string]bool{
+	"GlobalIdent":   true,
+	"LocalIdent":    true,
+	"Typ":           true, // encoded through the Type method.
+	"Successors":    true,
+	"Parent":        true,
+	"Metadata":      true,
+	"UseListOrders": true,
+}
+
+// linkageFields specifies the struct fields of global values which are only
+// included in module encodings.
+var linkageFields = map[string]bool{
+	"Linkage":         true,
+	"Preemption":      true,
+	"Visibility":      true,
+	"DLLStorageClass": true,
+	"UnnamedAddr":     true,
+	"Section":         true,
+	"Comdat":          true,
+}
+
+// encoder produces canonical encodings of LLVM IR functions and modules.
+type encoder struct {
+	// Output buffer.
+	buf strings.Builder
+	// Function being encoded; or nil if not present.
+	self *ir.Function
+	// Index of each local variable of the function being encoded (function
+	// parameters, basic blocks, instructions and terminators).
+	locals map[value.Value]int
+	// (optional) Encoding of operands; reports ok if encoded.
+	local func(v value.Value) (string, bool)
+	// Include linkage fields of global values.
+	linkage bool
+}
+
+// encodeFunc returns the canonical encoding of the given function. Linkage
+// fields are included if linkage is set.
+func encodeFunc(f *ir.Function, linkage bool) string {
+	e := &encoder{linkage: linkage}
+	e.function(f)
+	return e.buf.String()
+}
+
+// encodeModule returns the canonical encoding of the given module.
+func encodeModule(m *ir.Module) string {
+	e := &encoder{linkage: true}
+	for _, t := range m.TypeDefs {
+		fmt.Fprintf(&e.buf, "type %s = %s\n", t, t.Def())
+	}
+	for _, g := range m.Globals {
+		fmt.Fprintf(&e.buf, "global %s", g.Ident())
+		e.fields(reflect.ValueOf(g).Elem())
+		e.buf.WriteString("\n")
+	}
+	for _, f := range m.Funcs {
+		fmt.Fprintf(&e.buf, "func %s", f.Ident())
+		e.function(f)
+	}
+	for _, alias := range m.Aliases {
+		fmt.Fprintf(&e.buf, "alias %s", alias.Ident())
+		e.fields(reflect.ValueOf(alias).Elem())
+		e.buf.WriteString("\n")
+	}
+	for _, ifunc := range m.IFuncs {
+		fmt.Fprintf(&e.buf, "ifunc %s", ifunc.Ident())
+		e.fields(reflect.ValueOf(ifunc).Elem())
+		e.buf.WriteString("\n")
+	}
+	for _, def := range m.ComdatDefs {
+		fmt.Fprintf(&e.buf, "comdat %s\n", def.Def())
+	}
+	for _, asm := range m.ModuleAsms {
+		fmt.Fprintf(&e.buf, "asm %q\n", asm)
+	}
+	fmt.Fprintf(&e.buf, "datalayout %q\n", m.DataLayout)
+	fmt.Fprintf(&e.buf, "triple %q\n", m.TargetTriple)
+	return e.buf.String()
+}
+
+// function encodes the given function.
+func (e *encoder) function(f *ir.Function) {
+	e.self = f
+	e.locals = make(map[value.Value]int)
+	defer func() {
+		e.self = nil
+		e.locals = nil
+	}()
+	// Index local variables before encoding, as instructions may refer to
+	// subsequent basic blocks and instructions.
+	add := func(v value.Value) {
+		e.locals[v] = len(e.locals)
+	}
+	for _, param := range f.Params {
+		add(param)
+	}
+	for _, block := range f.Blocks {
+		add(block)
+		for _, inst := range block.Insts {
+			if v, ok := inst.(value.Value); ok {
+				add(v)
+			}
+		}
+		if v, ok := block.Term.(value.Value); ok {
+			add(v)
+		}
+	}
+	e.fields(reflect.ValueOf(f).Elem())
+	e.buf.WriteString("\n")
+	for _, param := range f.Params {
+		e.buf.WriteString("param")
+		e.fields(reflect.ValueOf(param).Elem())
+		e.buf.WriteString("\n")
+	}
+	for _, block := range f.Blocks {
+		fmt.Fprintf(&e.buf, "block %d\n", e.locals[block])
+		for _, inst := range block.Insts {
+			e.inst(inst)
+		}
+		if block.Term != nil {
+			e.inst(block.Term)
+		}
+	}
+}
+
+// inst encodes the given instruction or terminator.
+func (e *encoder) inst(inst interface{}) {
+	v := reflect.ValueOf(inst)
+	e.buf.WriteString(v.Elem().Type().Name())
+	e.fields(v.Elem())
+	e.buf.WriteString("\n")
+}
+
+// fields encodes the exported fields of the given struct value.
+func (e *encoder) fields(v reflect.Value) {
+	t := v.Type()
+	e.buf.WriteString(" {")
+	if x, ok := v.Addr().Interface().(value.Value); ok {
+		// Type of result, as the Typ field may be nil or hold an explicit type
+		// other than the type of the value (e.g. the callee signature of call
+		// instructions).
+		fmt.Fprintf(&e.buf, "%s;", x.Type())
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || skipFields[field.Name] {
+			continue
+		}
+		if !e.linkage && linkageFields[field.Name] {
+			switch v.Addr().Interface().(type) {
+			case *ir.Global, *ir.Function, *ir.Alias, *ir.IFunc:
+				continue
+			}
+		}
+		fmt.Fprintf(&e.buf, "%s:", field.Name)
+		e.field(v.Field(i))
+		e.buf.WriteString(";")
+	}
+	e.buf.WriteString("}")
+}
+
+// field encodes the given struct field value.
+func (e *encoder) field(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		if v.IsNil() {
+			e.buf.WriteString("nil")
+			return
+		}
+		switch x := v.Interface().(type) {
+		case value.Value:
+			e.value(x)
+			return
+		case types.Type:
+			e.buf.WriteString(x.String())
+			return
+		case *ir.AttrGroupDef:
+			// Attribute groups are compared by contents, not by ID.
+			e.field(reflect.ValueOf(x.FuncAttrs))
+			return
+		}
+		if v.Kind() == reflect.Interface {
+			e.field(v.Elem())
+			return
+		}
+		if v.Elem().Kind() == reflect.Struct {
+			e.fields(v.Elem())
+			return
+		}
+		e.field(v.Elem())
+	case reflect.Slice, reflect.Array:
+		e.buf.WriteString("[")
+		for i := 0; i < v.Len(); i++ {
+			if i != 0 {
+				e.buf.WriteString(",")
+			}
+			e.field(v.Index(i))
+		}
+		e.buf.WriteString("]")
+	case reflect.Struct:
+		if v.CanAddr() {
+			e.fields(v)
+			return
+		}
+		// Make a copy of the struct to allow method lookup on its address.
+		p := reflect.New(v.Type())
+		p.Elem().Set(v)
+		e.fields(p.Elem())
+	default:
+		fmt.Fprintf(&e.buf, "%v", v.Interface())
+	}
+}
+
+// value encodes the given operand value.
+func (e *encoder) value(v value.Value) {
+	if e.local != nil {
+		if s, ok := e.local(v); ok {
+			e.buf.WriteString(s)
+			return
+		}
+	}
+	if i, ok := e.locals[v]; ok {
+		fmt.Fprintf(&e.buf, "%%%d", i)
+		return
+	}
+	if v == e.self && e.self != nil {
+		e.buf.WriteString("self")
+		return
+	}
+	switch v := v.(type) {
+	case *ir.Global, *ir.Function, *ir.Alias, *ir.IFunc:
+		e.buf.WriteString(v.Ident())
+	case *ir.BasicBlock:
+		// Basic block of another function (e.g. in blockaddress constants),
+		// identified by its index in the parent function.
+		if v.Parent == nil {
+			e.buf.WriteString(v.Ident())
+			return
+		}
+		for i, block := range v.Parent.Blocks {
+			if block == v {
+				fmt.Fprintf(&e.buf, "%s:%d", v.Parent.Ident(), i)
+				return
+			}
+		}
+		e.buf.WriteString(v.Ident())
+	case *metadata.Value:
+		if x, ok := v.Value.(value.Value); ok {
+			e.buf.WriteString("metadata ")
+			e.value(x)
+			return
+		}
+		e.buf.WriteString(v.String())
+	case *ir.InlineAsm:
+		e.buf.WriteString(v.String())
+	case constant.Expression, *constant.Array, *constant.Struct, *constant.Vector, *constant.BlockAddress:
+		// Constants which may refer to local variables or to the function being
+		// encoded.
+		fmt.Fprintf(&e.buf, "%s", reflect.TypeOf(v).Elem().Name())
+		e.fields(reflect.ValueOf(v).Elem())
+	case constant.Constant:
+		e.buf.WriteString(v.String())
+	default:
+		// Compound values, such as function arguments with parameter
+		// attributes.
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr && rv.Elem().Kind() == reflect.Struct {
+			fmt.Fprintf(&e.buf, "%s", rv.Elem().Type().Name())
+			e.fields(rv.Elem())
+			return
+		}
+		e.buf.WriteString(v.String())
+	}
+}
@@ -0,0 +1,169 @@
+package irutil
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+)
+
+func TestEqualFuncs(t *testing.T) {
+	const input = `
+define i32 @f(i32 %x) {
+entry:
+	%y = add i32 %x, 1
+	br label %exit
+exit:
+	%z = call i32 @f(i32 %y)
+	ret i32 %z
+}
+
+define i32 @renamed(i32) {
+; <label>:1
+	%2 = add i32 %0, 1
+	br label %3
+; <label>:3
+	%4 = call i32 @renamed(i32 %2)
+	ret i32 %4
+}
+
+define i32 @other.const(i32 %x) {
+entry:
+	%y = add i32 %x, 2
+	br label %exit
+exit:
+	%z = call i32 @other.const(i32 %y)
+	ret i32 %z
+}
+
+define i32 @other.callee(i32 %x) {
+entry:
+	%y = add i32 %x, 1
+	br label %exit
+exit:
+	%z = call i32 @f(i32 %y)
+	ret i32 %z
+}
+
+define i32 @other.order(i32 %x) {
+entry:
+	%y = add i32 1, %x
+	br label %exit
+exit:
+	%z = call i32 @other.order(i32 %y)
+	ret i32 %z
+}
+
+define i32 @other.flags(i32 %x) {
+entry:
+	%y = add nsw i32 %x, 1
+	br label %exit
+exit:
+	%z = call i32 @other.flags(i32 %y)
+	ret i32 %z
+}
+
+define internal i32 @other.linkage(i32 %x) !dbg !0 {
+entry:
+	%y = add i32 %x, 1
+	br label %exit
+exit:
+	%z = call i32 @other.linkage(i32 %y)
+	ret i32 %z
+}
+
+!0 = !{}
+`
+	m, err := asm.ParseString("equal.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	funcs := make(map[string]*ir.Function)
+	for _, f := range m.Funcs {
+		funcs[f.Name()] = f
+	}
+	f := funcs["f"]
+	golden := []struct {
+		name string
+		want bool
+	}{
+		{name: "renamed", want: true},
+		{name: "other.const", want: false},
+		{name: "other.callee", want: false},
+		{name: "other.order", want: false},
+		{name: "other.flags", want: false},
+		{name: "other.linkage", want: true},
+	}
+	for _, g := range golden {
+		h := funcs[g.name]
+		if got := EqualFuncs(f, h); got != g.want {
+			t.Errorf("%q: equality mismatch; expected %v, got %v", g.name, g.want, got)
+		}
+		if got := HashFunc(f) == HashFunc(h); g.want && !got {
+			t.Errorf("%q: hash mismatch of equal functions", g.name)
+		}
+	}
+}
+
+func TestEqualModules(t *testing.T) {
+	const a = `
+%T = type { i32, %T* }
+
+@g = global %T zeroinitializer
+
+define void @f(%T* %p) #0 {
+entry:
+	%q = getelementptr %T, %T* %p, i32 0, i32 1
+	store %T* %p, %T** %q
+	ret void
+}
+
+attributes #0 = { nounwind }
+`
+	const b = `
+source_filename = "b.c"
+
+%T = type { i32, %T* }
+
+@g = global %T zeroinitializer
+
+define void @f(%T*) #1 {
+	%2 = getelementptr %T, %T* %0, i32 0, i32 1
+	store %T* %0, %T** %2
+	ret void
+}
+
+attributes #1 = { nounwind }
+`
+	const c = `
+%T = type { i32, %T* }
+
+@g = internal global %T zeroinitializer
+
+define void @f(%T* %p) #0 {
+entry:
+	%q = getelementptr %T, %T* %p, i32 0, i32 1
+	store %T* %p, %T** %q
+	ret void
+}
+
+attributes #0 = { nounwind }
+`
+	var ms []*ir.Module
+	for _, input := range []string{a, b, c} {
+		m, err := asm.ParseString("module.ll", input)
+		if err != nil {
+			t.Fatalf("unable to parse module; %v", err)
+		}
+		ms = append(ms, m)
+	}
+	if !EqualModules(ms[0], ms[1]) {
+		t.Errorf("expected modules a and b to be equal")
+	}
+	if HashModule(ms[0]) != HashModule(ms[1]) {
+		t.Errorf("hash mismatch of equal modules a and b")
+	}
+	if EqualModules(ms[0], ms[2]) {
+		t.Errorf("expected modules a and c to differ in linkage")
+	}
+}
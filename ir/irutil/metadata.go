@@ -0,0 +1,198 @@
+// Package irutil provides utility functions for traversing and transforming
+// LLVM IR modules.
+package irutil
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Metadata ] ============================================================
+
+// WalkMetadata traverses the metadata reachable from the given module in
+// depth-first order, invoking f for each metadata. Each metadata definition is
+// visited once.
+//
+// Metadata is reachable if referenced (directly or transitively) by named
+// metadata definitions, metadata attachments of global variables, functions,
+// instructions and terminators, or metadata arguments of instructions and
+// terminators (e.g. calls to llvm.dbg.value).
+func WalkMetadata(m *ir.Module, f func(md metadata.Metadata)) {
+	visited := make(map[*metadata.MetadataDef]bool)
+	var walk func(md metadata.Metadata)
+	walk = func(md metadata.Metadata) {
+		if def, ok := md.(*metadata.MetadataDef); ok {
+			if visited[def] {
+				return
+			}
+			visited[def] = true
+		}
+		f(md)
+		for _, op := range metadata.Operands(md) {
+			walk(op)
+		}
+	}
+	replaceMetadataRoots(m, func(md metadata.Metadata) metadata.Metadata {
+		walk(md)
+		return md
+	})
+}
+
+// ReachableMetadata returns the metadata definitions reachable from the given
+// module (see WalkMetadata), in order of traversal.
+func ReachableMetadata(m *ir.Module) []*metadata.MetadataDef {
+	var defs []*metadata.MetadataDef
+	WalkMetadata(m, func(md metadata.Metadata) {
+		if def, ok := md.(*metadata.MetadataDef); ok {
+			defs = append(defs, def)
+		}
+	})
+	return defs
+}
+
+// StripUnreachableMetadata removes the metadata definitions of the given module
+// which are not reachable (see WalkMetadata), and returns the number of
+// metadata definitions removed.
+func StripUnreachableMetadata(m *ir.Module) int {
+	reachable := make(map[*metadata.MetadataDef]bool)
+	for _, def := range ReachableMetadata(m) {
+		reachable[def] = true
+	}
+	var defs []*metadata.MetadataDef
+	for _, def := range m.MetadataDefs {
+		if reachable[def] {
+			defs = append(defs, def)
+		}
+	}
+	n := len(m.MetadataDefs) - len(defs)
+	m.MetadataDefs = defs
+	return n
+}
+
+// DedupMetadata merges identical non-distinct metadata definitions of the given
+// module, updating all references to use the first of the identical metadata
+// definitions, and returns the number of metadata definitions removed.
+//
+// Two metadata definitions are identical if their nodes have the same contents
+// after merging; the metadata definitions of the module are required to have
+// unique IDs.
+func DedupMetadata(m *ir.Module) int {
+	total := 0
+	for {
+		// Locate duplicates; merging a pair of metadata definitions may cause
+		// the metadata definitions referring to them to become identical, so
+		// repeat until no more duplicates are found.
+		canon := make(map[string]*metadata.MetadataDef)
+		dups := make(map[*metadata.MetadataDef]*metadata.MetadataDef)
+		for _, def := range m.MetadataDefs {
+			if def.Distinct {
+				continue
+			}
+			key := def.Node.String()
+			if c, ok := canon[key]; ok {
+				dups[def] = c
+				continue
+			}
+			canon[key] = def
+		}
+		if len(dups) == 0 {
+			return total
+		}
+		// Update references.
+		var replace func(md metadata.Metadata) metadata.Metadata
+		replace = func(md metadata.Metadata) metadata.Metadata {
+			if def, ok := md.(*metadata.MetadataDef); ok {
+				if c, ok := dups[def]; ok {
+					return c
+				}
+				return def
+			}
+			metadata.ReplaceOperands(md, replace)
+			return md
+		}
+		replaceMetadataRoots(m, replace)
+		for _, def := range m.MetadataDefs {
+			metadata.ReplaceOperands(def, replace)
+		}
+		// Remove duplicates.
+		var defs []*metadata.MetadataDef
+		for _, def := range m.MetadataDefs {
+			if _, ok := dups[def]; !ok {
+				defs = append(defs, def)
+			}
+		}
+		m.MetadataDefs = defs
+		total += len(dups)
+	}
+}
+
+// RenumberMetadata assigns consecutive IDs starting at 0 to the metadata
+// definitions of the given module, in order of traversal (see WalkMetadata),
+// followed by unreachable metadata definitions in their original order. The
+// metadata definitions of the module are sorted by ID.
+func RenumberMetadata(m *ir.Module) {
+	defs := ReachableMetadata(m)
+	reachable := make(map[*metadata.MetadataDef]bool)
+	for _, def := range defs {
+		reachable[def] = true
+	}
+	for _, def := range m.MetadataDefs {
+		if !reachable[def] {
+			defs = append(defs, def)
+		}
+	}
+	for i, def := range defs {
+		def.ID = int64(i)
+	}
+	m.MetadataDefs = defs
+}
+
+// ### [ Helper functions ] ####################################################
+
+// replaceMetadataRoots replaces each root metadata of the given module with the
+// result of invoking f on the metadata. Root metadata are the nodes of named
+// metadata definitions, the nodes of metadata attachments, and the metadata of
+// metadata arguments (see WalkMetadata).
+func replaceMetadataRoots(m *ir.Module, f func(md metadata.Metadata) metadata.Metadata) {
+	for _, md := range m.NamedMetadataDefs {
+		metadata.ReplaceOperands(md, f)
+	}
+	replaceAttachments := func(v interface{}) {
+		for _, md := range ir.MDAttachments(v) {
+			md.Node = f(md.Node)
+		}
+	}
+	replaceArgs := func(args []value.Value) {
+		for _, arg := range args {
+			if arg, ok := arg.(*metadata.Value); ok {
+				metadata.ReplaceOperands(arg, f)
+			}
+		}
+	}
+	for _, g := range m.Globals {
+		replaceAttachments(g)
+	}
+	for _, fn := range m.Funcs {
+		replaceAttachments(fn)
+		for _, block := range fn.Blocks {
+			// Note, metadata arguments are handled before metadata attachments,
+			// to match the metadata numbering order of LLVM.
+			for _, inst := range block.Insts {
+				switch inst := inst.(type) {
+				case *ir.InstCall:
+					replaceArgs(inst.Args)
+				case *ir.InstCatchPad:
+					replaceArgs(inst.Args)
+				case *ir.InstCleanupPad:
+					replaceArgs(inst.Args)
+				}
+				replaceAttachments(inst)
+			}
+			if term, ok := block.Term.(*ir.TermInvoke); ok {
+				replaceArgs(term.Args)
+			}
+			replaceAttachments(block.Term)
+		}
+	}
+}
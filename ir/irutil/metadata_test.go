@@ -0,0 +1,84 @@
+package irutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestMetadata(t *testing.T) {
+	const input = `
+define void @f() !dbg !3 {
+	call void @llvm.dbg.value(metadata i32 0, metadata !8, metadata !DIExpression()), !dbg !6
+	ret void, !dbg !7
+}
+
+declare void @llvm.dbg.value(metadata, metadata, metadata)
+
+!llvm.dbg.cu = !{!0}
+
+!0 = distinct !DICompileUnit(language: DW_LANG_C99, file: !1, emissionKind: FullDebug)
+!1 = !DIFile(filename: "f.c", directory: "/src")
+!2 = !DIFile(filename: "f.c", directory: "/src")
+!3 = distinct !DISubprogram(name: "f", scope: !2, file: !2, unit: !0)
+!4 = !DIFile(filename: "orphan.c", directory: "/src")
+!5 = !DIBasicType(name: "int", size: 32)
+!6 = !DILocation(line: 1, scope: !3)
+!7 = !DILocation(line: 1, scope: !3)
+!8 = !DILocalVariable(name: "x", scope: !3, file: !1, type: !9)
+!9 = !DIBasicType(name: "int", size: 32)
+`
+	m, err := asm.ParseString("metadata.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	// Test traversal.
+	var ids []int64
+	for _, def := range ReachableMetadata(m) {
+		ids = append(ids, def.ID)
+	}
+	wantIDs := []int64{0, 1, 3, 2, 8, 9, 6, 7}
+	if !equalIDs(ids, wantIDs) {
+		t.Errorf("reachable metadata mismatch; expected %v, got %v", wantIDs, ids)
+	}
+	// Test garbage collection.
+	if n := StripUnreachableMetadata(m); n != 2 {
+		t.Errorf("number of unreachable metadata definitions mismatch; expected 2, got %d", n)
+	}
+	// Test uniquing.
+	if n := DedupMetadata(m); n != 2 {
+		t.Errorf("number of duplicate metadata definitions mismatch; expected 2, got %d", n)
+	}
+	// Test renumbering.
+	RenumberMetadata(m)
+	const want = `!llvm.dbg.cu = !{!0}
+
+!0 = distinct !DICompileUnit(language: DW_LANG_C99, file: !1, emissionKind: FullDebug)
+!1 = !DIFile(filename: "f.c", directory: "/src")
+!2 = distinct !DISubprogram(name: "f", scope: !1, file: !1, unit: !0)
+!3 = !DILocalVariable(name: "x", scope: !2, file: !1, type: !4)
+!4 = !DIBasicType(name: "int", size: 32)
+!5 = !DILocation(line: 1, scope: !2)
+`
+	got := m.String()
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("module mismatch; expected suffix:\n%s\ngot:\n%s", want, got)
+	}
+	if !strings.Contains(got, "ret void, !dbg !5") || !strings.Contains(got, "metadata !3, metadata !DIExpression()), !dbg !5") {
+		t.Errorf("references not updated; got:\n%s", got)
+	}
+}
+
+// equalIDs reports whether the given metadata ID slices are equal.
+func equalIDs(a, b []int64) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,115 @@
+package irutil
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Operands ] ============================================================
+
+// Operands returns the operands of the given value, instruction, terminator or
+// constant expression, in order of occurrence. Basic block operands (e.g.
+// branch targets and incoming basic blocks of phi instructions) and metadata
+// are not included. Optional operands which are not present are omitted.
+func Operands(v interface{}) []value.Value {
+	var ops []value.Value
+	for _, op := range operands(v) {
+		if x := op.get(); x != nil {
+			ops = append(ops, x)
+		}
+	}
+	return ops
+}
+
+// operand is a mutable operand slot of a value, instruction, terminator or
+// constant expression.
+type operand struct {
+	// get returns the operand value, or nil if not present.
+	get func() value.Value
+	// set replaces the operand value.
+	set func(v value.Value)
+}
+
+// operands returns the operand slots of the given value, instruction,
+// terminator or constant expression, in order of occurrence (see Operands).
+func operands(v interface{}) []operand {
+	switch v := v.(type) {
+	// Instructions and terminators.
+	case value.User:
+		return useOps(v.Operands()...)
+	// Constant expressions.
+	case constant.Expression:
+		return constantOps(v.Operands()...)
+	// Constants.
+	case *constant.Array:
+		var ops []operand
+		for i := range v.Elems {
+			ops = append(ops, constantOps(&v.Elems[i])...)
+		}
+		return ops
+	case *constant.Struct:
+		var ops []operand
+		for i := range v.Fields {
+			ops = append(ops, constantOps(&v.Fields[i])...)
+		}
+		return ops
+	case *constant.Vector:
+		var ops []operand
+		for i := range v.Elems {
+			ops = append(ops, constantOps(&v.Elems[i])...)
+		}
+		return ops
+	case *constant.BlockAddress:
+		return constantOps(&v.Func)
+	// Values without operands.
+	case *ir.Global, *ir.Function, *ir.Alias, *ir.IFunc, *ir.Param, *ir.BasicBlock, *ir.InlineAsm:
+		return nil
+	case constant.Constant:
+		return nil
+	case *metadata.Value:
+		// Metadata operands are not included.
+		return nil
+	default:
+		panic(fmt.Errorf("support for value %T not yet implemented", v))
+	}
+}
+
+// ### [ Helper functions ] ####################################################
+
+// useOps returns the operand slots of the given uses.
+func useOps(uses ...*value.Use) []operand {
+	ops := make([]operand, len(uses))
+	for i, use := range uses {
+		ops[i] = operand{get: use.Get, set: use.Set}
+	}
+	return ops
+}
+
+// constantOps returns the operand slots of the given constant fields. Values
+// assigned to the slots must be constants.
+func constantOps(ps ...*constant.Constant) []operand {
+	ops := make([]operand, len(ps))
+	for i := range ps {
+		p := ps[i]
+		ops[i] = operand{
+			get: func() value.Value {
+				if *p == nil {
+					return nil
+				}
+				return *p
+			},
+			set: func(x value.Value) {
+				c, ok := x.(constant.Constant)
+				if !ok {
+					panic(fmt.Errorf("invalid constant operand; expected constant.Constant, got %T", x))
+				}
+				*p = c
+			},
+		}
+	}
+	return ops
+}
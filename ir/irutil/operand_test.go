@@ -0,0 +1,54 @@
+package irutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestOperands(t *testing.T) {
+	const input = `
+@g = global i32 0
+
+define i32 @f(i32 %x, i1 %c) {
+entry:
+	%y = add i32 %x, ptrtoint (i32* @g to i32)
+	%z = call i32 @f(i32 %y, i1 %c) [ "deopt"(i32 %x) ]
+	br i1 %c, label %a, label %b
+
+a:
+	br label %b
+
+b:
+	%p = phi i32 [ %y, %entry ], [ %z, %a ]
+	ret i32 %p
+}
+`
+	m, err := asm.ParseString("operand.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	f := m.Funcs[0]
+	golden := []struct {
+		v    interface{}
+		want string
+	}{
+		{v: f.Blocks[0].Insts[0], want: "%x ptrtoint (i32* @g to i32)"},
+		{v: f.Blocks[0].Insts[1], want: "@f %y %c %x"},
+		{v: f.Blocks[0].Term, want: "%c"},
+		{v: f.Blocks[1].Term, want: ""},
+		{v: f.Blocks[2].Insts[0], want: "%y %z"},
+		{v: f.Blocks[2].Term, want: "%p"},
+		{v: m.Globals[0].Init, want: ""},
+	}
+	for i, g := range golden {
+		var ops []string
+		for _, op := range Operands(g.v) {
+			ops = append(ops, op.Ident())
+		}
+		if got := strings.Join(ops, " "); got != g.want {
+			t.Errorf("%d: operands mismatch; expected %q, got %q", i, g.want, got)
+		}
+	}
+}
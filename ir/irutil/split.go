@@ -0,0 +1,200 @@
+package irutil
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Block splitting ] =====================================================
+
+// SplitBlock splits the given basic block before the instruction at the
+// specified index. The instructions starting at the index and the terminator
+// are moved to a new basic block, which is inserted after the basic block in
+// its parent function, and the basic block is terminated by an unconditional
+// branch to the new basic block. Phi instructions of successor basic blocks are
+// updated to refer to the new basic block, which is returned.
+//
+// SplitBlock panics if the basic block has no parent function, or if the index
+// is out of range or refers to a phi instruction.
+func SplitBlock(block *ir.BasicBlock, at int) *ir.BasicBlock {
+	f := block.Parent
+	if f == nil {
+		panic(fmt.Errorf("unable to split basic block %s; missing parent function", block.Ident()))
+	}
+	if at < 0 || at > len(block.Insts) {
+		panic(fmt.Errorf("unable to split basic block %s at index %d; index out of range [0, %d]", block.Ident(), at, len(block.Insts)))
+	}
+	if at < len(block.Insts) {
+		if _, ok := block.Insts[at].(*ir.InstPhi); ok {
+			panic(fmt.Errorf("unable to split basic block %s at index %d; phi instruction", block.Ident(), at))
+		}
+	}
+	base := "split"
+	if len(block.Name()) > 0 {
+		base = block.Name() + ".split"
+	}
+	tail := f.NewBlockAfter(block, uniqueName(f, base))
+	tail.Insts = append([]ir.Instruction(nil), block.Insts[at:]...)
+	tail.Term = block.Term
+	block.Insts = block.Insts[:at]
+	block.Term = nil
+	block.NewBr(tail)
+	if tail.Term != nil {
+		for _, succ := range tail.Term.Succs() {
+			replacePhiPred(succ, block, tail)
+		}
+	}
+	return tail
+}
+
+// SplitEdge splits the edges from the given predecessor to the successor basic
+// block, by inserting a new basic block after the predecessor in its parent
+// function. The new basic block, which branches unconditionally to the
+// successor, is returned. The terminator of the predecessor and phi
+// instructions of the successor are updated to refer to the new basic block.
+//
+// Edges of conditional br and switch terminators, and normal edges of invoke
+// terminators may be split.
+func SplitEdge(pred, succ *ir.BasicBlock) (*ir.BasicBlock, error) {
+	f := pred.Parent
+	if f == nil {
+		return nil, errors.Errorf("unable to split edge from %s to %s; missing parent function", pred.Ident(), succ.Ident())
+	}
+	switch term := pred.Term.(type) {
+	case *ir.TermCondBr, *ir.TermSwitch:
+		// supported.
+	case *ir.TermInvoke:
+		if term.Exception == succ {
+			return nil, errors.Errorf("unable to split edge from %s to exception handler %s", pred.Ident(), succ.Ident())
+		}
+	default:
+		return nil, errors.Errorf("unable to split edge from %s to %s; support for terminator %T not yet implemented", pred.Ident(), succ.Ident(), term)
+	}
+	base := "crit_edge"
+	if len(pred.Name()) > 0 && len(succ.Name()) > 0 {
+		base = pred.Name() + "." + succ.Name() + "_crit_edge"
+	}
+	edge := f.NewBlockAfter(pred, uniqueName(f, base))
+	edge.NewBr(succ)
+	switch term := pred.Term.(type) {
+	case *ir.TermCondBr:
+		if term.TargetTrue == succ {
+			term.TargetTrue = edge
+		}
+		if term.TargetFalse == succ {
+			term.TargetFalse = edge
+		}
+		term.Successors = nil
+	case *ir.TermSwitch:
+		if term.TargetDefault == succ {
+			term.TargetDefault = edge
+		}
+		for _, c := range term.Cases {
+			if c.Target == succ {
+				c.Target = edge
+			}
+		}
+		term.Successors = nil
+	case *ir.TermInvoke:
+		term.Normal = edge
+		term.Successors = nil
+	}
+	replacePhiPred(succ, pred, edge)
+	return edge, nil
+}
+
+// SplitCriticalEdges splits the critical edges of the given function, and
+// returns the number of edges split. An edge is critical if its source has
+// multiple successors and its target has multiple predecessors. Critical edges
+// which may not be split (e.g. of indirectbr terminators or to exception
+// handlers) are left unchanged.
+func SplitCriticalEdges(f *ir.Function) int {
+	npreds := make(map[*ir.BasicBlock]int)
+	for _, block := range f.Blocks {
+		for _, succ := range uniqueSuccs(block) {
+			npreds[succ]++
+		}
+	}
+	n := 0
+	for _, block := range append([]*ir.BasicBlock(nil), f.Blocks...) {
+		succs := uniqueSuccs(block)
+		if len(succs) < 2 {
+			continue
+		}
+		// Split edges in reverse order, as new basic blocks are inserted
+		// immediately after the predecessor.
+		for i := len(succs) - 1; i >= 0; i-- {
+			if npreds[succs[i]] < 2 {
+				continue
+			}
+			if _, err := SplitEdge(block, succs[i]); err == nil {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// ### [ Helper functions ] ####################################################
+
+// uniqueSuccs returns the successors of the given basic block, with each
+// successor included once.
+func uniqueSuccs(block *ir.BasicBlock) []*ir.BasicBlock {
+	if block.Term == nil {
+		return nil
+	}
+	var succs []*ir.BasicBlock
+	seen := make(map[*ir.BasicBlock]bool)
+	for _, succ := range block.Term.Succs() {
+		if !seen[succ] {
+			seen[succ] = true
+			succs = append(succs, succ)
+		}
+	}
+	return succs
+}
+
+// replacePhiPred replaces the incoming basic block old with new in the phi
+// instructions of the given basic block.
+func replacePhiPred(block, old, new *ir.BasicBlock) {
+	for _, inst := range block.Insts {
+		phi, ok := inst.(*ir.InstPhi)
+		if !ok {
+			break
+		}
+		for _, inc := range phi.Incs {
+			if inc.Pred == old {
+				inc.Pred = new
+			}
+		}
+	}
+}
+
+// uniqueName returns a local name of the given function based on the specified
+// base name, which is not yet in use.
+func uniqueName(f *ir.Function, base string) string {
+	names := make(map[string]bool)
+	for _, param := range f.Params {
+		names[param.Name()] = true
+	}
+	for _, block := range f.Blocks {
+		names[block.Name()] = true
+		for _, inst := range block.Insts {
+			if v, ok := inst.(value.Named); ok {
+				names[v.Name()] = true
+			}
+		}
+		if v, ok := block.Term.(value.Named); ok {
+			names[v.Name()] = true
+		}
+	}
+	name := base
+	for i := 1; names[name]; i++ {
+		name = base + strconv.Itoa(i)
+	}
+	return name
+}
@@ -0,0 +1,120 @@
+package irutil
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestSplitBlock(t *testing.T) {
+	const input = `
+define i32 @f(i32 %x, i1 %c) {
+entry:
+	%y = add i32 %x, 1
+	%z = mul i32 %y, 2
+	br i1 %c, label %exit, label %other
+
+other:
+	br label %exit
+
+exit:
+	%p = phi i32 [ %z, %entry ], [ 0, %other ]
+	ret i32 %p
+}
+`
+	m, err := asm.ParseString("split.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	f := m.Funcs[0]
+	tail := SplitBlock(f.Blocks[0], 1)
+	const want = `
+define i32 @f(i32 %x, i1 %c) {
+entry:
+	%y = add i32 %x, 1
+	br label %entry.split
+
+entry.split:
+	%z = mul i32 %y, 2
+	br i1 %c, label %exit, label %other
+
+other:
+	br label %exit
+
+exit:
+	%p = phi i32 [ %z, %entry.split ], [ 0, %other ]
+	ret i32 %p
+}`
+	if got := f.Def(); got != strings.TrimSpace(want) {
+		t.Errorf("function mismatch; expected %q, got %q", strings.TrimSpace(want), got)
+	}
+	if tail != f.Blocks[1] || tail.Parent != f {
+		t.Errorf("new basic block mismatch; expected %q with parent %q", f.Blocks[1].Ident(), f.Ident())
+	}
+}
+
+func TestSplitCriticalEdges(t *testing.T) {
+	const input = `
+define i32 @f(i32 %x, i1 %c) {
+entry:
+	switch i32 %x, label %exit [
+		i32 0, label %a
+		i32 1, label %exit
+		i32 2, label %b
+	]
+
+a:
+	br i1 %c, label %b, label %exit
+
+b:
+	br label %exit
+
+exit:
+	%p = phi i32 [ 0, %entry ], [ 1, %a ], [ 2, %b ]
+	ret i32 %p
+}
+`
+	m, err := asm.ParseString("split.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	f := m.Funcs[0]
+	if n := SplitCriticalEdges(f); n != 4 {
+		t.Errorf("number of split edges mismatch; expected 4, got %d", n)
+	}
+	const want = `
+define i32 @f(i32 %x, i1 %c) {
+entry:
+	switch i32 %x, label %entry.exit_crit_edge [
+		i32 0, label %a
+		i32 1, label %entry.exit_crit_edge
+		i32 2, label %entry.b_crit_edge
+	]
+
+entry.exit_crit_edge:
+	br label %exit
+
+entry.b_crit_edge:
+	br label %b
+
+a:
+	br i1 %c, label %a.b_crit_edge, label %a.exit_crit_edge
+
+a.b_crit_edge:
+	br label %b
+
+a.exit_crit_edge:
+	br label %exit
+
+b:
+	br label %exit
+
+exit:
+	%p = phi i32 [ 0, %entry.exit_crit_edge ], [ 1, %a.exit_crit_edge ], [ 2, %b ]
+	ret i32 %p
+}`
+	if got := f.Def(); got != strings.TrimSpace(want) {
+		t.Errorf("function mismatch; expected %q, got %q", strings.TrimSpace(want), got)
+	}
+}
@@ -0,0 +1,277 @@
+package irutil
+
+import (
+	"fmt"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Walk ] ================================================================
+
+// Inspect traverses the given node in depth-first order, invoking f for each
+// node before its children (see Walk). If f returns false, the children of the
+// node are skipped.
+//
+// Operands referring to named values (e.g. instructions, function parameters
+// and global variables) are visited without their children; use Walk to
+// distinguish operands from definitions.
+func Inspect(root interface{}, f func(n interface{}) bool) {
+	Walk(root, func(c *Cursor) bool {
+		return f(c.Node())
+	}, nil)
+}
+
+// Walk traverses the given node in depth-first order, visiting the node and
+// its children. The node is one of the following:
+//
+//    *ir.Module                   // type definitions, globals, functions, aliases and IFuncs
+//    *ir.Global                   // content type, initializer and metadata attachments
+//    *ir.Function                 // signature, parameters, prefix, prologue, personality, metadata attachments and basic blocks
+//    *ir.Alias                    // aliasee
+//    *ir.IFunc                    // resolver
+//    *ir.Param                    // type
+//    *ir.BasicBlock               // instructions and terminator
+//    ir.Instruction               // type, operands and metadata attachments
+//    ir.Terminator                // type, operands and metadata attachments
+//    value.Value                  // operands of constant aggregates and constant expressions
+//    types.Type                   // element, field, parameter and return types
+//    *metadata.MetadataAttachment // no children; see WalkMetadata
+//
+// Operands, including the operands of nested constant expressions, are visited
+// as value.Value with an operand cursor (see Cursor.IsOperand); operands
+// referring to named values (e.g. instructions, function parameters and global
+// variables) have no children. The types of instructions and terminators are
+// visited if non-void, and types are visited once per path from the root type
+// (i.e. recursive types are not revisited).
+//
+// pre is invoked for each node before its children are traversed, and post
+// after. If pre returns false, the children of the node and the post
+// invocation are skipped. If post returns false, the traversal is terminated.
+// Either function may be nil.
+func Walk(root interface{}, pre, post func(c *Cursor) bool) {
+	w := &walker{pre: pre, post: post, types: make(map[types.Type]bool)}
+	w.walk(&Cursor{node: root})
+}
+
+// Rewrite traverses the given node (see Walk), and replaces each operand,
+// including the operands of nested constant expressions, with the value
+// returned by f. Replacements of constant operands (e.g. of global variable
+// initializers and constant expressions) must be constants. The operands of
+// replacement values are traversed.
+func Rewrite(root interface{}, f func(v value.Value) value.Value) {
+	Walk(root, func(c *Cursor) bool {
+		if c.IsOperand() {
+			if v := f(c.Node().(value.Value)); v != c.Node() {
+				c.Replace(v)
+			}
+		}
+		return true
+	}, nil)
+}
+
+// Cursor describes a node encountered during Walk.
+type Cursor struct {
+	// Current node.
+	node interface{}
+	// Parent node; or nil if root.
+	parent interface{}
+	// Operand slot of the current node; or nil if not an operand.
+	op *operand
+}
+
+// Node returns the current node.
+func (c *Cursor) Node() interface{} {
+	return c.node
+}
+
+// Parent returns the parent of the current node, or nil if the current node
+// is the root.
+func (c *Cursor) Parent() interface{} {
+	return c.parent
+}
+
+// IsOperand reports whether the current node is an operand of its parent.
+func (c *Cursor) IsOperand() bool {
+	return c.op != nil
+}
+
+// Replace replaces the current operand in place with the given value. The
+// children of the replacement are traversed. Replace panics if the current
+// node is not an operand, or if a constant operand is replaced by a
+// non-constant value.
+func (c *Cursor) Replace(v value.Value) {
+	if c.op == nil {
+		panic(fmt.Errorf("unable to replace node %T; not an operand", c.node))
+	}
+	c.op.set(v)
+	c.node = v
+}
+
+// walker is a depth-first traversal of LLVM IR nodes.
+type walker struct {
+	// Functions invoked before and after the children of each node.
+	pre, post func(c *Cursor) bool
+	// Types on the current type path; to prevent infinite recursion of
+	// recursive types.
+	types map[types.Type]bool
+	// Traversal terminated.
+	stop bool
+}
+
+// walk traverses the node of the given cursor and its children. It reports
+// whether the traversal should continue.
+func (w *walker) walk(c *Cursor) bool {
+	if w.stop {
+		return false
+	}
+	if w.pre != nil && !w.pre(c) {
+		return true
+	}
+	w.walkChildren(c)
+	if w.stop {
+		return false
+	}
+	if w.post != nil && !w.post(c) {
+		w.stop = true
+		return false
+	}
+	return true
+}
+
+// walkChildren traverses the children of the node of the given cursor.
+func (w *walker) walkChildren(c *Cursor) {
+	if c.op != nil {
+		// Operands referring to named values have no children.
+		if _, ok := c.node.(value.Named); ok {
+			return
+		}
+		if _, ok := c.node.(constant.Constant); ok {
+			w.walkOperands(c.node)
+		}
+		return
+	}
+	switch n := c.node.(type) {
+	case *ir.Module:
+		for _, t := range n.TypeDefs {
+			w.walkNode(n, t)
+		}
+		for _, g := range n.Globals {
+			w.walkNode(n, g)
+		}
+		for _, f := range n.Funcs {
+			w.walkNode(n, f)
+		}
+		for _, a := range n.Aliases {
+			w.walkNode(n, a)
+		}
+		for _, i := range n.IFuncs {
+			w.walkNode(n, i)
+		}
+	case *ir.Global:
+		w.walkNode(n, n.ContentType)
+		w.walkOperands(n)
+		w.walkAttachments(n)
+	case *ir.Function:
+		w.walkNode(n, n.Sig)
+		for _, param := range n.Params {
+			w.walkNode(n, param)
+		}
+		w.walkOperands(n)
+		w.walkAttachments(n)
+		for _, block := range n.Blocks {
+			w.walkNode(n, block)
+		}
+	case *ir.Alias, *ir.IFunc:
+		w.walkOperands(n)
+	case *ir.Param:
+		w.walkNode(n, n.Typ)
+	case *ir.BasicBlock:
+		for _, inst := range n.Insts {
+			w.walkNode(n, inst)
+		}
+		if n.Term != nil {
+			w.walkNode(n, n.Term)
+		}
+	case ir.Instruction, ir.Terminator:
+		if v, ok := n.(value.Value); ok {
+			if t := v.Type(); !t.Equal(types.Void) {
+				w.walkNode(n, t)
+			}
+		}
+		w.walkOperands(n)
+		w.walkAttachments(n)
+	case types.Type:
+		if w.types[n] {
+			return
+		}
+		w.types[n] = true
+		defer delete(w.types, n)
+		switch t := n.(type) {
+		case *types.FuncType:
+			w.walkNode(t, t.RetType)
+			for _, param := range t.Params {
+				w.walkNode(t, param)
+			}
+		case *types.PointerType:
+			w.walkNode(t, t.ElemType)
+		case *types.VectorType:
+			w.walkNode(t, t.ElemType)
+		case *types.ArrayType:
+			w.walkNode(t, t.ElemType)
+		case *types.StructType:
+			for _, field := range t.Fields {
+				w.walkNode(t, field)
+			}
+		}
+	case *metadata.MetadataAttachment:
+		// Metadata is traversed by WalkMetadata.
+	case value.Value:
+		w.walkOperands(n)
+	default:
+		panic(fmt.Errorf("support for node %T not yet implemented", n))
+	}
+}
+
+// walkNode traverses the given child node of the parent node.
+func (w *walker) walkNode(parent, node interface{}) {
+	w.walk(&Cursor{node: node, parent: parent})
+}
+
+// walkOperands traverses the operands of the given node. Operands which are not
+// present are skipped.
+func (w *walker) walkOperands(n interface{}) {
+	var ops []operand
+	switch n := n.(type) {
+	case *ir.Global:
+		ops = constantOps(&n.Init)
+	case *ir.Function:
+		ops = constantOps(&n.Prefix, &n.Prologue, &n.Personality)
+	case *ir.Alias:
+		ops = constantOps(&n.Aliasee)
+	case *ir.IFunc:
+		ops = constantOps(&n.Resolver)
+	default:
+		ops = operands(n)
+	}
+	for i := range ops {
+		op := &ops[i]
+		v := op.get()
+		if v == nil {
+			continue
+		}
+		if !w.walk(&Cursor{node: v, parent: n, op: op}) {
+			return
+		}
+	}
+}
+
+// walkAttachments traverses the metadata attachments of the given node.
+func (w *walker) walkAttachments(n interface{}) {
+	for _, md := range ir.MDAttachments(n) {
+		w.walkNode(n, md)
+	}
+}
@@ -0,0 +1,153 @@
+package irutil
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestWalk(t *testing.T) {
+	const input = `
+@g = global i32 0
+@p = global i64 ptrtoint (i32* @g to i64)
+
+define i32 @f(i32 %x) {
+entry:
+	%y = add i32 %x, ptrtoint (i32* @g to i32), !foo !0
+	ret i32 %y
+}
+
+!0 = !{}
+`
+	m, err := asm.ParseString("walk.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+
+	// Record the nodes visited by Walk.
+	var nodes []string
+	Walk(m, func(c *Cursor) bool {
+		if c.IsOperand() {
+			nodes = append(nodes, "operand "+c.Node().(value.Value).Ident())
+			return true
+		}
+		var s string
+		switch n := c.Node().(type) {
+		case *ir.Module:
+			s = "module"
+		case *ir.Global:
+			s = "global " + n.Ident()
+		case *ir.Function:
+			s = "func " + n.Ident()
+		case *ir.Param:
+			s = "param " + n.Ident()
+		case *ir.BasicBlock:
+			s = "block " + n.Ident()
+		case ir.Instruction:
+			s = "inst " + n.(value.Named).Ident()
+		case ir.Terminator:
+			s = "term"
+		case types.Type:
+			// Skip types of parameters and instructions.
+			return false
+		default:
+			s = fmt.Sprintf("%T", n)
+		}
+		nodes = append(nodes, s)
+		// Prune global variables.
+		_, ok := c.Node().(*ir.Global)
+		return !ok
+	}, nil)
+	want := []string{
+		"module",
+		"global @g",
+		"global @p",
+		"func @f",
+		"param %x",
+		"block %entry",
+		"inst %y",
+		"operand %x",
+		"operand ptrtoint (i32* @g to i32)",
+		"operand @g",
+		"*metadata.MetadataAttachment",
+		"term",
+		"operand %y",
+	}
+	if got := strings.Join(nodes, "\n"); got != strings.Join(want, "\n") {
+		t.Errorf("visited nodes mismatch; expected %q, got %q", want, nodes)
+	}
+
+	// Count the type nodes of the global variable @p using Inspect.
+	ntypes := 0
+	Inspect(m.Globals[1], func(n interface{}) bool {
+		if _, ok := n.(types.Type); ok {
+			ntypes++
+		}
+		return true
+	})
+	if ntypes != 1 {
+		t.Errorf("number of types mismatch; expected 1, got %d", ntypes)
+	}
+
+	// Terminate the traversal after the first function parameter.
+	Walk(m, func(c *Cursor) bool {
+		if _, ok := c.Node().(*ir.BasicBlock); ok {
+			t.Errorf("basic block %s visited after termination", c.Node().(*ir.BasicBlock).Ident())
+		}
+		return true
+	}, func(c *Cursor) bool {
+		_, ok := c.Node().(*ir.Param)
+		return !ok
+	})
+}
+
+func TestRewrite(t *testing.T) {
+	const input = `
+@g = global i32 0
+@h = global i32 1
+@p = global i64 ptrtoint (i32* @g to i64)
+
+define i32* @f(i32 %x) {
+entry:
+	%y = add i32 %x, ptrtoint (i32* @g to i32)
+	%z = call i32* @f(i32 %y)
+	ret i32* @g
+}
+`
+	m, err := asm.ParseString("rewrite.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	g, h := m.Globals[0], m.Globals[1]
+	f := m.Funcs[0]
+	Rewrite(m, func(v value.Value) value.Value {
+		switch v {
+		case g:
+			return h
+		case f.Params[0]:
+			return constant.NewInt(types.I32, 42)
+		}
+		return v
+	})
+	const want = `
+@g = global i32 0
+@h = global i32 1
+@p = global i64 ptrtoint (i32* @h to i64)
+
+define i32* @f(i32 %x) {
+entry:
+	%y = add i32 42, ptrtoint (i32* @h to i32)
+	%z = call i32* @f(i32 %y)
+	ret i32* @h
+}
+`
+	if got := m.String(); got != strings.TrimLeft(want, "\n") {
+		t.Errorf("module mismatch; expected %q, got %q", strings.TrimLeft(want, "\n"), got)
+	}
+}
@@ -0,0 +1,245 @@
+package metadata
+
+// === [ Metadata operands ] ===================================================
+
+// Operands returns the non-nil metadata operands of the given metadata; i.e.
+// the node of a metadata definition, the nodes of a named metadata definition,
+// the fields of a metadata tuple, the value of a metadata value, and the
+// metadata fields of specialized metadata nodes.
+func Operands(md Metadata) []Metadata {
+	var ops []Metadata
+	ReplaceOperands(md, func(op Metadata) Metadata {
+		ops = append(ops, op)
+		return op
+	})
+	return ops
+}
+
+// ReplaceOperands replaces each non-nil metadata operand of the given metadata
+// (see Operands) with the result of invoking f on the operand.
+func ReplaceOperands(md Metadata, f func(op Metadata) Metadata) {
+	switch md := md.(type) {
+	case *NamedMetadataDef:
+		for i, node := range md.Nodes {
+			md.Nodes[i] = f(node)
+		}
+	case *MetadataDef:
+		if md.Node != nil {
+			md.Node = f(md.Node)
+		}
+	case *MDTuple:
+		for i, field := range md.Fields {
+			if field != nil {
+				md.Fields[i] = f(field)
+			}
+		}
+	case *Value:
+		if md.Value != nil {
+			md.Value = f(md.Value)
+		}
+	// Specialized metadata nodes.
+	case *DICompileUnit:
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+		if md.Enums != nil {
+			md.Enums = f(md.Enums)
+		}
+		if md.RetainedTypes != nil {
+			md.RetainedTypes = f(md.RetainedTypes)
+		}
+		if md.Globals != nil {
+			md.Globals = f(md.Globals)
+		}
+		if md.Imports != nil {
+			md.Imports = f(md.Imports)
+		}
+		if md.Macros != nil {
+			md.Macros = f(md.Macros)
+		}
+	case *DICompositeType:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+		if md.BaseType != nil {
+			md.BaseType = f(md.BaseType)
+		}
+		if md.Elements != nil {
+			md.Elements = f(md.Elements)
+		}
+		if md.VtableHolder != nil {
+			md.VtableHolder = f(md.VtableHolder)
+		}
+		if md.TemplateParams != nil {
+			md.TemplateParams = f(md.TemplateParams)
+		}
+		if md.Discriminator != nil {
+			md.Discriminator = f(md.Discriminator)
+		}
+	case *DIDerivedType:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+		if md.BaseType != nil {
+			md.BaseType = f(md.BaseType)
+		}
+		if md.ExtraData != nil {
+			md.ExtraData = f(md.ExtraData)
+		}
+	case *DIGlobalVariable:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+		if md.Type != nil {
+			md.Type = f(md.Type)
+		}
+		if md.TemplateParams != nil {
+			md.TemplateParams = f(md.TemplateParams)
+		}
+		if md.Declaration != nil {
+			md.Declaration = f(md.Declaration)
+		}
+	case *DIGlobalVariableExpression:
+		if md.Var != nil {
+			md.Var = f(md.Var)
+		}
+		if md.Expr != nil {
+			md.Expr = f(md.Expr)
+		}
+	case *DIImportedEntity:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.Entity != nil {
+			md.Entity = f(md.Entity)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+	case *DILabel:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+	case *DILexicalBlock:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+	case *DILexicalBlockFile:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+	case *DILocalVariable:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+		if md.Type != nil {
+			md.Type = f(md.Type)
+		}
+	case *DILocation:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.InlinedAt != nil {
+			md.InlinedAt = f(md.InlinedAt)
+		}
+	case *DIMacroFile:
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+		if md.Nodes != nil {
+			md.Nodes = f(md.Nodes)
+		}
+	case *DIModule:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+	case *DINamespace:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+	case *DIObjCProperty:
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+		if md.Type != nil {
+			md.Type = f(md.Type)
+		}
+	case *DISubprogram:
+		if md.Scope != nil {
+			md.Scope = f(md.Scope)
+		}
+		if md.File != nil {
+			md.File = f(md.File)
+		}
+		if md.Type != nil {
+			md.Type = f(md.Type)
+		}
+		if md.ContainingType != nil {
+			md.ContainingType = f(md.ContainingType)
+		}
+		if md.Unit != nil {
+			md.Unit = f(md.Unit)
+		}
+		if md.TemplateParams != nil {
+			md.TemplateParams = f(md.TemplateParams)
+		}
+		if md.Declaration != nil {
+			md.Declaration = f(md.Declaration)
+		}
+		if md.RetainedNodes != nil {
+			md.RetainedNodes = f(md.RetainedNodes)
+		}
+		if md.ThrownTypes != nil {
+			md.ThrownTypes = f(md.ThrownTypes)
+		}
+	case *DISubrange:
+		if md.Count != nil {
+			md.Count = f(md.Count)
+		}
+	case *DISubroutineType:
+		if md.Types != nil {
+			md.Types = f(md.Types)
+		}
+	case *DITemplateTypeParameter:
+		if md.Type != nil {
+			md.Type = f(md.Type)
+		}
+	case *DITemplateValueParameter:
+		if md.Type != nil {
+			md.Type = f(md.Type)
+		}
+		if md.Value != nil {
+			md.Value = f(md.Value)
+		}
+	case *GenericDINode:
+		for i, op := range md.Operands {
+			if op != nil {
+				md.Operands[i] = f(op)
+			}
+		}
+	default:
+		// Metadata without metadata operands; e.g. DIFile, MDString or metadata
+		// values of non-metadata type (e.g. `i32 42`).
+	}
+}
@@ -50,6 +50,8 @@ type Module struct {
 	UseListOrders []*UseListOrder
 	// (optional) Basic block specific use-list order directives.
 	UseListOrderBBs []*UseListOrderBB
+	// (optional) Comments of top-level entities, basic blocks and instructions.
+	Comments CommentMap
 }
 
 // NewModule returns a new LLVM IR module.
@@ -61,28 +63,34 @@ func NewModule() *Module {
 // syntax.
 func (m *Module) String() string {
 	buf := &strings.Builder{}
+	// Module header comments.
+	m.Comments.writeLeading(buf, m, "")
 	// Source filename.
 	if len(m.SourceFilename) > 0 {
 		// 'source_filename' '=' Name=StringLit
-		fmt.Fprintf(buf, "source_filename = %s\n", quote(m.SourceFilename))
+		m.Comments.writeLeading(buf, &m.SourceFilename, "")
+		fmt.Fprintf(buf, "source_filename = %s%s\n", quote(m.SourceFilename), m.Comments.trailing(&m.SourceFilename))
 	}
 	// Data layout.
 	if len(m.DataLayout) > 0 {
 		// 'target' 'datalayout' '=' DataLayout=StringLit
-		fmt.Fprintf(buf, "target datalayout = %s\n", quote(m.DataLayout))
+		m.Comments.writeLeading(buf, &m.DataLayout, "")
+		fmt.Fprintf(buf, "target datalayout = %s%s\n", quote(m.DataLayout), m.Comments.trailing(&m.DataLayout))
 	}
 	// Target triple.
 	if len(m.TargetTriple) > 0 {
 		// 'target' 'triple' '=' TargetTriple=StringLit
-		fmt.Fprintf(buf, "target triple = %s\n", quote(m.TargetTriple))
+		m.Comments.writeLeading(buf, &m.TargetTriple, "")
+		fmt.Fprintf(buf, "target triple = %s%s\n", quote(m.TargetTriple), m.Comments.trailing(&m.TargetTriple))
 	}
 	// Module-level inline assembly.
 	if len(m.ModuleAsms) > 0 && buf.Len() > 0 {
 		buf.WriteString("\n")
 	}
-	for _, asm := range m.ModuleAsms {
+	for i, asm := range m.ModuleAsms {
 		// 'module' 'asm' Asm=StringLit
-		fmt.Fprintf(buf, "module asm %s\n", quote(asm))
+		m.Comments.writeLeading(buf, &m.ModuleAsms[i], "")
+		fmt.Fprintf(buf, "module asm %s%s\n", quote(asm), m.Comments.trailing(&m.ModuleAsms[i]))
 	}
 	// Type definitions.
 	if len(m.TypeDefs) > 0 && buf.Len() > 0 {
@@ -92,35 +100,40 @@ func (m *Module) String() string {
 		// Alias=LocalIdent '=' 'type' Typ=OpaqueType
 		//
 		// Alias=LocalIdent '=' 'type' Typ=Type
-		fmt.Fprintf(buf, "%s = type %s\n", t, t.Def())
+		m.Comments.writeLeading(buf, t, "")
+		fmt.Fprintf(buf, "%s = type %s%s\n", t, t.Def(), m.Comments.trailing(t))
 	}
 	// Comdat definitions.
 	if len(m.ComdatDefs) > 0 && buf.Len() > 0 {
 		buf.WriteString("\n")
 	}
 	for _, def := range m.ComdatDefs {
-		fmt.Fprintln(buf, def.Def())
+		m.Comments.writeLeading(buf, def, "")
+		fmt.Fprintf(buf, "%s%s\n", def.Def(), m.Comments.trailing(def))
 	}
 	// Global declarations and definitions.
 	if len(m.Globals) > 0 && buf.Len() > 0 {
 		buf.WriteString("\n")
 	}
 	for _, g := range m.Globals {
-		fmt.Fprintln(buf, g.Def())
+		m.Comments.writeLeading(buf, g, "")
+		fmt.Fprintf(buf, "%s%s\n", g.Def(), m.Comments.trailing(g))
 	}
 	// Aliases.
 	if len(m.Aliases) > 0 && buf.Len() > 0 {
 		buf.WriteString("\n")
 	}
 	for _, alias := range m.Aliases {
-		fmt.Fprintln(buf, alias.Def())
+		m.Comments.writeLeading(buf, alias, "")
+		fmt.Fprintf(buf, "%s%s\n", alias.Def(), m.Comments.trailing(alias))
 	}
 	// IFuncs.
 	if len(m.IFuncs) > 0 && buf.Len() > 0 {
 		buf.WriteString("\n")
 	}
 	for _, ifunc := range m.IFuncs {
-		fmt.Fprintln(buf, ifunc.Def())
+		m.Comments.writeLeading(buf, ifunc, "")
+		fmt.Fprintf(buf, "%s%s\n", ifunc.Def(), m.Comments.trailing(ifunc))
 	}
 	// Function declarations and definitions.
 	if len(m.Funcs) > 0 && buf.Len() > 0 {
@@ -130,28 +143,32 @@ func (m *Module) String() string {
 		if i != 0 {
 			buf.WriteString("\n")
 		}
-		fmt.Fprintln(buf, f.Def())
+		m.Comments.writeLeading(buf, f, "")
+		fmt.Fprintln(buf, f.def(m.Comments))
 	}
 	// Attribute group definitions.
 	if len(m.AttrGroupDefs) > 0 && buf.Len() > 0 {
 		buf.WriteString("\n")
 	}
 	for _, a := range m.AttrGroupDefs {
-		fmt.Fprintln(buf, a.Def())
+		m.Comments.writeLeading(buf, a, "")
+		fmt.Fprintf(buf, "%s%s\n", a.Def(), m.Comments.trailing(a))
 	}
 	// Named metadata definitions.
 	if len(m.NamedMetadataDefs) > 0 && buf.Len() > 0 {
 		buf.WriteString("\n")
 	}
 	for _, md := range m.NamedMetadataDefs {
-		fmt.Fprintln(buf, md.Def())
+		m.Comments.writeLeading(buf, md, "")
+		fmt.Fprintf(buf, "%s%s\n", md.Def(), m.Comments.trailing(md))
 	}
 	// Metadata definitions.
 	if len(m.MetadataDefs) > 0 && buf.Len() > 0 {
 		buf.WriteString("\n")
 	}
 	for _, md := range m.MetadataDefs {
-		fmt.Fprintln(buf, md.Def())
+		m.Comments.writeLeading(buf, md, "")
+		fmt.Fprintf(buf, "%s%s\n", md.Def(), m.Comments.trailing(md))
 	}
 	// Use-list orders.
 	if len(m.UseListOrders) > 0 && buf.Len() > 0 {
@@ -167,6 +184,11 @@ func (m *Module) String() string {
 	for _, u := range m.UseListOrderBBs {
 		fmt.Fprintln(buf, u)
 	}
+	// Comments at the end of the module.
+	if c, ok := m.Comments[m]; ok && len(c.Final) > 0 && buf.Len() > 0 {
+		buf.WriteString("\n")
+	}
+	m.Comments.writeFinal(buf, m, "")
 	return buf.String()
 }
 
@@ -0,0 +1,90 @@
+package ir
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+func TestOperands(t *testing.T) {
+	x := NewParam("x", types.I32)
+	y := NewParam("y", types.I32)
+	f := NewFunc("f", types.I32, x)
+	zero := constant.NewInt(types.I32, 0)
+	one := constant.NewInt(types.I32, 1)
+	entry := NewBlock("entry")
+	exit := NewBlock("exit")
+
+	// Replace each use of %x by %y.
+	replace := func(u value.User) {
+		for _, op := range u.Operands() {
+			if op.Get() == x {
+				op.Set(y)
+			}
+		}
+	}
+	call := NewCall(f, x)
+	call.OperandBundles = []*OperandBundle{NewOperandBundle("deopt", x)}
+	phi := NewPhi(NewIncoming(x, entry), NewIncoming(zero, exit))
+	sw := NewSwitch(x, exit, NewCase(one, entry))
+	ret := NewRet(nil)
+	golden := []struct {
+		u    value.User
+		want string
+	}{
+		{u: NewAdd(x, x), want: "%y %y"},
+		{u: call, want: "@f %y %y"},
+		{u: phi, want: "%y 0"},
+		{u: NewAlloca(types.I32), want: ""},
+		{u: sw, want: "%y 1"},
+		{u: ret, want: ""},
+		{u: NewBr(exit), want: ""},
+	}
+	for i, g := range golden {
+		replace(g.u)
+		var got string
+		for j, op := range g.u.Operands() {
+			if j != 0 {
+				got += " "
+			}
+			got += op.Get().Ident()
+		}
+		if got != g.want {
+			t.Errorf("i=%d: operands mismatch; expected %q, got %q", i, g.want, got)
+		}
+	}
+
+	// Typed operand slots.
+	pad := NewCatchPad(NewCatchSwitch(&constant.NoneToken{}, []*BasicBlock{entry}, nil))
+	catchRet := NewCatchRet(pad, exit)
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Errorf("expected panic when assigning %v to catchret operand", x)
+			}
+		}()
+		catchRet.Operands()[0].Set(x)
+	}()
+	if catchRet.From != pad {
+		t.Errorf("catchret operand mismatch; expected %v, got %v", pad, catchRet.From)
+	}
+	sw.Operands()[1].Set(zero)
+	if sw.Cases[0].X != zero {
+		t.Errorf("switch case operand mismatch; expected %v, got %v", zero, sw.Cases[0].X)
+	}
+
+	// Constant expressions.
+	g := NewGlobalDef("g", zero)
+	h := NewGlobalDef("h", one)
+	expr := constant.NewPtrToInt(g, types.I64)
+	for _, op := range expr.Operands() {
+		if *op == g {
+			*op = h
+		}
+	}
+	if want, got := "ptrtoint (i32* @h to i64)", expr.Ident(); got != want {
+		t.Errorf("constant expression mismatch; expected %q, got %q", want, got)
+	}
+}
@@ -37,6 +37,8 @@ type Terminator interface {
 	Def() string
 	// Succs returns the successor basic blocks of the terminator.
 	Succs() []*BasicBlock
+	// Operands returns a mutable list of operands of the terminator.
+	Operands() []*value.Use
 }
 
 // --- [ ret ] -----------------------------------------------------------------
@@ -85,6 +87,14 @@ func (term *TermRet) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermRet) Operands() []*value.Use {
+	if term.X == nil {
+		return nil
+	}
+	return uses(&term.X)
+}
+
 // --- [ br ] ------------------------------------------------------------------
 
 // TermBr is an unconditional LLVM IR br terminator.
@@ -126,6 +136,11 @@ func (term *TermBr) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermBr) Operands() []*value.Use {
+	return nil
+}
+
 // --- [ conditional br ] ------------------------------------------------------
 
 // TermCondBr is a conditional LLVM IR br terminator.
@@ -172,6 +187,11 @@ func (term *TermCondBr) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermCondBr) Operands() []*value.Use {
+	return uses(&term.Cond)
+}
+
 // --- [ switch ] --------------------------------------------------------------
 
 // TermSwitch is an LLVM IR switch terminator.
@@ -227,6 +247,31 @@ func (term *TermSwitch) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermSwitch) Operands() []*value.Use {
+	ops := make([]*value.Use, 0, 1+len(term.Cases))
+	ops = append(ops, value.NewUse(&term.X))
+	for _, c := range term.Cases {
+		c := c
+		ops = append(ops, &value.Use{
+			Get: func() value.Value {
+				if c.X == nil {
+					return nil
+				}
+				return c.X
+			},
+			Set: func(v value.Value) {
+				x, ok := v.(constant.Constant)
+				if !ok {
+					panic(fmt.Errorf("invalid switch case comparand; expected constant.Constant, got %T", v))
+				}
+				c.X = x
+			},
+		})
+	}
+	return ops
+}
+
 // ~~~ [ Switch case ] ~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~~
 
 // Case is a switch case.
@@ -295,6 +340,11 @@ func (term *TermIndirectBr) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermIndirectBr) Operands() []*value.Use {
+	return uses(&term.Addr)
+}
+
 // --- [ invoke ] --------------------------------------------------------------
 
 // TermInvoke is an LLVM IR invoke terminator.
@@ -439,6 +489,12 @@ func (term *TermInvoke) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermInvoke) Operands() []*value.Use {
+	ops := append(uses(&term.Invokee), sliceUses(term.Args)...)
+	return append(ops, bundleUses(term.OperandBundles)...)
+}
+
 // --- [ resume ] --------------------------------------------------------------
 
 // TermResume is an LLVM IR resume terminator.
@@ -475,6 +531,11 @@ func (term *TermResume) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermResume) Operands() []*value.Use {
+	return uses(&term.X)
+}
+
 // --- [ catchswitch ] ---------------------------------------------------------
 
 // TermCatchSwitch is an LLVM IR catchswitch terminator.
@@ -547,6 +608,11 @@ func (term *TermCatchSwitch) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermCatchSwitch) Operands() []*value.Use {
+	return []*value.Use{scopeUse(&term.Scope)}
+}
+
 // --- [ catchret ] ------------------------------------------------------------
 
 // TermCatchRet is an LLVM IR catchret terminator.
@@ -591,6 +657,26 @@ func (term *TermCatchRet) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermCatchRet) Operands() []*value.Use {
+	from := &value.Use{
+		Get: func() value.Value {
+			if term.From == nil {
+				return nil
+			}
+			return term.From
+		},
+		Set: func(v value.Value) {
+			from, ok := v.(*InstCatchPad)
+			if !ok {
+				panic(fmt.Errorf("invalid catchret operand; expected *ir.InstCatchPad, got %T", v))
+			}
+			term.From = from
+		},
+	}
+	return []*value.Use{from}
+}
+
 // --- [ cleanupret ] ----------------------------------------------------------
 
 // TermCleanupRet is an LLVM IR cleanupret terminator.
@@ -639,6 +725,26 @@ func (term *TermCleanupRet) Def() string {
 	return buf.String()
 }
 
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermCleanupRet) Operands() []*value.Use {
+	from := &value.Use{
+		Get: func() value.Value {
+			if term.From == nil {
+				return nil
+			}
+			return term.From
+		},
+		Set: func(v value.Value) {
+			from, ok := v.(*InstCleanupPad)
+			if !ok {
+				panic(fmt.Errorf("invalid cleanupret operand; expected *ir.InstCleanupPad, got %T", v))
+			}
+			term.From = from
+		},
+	}
+	return []*value.Use{from}
+}
+
 // --- [ unreachable ] ---------------------------------------------------------
 
 // TermUnreachable is an LLVM IR unreachable terminator.
@@ -670,3 +776,8 @@ func (term *TermUnreachable) Def() string {
 	}
 	return buf.String()
 }
+
+// Operands returns a mutable list of operands of the given terminator.
+func (term *TermUnreachable) Operands() []*value.Use {
+	return nil
+}
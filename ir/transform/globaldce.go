@@ -0,0 +1,201 @@
+// Package transform provides transformations of LLVM IR modules.
+package transform
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/constant"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/metadata"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Global dead code elimination ] ========================================
+
+// GlobalDCE removes the global variables, functions, aliases, IFuncs and Comdat
+// definitions of the given module which are not used, and reports whether the
+// module was changed.
+//
+// The roots of the module are its global variable, function, alias and IFunc
+// definitions which may be referenced from outside of the module (i.e. with
+// external, weak, common, appending or extern_weak linkage), and the global
+// variables llvm.used and llvm.compiler.used. A global value is used if
+// transitively referenced by a root; through the initializer of a global
+// variable, the body, prefix, prologue or personality of a function, the
+// aliasee of an alias, the resolver of an IFunc, or metadata. Every member of a
+// Comdat is used if any of its members is used.
+func GlobalDCE(m *ir.Module) bool {
+	live := make(map[value.Value]bool)
+	members := make(map[*ir.ComdatDef][]value.Value)
+	for _, g := range m.Globals {
+		if g.Comdat != nil {
+			members[g.Comdat] = append(members[g.Comdat], g)
+		}
+	}
+	for _, f := range m.Funcs {
+		if f.Comdat != nil {
+			members[f.Comdat] = append(members[f.Comdat], f)
+		}
+	}
+	var work []value.Value
+	mark := func(v value.Value) {
+		if !live[v] {
+			live[v] = true
+			work = append(work, v)
+		}
+	}
+	// use marks the global values referenced by the given value as used.
+	var use func(v value.Value)
+	use = func(v value.Value) {
+		switch v := v.(type) {
+		case nil:
+		case *ir.Global, *ir.Function, *ir.Alias, *ir.IFunc:
+			mark(v)
+		case *metadata.Value:
+			if v, ok := v.Value.(value.Value); ok {
+				use(v)
+			}
+		case constant.Constant:
+			for _, op := range irutil.Operands(v) {
+				use(op)
+			}
+		}
+	}
+	// Mark roots.
+	for _, g := range m.Globals {
+		if isRoot(g.Linkage, g.Init == nil) || g.Name() == "llvm.used" || g.Name() == "llvm.compiler.used" {
+			mark(g)
+		}
+	}
+	for _, f := range m.Funcs {
+		if isRoot(f.Linkage, len(f.Blocks) == 0) {
+			mark(f)
+		}
+	}
+	for _, alias := range m.Aliases {
+		if isRoot(alias.Linkage, false) {
+			mark(alias)
+		}
+	}
+	for _, ifunc := range m.IFuncs {
+		if isRoot(ifunc.Linkage, false) {
+			mark(ifunc)
+		}
+	}
+	irutil.WalkMetadata(m, func(md metadata.Metadata) {
+		if v, ok := md.(value.Value); ok {
+			use(v)
+		}
+	})
+	// Propagate uses.
+	for len(work) > 0 {
+		v := work[len(work)-1]
+		work = work[:len(work)-1]
+		switch v := v.(type) {
+		case *ir.Global:
+			use(v.Init)
+			if v.Comdat != nil {
+				for _, member := range members[v.Comdat] {
+					mark(member)
+				}
+			}
+		case *ir.Function:
+			use(v.Prefix)
+			use(v.Prologue)
+			use(v.Personality)
+			for _, block := range v.Blocks {
+				for _, inst := range block.Insts {
+					for _, op := range irutil.Operands(inst) {
+						use(op)
+					}
+				}
+				if block.Term != nil {
+					for _, op := range irutil.Operands(block.Term) {
+						use(op)
+					}
+				}
+			}
+			if v.Comdat != nil {
+				for _, member := range members[v.Comdat] {
+					mark(member)
+				}
+			}
+		case *ir.Alias:
+			use(v.Aliasee)
+		case *ir.IFunc:
+			use(v.Resolver)
+		}
+	}
+	// Remove unused global values.
+	changed := false
+	var globals []*ir.Global
+	for _, g := range m.Globals {
+		if !live[g] {
+			changed = true
+			continue
+		}
+		globals = append(globals, g)
+	}
+	m.Globals = globals
+	var funcs []*ir.Function
+	for _, f := range m.Funcs {
+		if !live[f] {
+			changed = true
+			continue
+		}
+		funcs = append(funcs, f)
+	}
+	m.Funcs = funcs
+	var aliases []*ir.Alias
+	for _, alias := range m.Aliases {
+		if !live[alias] {
+			changed = true
+			continue
+		}
+		aliases = append(aliases, alias)
+	}
+	m.Aliases = aliases
+	var ifuncs []*ir.IFunc
+	for _, ifunc := range m.IFuncs {
+		if !live[ifunc] {
+			changed = true
+			continue
+		}
+		ifuncs = append(ifuncs, ifunc)
+	}
+	m.IFuncs = ifuncs
+	// Remove unused Comdat definitions.
+	var comdats []*ir.ComdatDef
+	for _, def := range m.ComdatDefs {
+		used := false
+		for _, member := range members[def] {
+			if live[member] {
+				used = true
+				break
+			}
+		}
+		if !used {
+			changed = true
+			continue
+		}
+		comdats = append(comdats, def)
+	}
+	m.ComdatDefs = comdats
+	return changed
+}
+
+// ### [ Helper functions ] ####################################################
+
+// isRoot reports whether a global value with the given linkage may be
+// referenced from outside of the module, and must therefore be kept. Unused
+// declarations are never roots.
+func isRoot(linkage enum.Linkage, declaration bool) bool {
+	if declaration {
+		return false
+	}
+	switch linkage {
+	case enum.LinkageInternal, enum.LinkagePrivate, enum.LinkageLinkOnce, enum.LinkageLinkOnceODR, enum.LinkageAvailableExternally:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,108 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestGlobalDCE(t *testing.T) {
+	const input = `
+$kept = comdat any
+$dead = comdat any
+
+@llvm.used = appending global [1 x i8*] [i8* bitcast (i32* @used to i8*)], section "llvm.metadata"
+@used = internal global i32 0
+@exported = global i32* @referenced
+@referenced = internal global i32 1
+@unreferenced = internal global i32 2
+@kept.member = linkonce_odr global i32 3, comdat($kept)
+@dead.member = linkonce_odr global i32 4, comdat($dead)
+@decl = external global i32
+
+@alias = internal alias void (), void ()* @aliasee
+@dead.alias = internal alias void (), void ()* @dead.func
+
+define void @main() personality i32 (...)* @personality {
+	call void @alias()
+	call void @kept()
+	call void @llvm.dbg.value(metadata i32* @md, metadata !0, metadata !0)
+	ret void
+}
+
+define internal void @aliasee() {
+	ret void
+}
+
+define internal void @dead.func() {
+	call void @dead.callee()
+	ret void
+}
+
+define internal void @dead.callee() {
+	ret void
+}
+
+define linkonce_odr void @kept() comdat {
+	ret void
+}
+
+declare i32 @personality(...)
+
+declare void @unused.decl()
+
+declare void @llvm.dbg.value(metadata, metadata, metadata)
+
+@md = internal global i32 5
+
+!0 = !{}
+`
+	const want = `$kept = comdat any
+
+@llvm.used = appending global [1 x i8*] [i8* bitcast (i32* @used to i8*)], section "llvm.metadata"
+@used = internal global i32 0
+@exported = global i32* @referenced
+@referenced = internal global i32 1
+@kept.member = linkonce_odr global i32 3, comdat($kept)
+@md = internal global i32 5
+
+@alias = internal alias void (), void ()* @aliasee
+
+define void @main() personality i32 (...)* @personality {
+; <label>:0
+	call void @alias()
+	call void @kept()
+	call void @llvm.dbg.value(metadata i32* @md, metadata !0, metadata !0)
+	ret void
+}
+
+define internal void @aliasee() {
+; <label>:0
+	ret void
+}
+
+define linkonce_odr void @kept() comdat {
+; <label>:0
+	ret void
+}
+
+declare i32 @personality(...)
+
+declare void @llvm.dbg.value(metadata, metadata, metadata)
+
+!0 = !{}
+`
+	m, err := asm.ParseString("globaldce.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	if !GlobalDCE(m) {
+		t.Errorf("expected module to be changed")
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if GlobalDCE(m) {
+		t.Errorf("expected module to be unchanged")
+	}
+}
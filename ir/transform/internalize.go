@@ -0,0 +1,57 @@
+package transform
+
+import (
+	"strings"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/enum"
+)
+
+// === [ Internalize ] =========================================================
+
+// Internalize changes the linkage of the global variable, function and alias
+// definitions of the given module which are not in the export list to internal
+// linkage, and reports whether the module was changed. Unused definitions may
+// subsequently be removed by GlobalDCE.
+//
+// Declarations, definitions with local, appending or available_externally
+// linkage, and global values with the "llvm." name prefix (e.g. llvm.used and
+// llvm.global_ctors) are left unchanged.
+func Internalize(m *ir.Module, exports []string) bool {
+	exported := make(map[string]bool)
+	for _, name := range exports {
+		exported[name] = true
+	}
+	changed := false
+	// internalize updates the linkage, visibility and DLL storage class of the
+	// definition with the given name for internal linkage, unless exported.
+	internalize := func(name string, linkage *enum.Linkage, visibility *enum.Visibility, dllStorageClass *enum.DLLStorageClass) {
+		if exported[name] || strings.HasPrefix(name, "llvm.") {
+			return
+		}
+		switch *linkage {
+		case enum.LinkageInternal, enum.LinkagePrivate, enum.LinkageAppending, enum.LinkageAvailableExternally:
+			return
+		}
+		// Global values with local linkage must have default visibility and may
+		// not be imported or exported from a DLL.
+		*linkage = enum.LinkageInternal
+		*visibility = enum.VisibilityNone
+		*dllStorageClass = enum.DLLStorageClassNone
+		changed = true
+	}
+	for _, g := range m.Globals {
+		if g.Init != nil {
+			internalize(g.Name(), &g.Linkage, &g.Visibility, &g.DLLStorageClass)
+		}
+	}
+	for _, f := range m.Funcs {
+		if len(f.Blocks) > 0 {
+			internalize(f.Name(), &f.Linkage, &f.Visibility, &f.DLLStorageClass)
+		}
+	}
+	for _, alias := range m.Aliases {
+		internalize(alias.Name(), &alias.Linkage, &alias.Visibility, &alias.DLLStorageClass)
+	}
+	return changed
+}
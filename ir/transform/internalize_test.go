@@ -0,0 +1,71 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestInternalize(t *testing.T) {
+	const input = `
+@llvm.used = appending global [1 x i8*] [i8* bitcast (void ()* @helper to i8*)], section "llvm.metadata"
+@exported = global i32 0
+@hidden = hidden global i32 1
+@ext = external global i32
+@avail = available_externally global i32 2
+
+@alias = alias void (), void ()* @helper
+
+define void @main() {
+	ret void
+}
+
+define dllexport void @helper() {
+	ret void
+}
+
+define weak_odr void @weak() {
+	ret void
+}
+
+declare void @decl()
+`
+	const want = `@llvm.used = appending global [1 x i8*] [i8* bitcast (void ()* @helper to i8*)], section "llvm.metadata"
+@exported = global i32 0
+@hidden = internal global i32 1
+@ext = external global i32
+@avail = available_externally global i32 2
+
+@alias = internal alias void (), void ()* @helper
+
+define void @main() {
+; <label>:0
+	ret void
+}
+
+define internal void @helper() {
+; <label>:0
+	ret void
+}
+
+define internal void @weak() {
+; <label>:0
+	ret void
+}
+
+declare void @decl()
+`
+	m, err := asm.ParseString("internalize.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	if !Internalize(m, []string{"main", "exported"}) {
+		t.Errorf("expected module to be changed")
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if Internalize(m, []string{"main", "exported"}) {
+		t.Errorf("expected module to be unchanged")
+	}
+}
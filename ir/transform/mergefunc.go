@@ -0,0 +1,157 @@
+package transform
+
+import (
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/enum"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+)
+
+// === [ Merge functions ] =====================================================
+
+// MergeFunctions merges the structurally equal function definitions of the
+// given module (see irutil.EqualFuncs), and reports whether the module was
+// changed.
+//
+// Of each set of equal functions, the first in order of occurrence is kept.
+// Duplicates with local linkage and insignificant address (i.e. unnamed_addr or
+// local_unnamed_addr) are removed, after replacing each of their uses with the
+// kept function. Other duplicates are retained as thunks tail calling the kept
+// function, as their address may be significant or referenced from outside of
+// the module, and direct calls to them are redirected to the kept function.
+// Unused thunks may subsequently be removed by GlobalDCE.
+//
+// Functions which may be replaced at link time (i.e. with weak, linkonce,
+// extern_weak or available_externally linkage) are not merged. Neither are
+// variadic duplicates which would be retained as thunks, as the variadic
+// arguments cannot be forwarded.
+func MergeFunctions(m *ir.Module) bool {
+	// Map from function to kept equal function.
+	replace := make(map[value.Value]*ir.Function)
+	// Map from hash to candidate functions kept.
+	kept := make(map[uint64][]*ir.Function)
+	var dups []*ir.Function
+	for _, f := range m.Funcs {
+		if len(f.Blocks) == 0 || isInterposable(f.Linkage) {
+			continue
+		}
+		h := irutil.HashFunc(f)
+		found := false
+		for _, g := range kept[h] {
+			if irutil.EqualFuncs(f, g) {
+				if f.Sig.Variadic && !isRemovable(f) {
+					found = true
+					break
+				}
+				replace[f] = g
+				dups = append(dups, f)
+				found = true
+				break
+			}
+		}
+		if !found {
+			kept[h] = append(kept[h], f)
+		}
+	}
+	if len(dups) == 0 {
+		return false
+	}
+	// Redirect uses of duplicates.
+	removed := make(map[*ir.Function]bool)
+	for _, f := range dups {
+		if isRemovable(f) {
+			removed[f] = true
+		}
+	}
+	irutil.Walk(m, func(c *irutil.Cursor) bool {
+		if !c.IsOperand() {
+			return true
+		}
+		g, ok := replace[c.Node().(value.Value)]
+		if !ok {
+			return true
+		}
+		if removed[c.Node().(*ir.Function)] || isCallee(c.Parent(), c.Node().(value.Value)) {
+			c.Replace(g)
+		}
+		return true
+	}, nil)
+	// Remove duplicates, or replace their bodies by thunks.
+	var funcs []*ir.Function
+	for _, f := range m.Funcs {
+		if removed[f] {
+			continue
+		}
+		if g, ok := replace[f]; ok {
+			makeThunk(f, g)
+		}
+		funcs = append(funcs, f)
+	}
+	m.Funcs = funcs
+	return true
+}
+
+// ### [ Helper functions ] ####################################################
+
+// makeThunk replaces the body of the given function by a tail call to the
+// callee, forwarding the function parameters.
+func makeThunk(f, callee *ir.Function) {
+	f.Blocks = nil
+	f.Personality = nil
+	args := make([]value.Value, len(f.Params))
+	for i, param := range f.Params {
+		args[i] = param
+	}
+	entry := f.NewBlock("")
+	call := entry.NewCall(callee, args...)
+	call.Tail = enum.TailTail
+	call.CallingConv = callee.CallingConv
+	if callee.Sig.RetType.Equal(types.Void) {
+		entry.NewRet(nil)
+	} else {
+		entry.NewRet(call)
+	}
+	if err := f.AssignIDs(); err != nil {
+		panic(err)
+	}
+}
+
+// isRemovable reports whether the given duplicate function may be removed after
+// replacing its uses, as it is local to the module and its address is
+// insignificant.
+func isRemovable(f *ir.Function) bool {
+	return isLocal(f.Linkage) && f.UnnamedAddr != enum.UnnamedAddrNone
+}
+
+// isCallee reports whether v is the callee of the given instruction or
+// terminator.
+func isCallee(n interface{}, v value.Value) bool {
+	switch n := n.(type) {
+	case *ir.InstCall:
+		return n.Callee == v
+	case *ir.TermInvoke:
+		return n.Invokee == v
+	}
+	return false
+}
+
+// isInterposable reports whether a global value with the given linkage may be
+// replaced by a different definition at link time.
+func isInterposable(linkage enum.Linkage) bool {
+	switch linkage {
+	case enum.LinkageWeak, enum.LinkageLinkOnce, enum.LinkageExternWeak, enum.LinkageAvailableExternally:
+		return true
+	}
+	return false
+}
+
+// isLocal reports whether a global value with the given linkage is local to
+// the module.
+func isLocal(linkage enum.Linkage) bool {
+	switch linkage {
+	case enum.LinkageInternal, enum.LinkagePrivate:
+		return true
+	}
+	return false
+}
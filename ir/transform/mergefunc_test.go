@@ -0,0 +1,141 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+)
+
+func TestMergeFunctions(t *testing.T) {
+	const input = `
+@table = global [2 x i32 (i32)*] [i32 (i32)* @inc, i32 (i32)* @inc.dup]
+
+define i32 @main() {
+	%a = call i32 @inc(i32 1)
+	%b = call i32 @inc.dup(i32 %a)
+	%c = call i32 @exported.dup(i32 %b)
+	%d = call i32 @weak.dup(i32 %c)
+	ret i32 %d
+}
+
+define internal i32 @inc(i32 %x) {
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+
+define internal i32 @inc.dup(i32 %a) unnamed_addr {
+	%b = add i32 %a, 1
+	ret i32 %b
+}
+
+define i32 @exported.dup(i32) {
+	%2 = add i32 %0, 1
+	ret i32 %2
+}
+
+define weak i32 @weak.dup(i32 %x) {
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+`
+	const want = `@table = global [2 x i32 (i32)*] [i32 (i32)* @inc, i32 (i32)* @inc]
+
+define i32 @main() {
+; <label>:0
+	%a = call i32 @inc(i32 1)
+	%b = call i32 @inc(i32 %a)
+	%c = call i32 @inc(i32 %b)
+	%d = call i32 @weak.dup(i32 %c)
+	ret i32 %d
+}
+
+define internal i32 @inc(i32 %x) {
+; <label>:0
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+
+define i32 @exported.dup(i32) {
+; <label>:1
+	%2 = tail call i32 @inc(i32 %0)
+	ret i32 %2
+}
+
+define weak i32 @weak.dup(i32 %x) {
+; <label>:0
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+`
+	m, err := asm.ParseString("mergefunc.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	if !MergeFunctions(m) {
+		t.Errorf("expected module to be changed")
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	if MergeFunctions(m) {
+		t.Errorf("expected module to be unchanged")
+	}
+}
+
+func TestMergeFunctionsVariadic(t *testing.T) {
+	// Variadic duplicates are only merged if they may be removed, as thunks
+	// cannot forward variadic arguments.
+	const input = `
+define i32 @main() {
+	%a = call i32 (i32, ...) @a(i32 1, i32 2)
+	%b = call i32 (i32, ...) @b(i32 %a, i32 3)
+	%c = call i32 (i32, ...) @c(i32 %b, i32 4)
+	ret i32 %c
+}
+
+define i32 @a(i32 %x, ...) {
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+
+define i32 @b(i32 %x, ...) {
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+
+define internal i32 @c(i32 %x, ...) unnamed_addr {
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+`
+	const want = `define i32 @main() {
+; <label>:0
+	%a = call i32 (i32, ...) @a(i32 1, i32 2)
+	%b = call i32 (i32, ...) @b(i32 %a, i32 3)
+	%c = call i32 (i32, ...) @a(i32 %b, i32 4)
+	ret i32 %c
+}
+
+define i32 @a(i32 %x, ...) {
+; <label>:0
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+
+define i32 @b(i32 %x, ...) {
+; <label>:0
+	%y = add i32 %x, 1
+	ret i32 %y
+}
+`
+	m, err := asm.ParseString("mergefunc_variadic.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	if !MergeFunctions(m) {
+		t.Errorf("expected module to be changed")
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+}
@@ -0,0 +1,426 @@
+package transform
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/llir/llvm/internal/enc"
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/analysis"
+	"github.com/llir/llvm/ir/irutil"
+	"github.com/llir/llvm/ir/types"
+	"github.com/llir/llvm/ir/value"
+	"github.com/pkg/errors"
+)
+
+// === [ Out-of-SSA translation ] ==============================================
+
+// NonSSA is the non-SSA form of a function, as produced by OutOfSSA; i.e. an
+// assignment of SSA values to variables, and the copies between variables
+// replacing phi instructions.
+type NonSSA struct {
+	// Variables of the function, in order of ID.
+	Vars []*Var
+	// Copies executed in order at the exit of each basic block, before its
+	// terminator.
+	Copies map[*ir.BasicBlock][]*Copy
+
+	// Variable of each SSA value.
+	varOf map[value.Value]*Var
+}
+
+// VarOf returns the variable of the given SSA value (i.e. function parameter or
+// result of an instruction or terminator), or nil if not an SSA value.
+func (n *NonSSA) VarOf(v value.Value) *Var {
+	return n.varOf[v]
+}
+
+// Var is a variable of a function out of SSA form; i.e. a storage location
+// shared by a set of SSA values.
+type Var struct {
+	// Variable ID; unique within the function.
+	ID int
+	// Variable type.
+	Typ types.Type
+	// SSA values assigned to the variable, in order of definition; empty for
+	// temporary variables introduced to break copy cycles.
+	Values []value.Value
+}
+
+// String returns the LLVM syntax representation of the variable as a
+// type-value pair.
+func (v *Var) String() string {
+	return fmt.Sprintf("%s %s", v.Type(), v.Ident())
+}
+
+// Type returns the type of the variable.
+func (v *Var) Type() types.Type {
+	return v.Typ
+}
+
+// Ident returns the identifier associated with the variable.
+func (v *Var) Ident() string {
+	return enc.Local("v" + strconv.Itoa(v.ID))
+}
+
+// Copy is a copy of a value to a variable.
+type Copy struct {
+	// Destination variable.
+	Dst *Var
+	// Source value.
+	//
+	// Src has one of the following underlying types.
+	//
+	//    *transform.Var
+	//    constant.Constant
+	Src value.Value
+}
+
+// String returns the string representation of the copy.
+func (c *Copy) String() string {
+	return fmt.Sprintf("%s = %s", c.Dst.Ident(), c.Src.Ident())
+}
+
+// OutOfSSA translates the given function out of SSA form, by replacing its phi
+// instructions with copies in predecessor basic blocks.
+//
+// Edges to basic blocks with phi instructions from basic blocks with multiple
+// successors (which includes all such critical edges) are first split, so that
+// the copies of each edge are executed only when the edge is taken. The
+// parallel copies of each edge are then sequentialized, introducing temporary
+// variables to break copy cycles (e.g. swaps). If coalesce is set, the result
+// of each phi instruction shares a variable with its incoming values where
+// their live ranges do not interfere, thus eliminating the corresponding
+// copies; otherwise, each SSA value is assigned a variable of its own.
+//
+// The phi instructions are removed from the function; any remaining uses of
+// SSA values refer to their variables (see NonSSA.VarOf).
+func OutOfSSA(f *ir.Function, coalesce bool) (*NonSSA, error) {
+	if err := splitPhiEdges(f); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	// Locate SSA values and their definitions.
+	d := &destructor{
+		defs: make(map[value.Value]defPos),
+		rep:  make(map[value.Value]value.Value),
+	}
+	d.addValues(f)
+	if coalesce {
+		d.dom = analysis.NewDomTree(f)
+		d.live = analysis.NewLiveness(f)
+		d.liveSets = make(map[defPos]map[value.Value]bool)
+		for _, block := range f.Blocks {
+			for _, inst := range block.Insts {
+				phi, ok := inst.(*ir.InstPhi)
+				if !ok {
+					continue
+				}
+				for _, inc := range phi.Incs {
+					if _, ok := d.defs[inc.X]; ok {
+						d.tryCoalesce(phi, inc.X)
+					}
+				}
+			}
+		}
+	}
+	// Assign variables.
+	n := &NonSSA{
+		Copies: make(map[*ir.BasicBlock][]*Copy),
+		varOf:  make(map[value.Value]*Var),
+	}
+	for _, v := range d.values {
+		root := d.find(v)
+		x, ok := n.varOf[root]
+		if !ok {
+			x = &Var{ID: len(n.Vars), Typ: v.Type()}
+			n.Vars = append(n.Vars, x)
+			n.varOf[root] = x
+		}
+		x.Values = append(x.Values, v)
+		n.varOf[v] = x
+	}
+	// Replace phi instructions with copies.
+	for _, block := range f.Blocks {
+		var phis []*ir.InstPhi
+		var insts []ir.Instruction
+		for _, inst := range block.Insts {
+			if phi, ok := inst.(*ir.InstPhi); ok {
+				phis = append(phis, phi)
+				continue
+			}
+			insts = append(insts, inst)
+		}
+		if len(phis) == 0 {
+			continue
+		}
+		for _, pred := range phiPreds(phis) {
+			var copies []*Copy
+			for _, phi := range phis {
+				for _, inc := range phi.Incs {
+					if inc.Pred != pred {
+						continue
+					}
+					src := inc.X
+					if x, ok := n.varOf[src]; ok {
+						src = x
+					}
+					copies = append(copies, &Copy{Dst: n.varOf[phi], Src: src})
+					break
+				}
+			}
+			n.Copies[pred] = append(n.Copies[pred], n.sequentialize(copies)...)
+		}
+		block.Insts = insts
+	}
+	return n, nil
+}
+
+// sequentialize returns a sequence of copies equivalent to the given parallel
+// copies, introducing temporary variables to break copy cycles.
+func (n *NonSSA) sequentialize(parallel []*Copy) []*Copy {
+	var pending []*Copy
+	for _, c := range parallel {
+		if c.Src != c.Dst {
+			pending = append(pending, &Copy{Dst: c.Dst, Src: c.Src})
+		}
+	}
+	var seq []*Copy
+	for len(pending) > 0 {
+		// Emit a copy whose destination is not the source of any other pending
+		// copy.
+		emitted := false
+		for i, c := range pending {
+			if !isSource(pending, c.Dst) {
+				seq = append(seq, c)
+				pending = append(pending[:i], pending[i+1:]...)
+				emitted = true
+				break
+			}
+		}
+		if emitted {
+			continue
+		}
+		// The destination of every pending copy is the source of another;
+		// break the cycle by saving the destination of the first copy to a
+		// temporary variable.
+		dst := pending[0].Dst
+		tmp := &Var{ID: len(n.Vars), Typ: dst.Typ}
+		n.Vars = append(n.Vars, tmp)
+		seq = append(seq, &Copy{Dst: tmp, Src: dst})
+		for _, c := range pending {
+			if c.Src == dst {
+				c.Src = tmp
+			}
+		}
+	}
+	return seq
+}
+
+// destructor tracks the SSA values of a function during out-of-SSA
+// translation.
+type destructor struct {
+	// SSA values of the function, in order of definition.
+	values []value.Value
+	// Definition position of each SSA value.
+	defs map[value.Value]defPos
+	// Union-find representative of each SSA value; values sharing a
+	// representative are assigned the same variable.
+	rep map[value.Value]value.Value
+	// Dominator tree of the function; used for coalescing.
+	dom *analysis.DomTree
+	// Liveness of SSA values; used for coalescing.
+	live *analysis.Liveness
+	// Values live immediately after each definition position; used for
+	// coalescing.
+	liveSets map[defPos]map[value.Value]bool
+}
+
+// defPos is the definition position of an SSA value.
+type defPos struct {
+	// Basic block of the definition.
+	block *ir.BasicBlock
+	// Index of the defining instruction in the basic block; the index -1
+	// denotes phi instructions (which are defined simultaneously at the entry
+	// of the basic block), the index -2 denotes function parameters, and the
+	// index len(block.Insts) denotes the terminator.
+	index int
+}
+
+// addValues adds the SSA values of the given function.
+func (d *destructor) addValues(f *ir.Function) {
+	add := func(v value.Value, block *ir.BasicBlock, index int) {
+		if v.Type().Equal(types.Void) {
+			return
+		}
+		d.values = append(d.values, v)
+		d.defs[v] = defPos{block: block, index: index}
+		d.rep[v] = v
+	}
+	for _, param := range f.Params {
+		add(param, f.Blocks[0], -2)
+	}
+	for _, block := range f.Blocks {
+		for i, inst := range block.Insts {
+			v, ok := inst.(value.Value)
+			if !ok {
+				continue
+			}
+			if _, ok := inst.(*ir.InstPhi); ok {
+				add(v, block, -1)
+			} else {
+				add(v, block, i)
+			}
+		}
+		if v, ok := block.Term.(value.Value); ok {
+			add(v, block, len(block.Insts))
+		}
+	}
+}
+
+// find returns the union-find representative of the given SSA value.
+func (d *destructor) find(v value.Value) value.Value {
+	for d.rep[v] != v {
+		d.rep[v] = d.rep[d.rep[v]]
+		v = d.rep[v]
+	}
+	return v
+}
+
+// tryCoalesce merges the classes of the given SSA values, unless any of their
+// members interfere.
+func (d *destructor) tryCoalesce(a, b value.Value) {
+	ra, rb := d.find(a), d.find(b)
+	if ra == rb {
+		return
+	}
+	var as, bs []value.Value
+	for _, v := range d.values {
+		switch d.find(v) {
+		case ra:
+			as = append(as, v)
+		case rb:
+			bs = append(bs, v)
+		}
+	}
+	for _, x := range as {
+		for _, y := range bs {
+			if d.interfere(x, y) {
+				return
+			}
+		}
+	}
+	d.rep[rb] = ra
+}
+
+// interfere reports whether the live ranges of the given SSA values interfere;
+// i.e. whether either is live immediately after the definition of the other.
+// Function parameters interfere with each other, as do phi instructions of the
+// same basic block.
+func (d *destructor) interfere(x, y value.Value) bool {
+	dx, dy := d.defs[x], d.defs[y]
+	if dx.block == dy.block && dx.index == dy.index && dx.index < 0 {
+		return true
+	}
+	switch {
+	case d.dominates(dx, dy):
+		return d.liveAfter(dy)[x]
+	case d.dominates(dy, dx):
+		return d.liveAfter(dx)[y]
+	}
+	// The live ranges of SSA values whose definitions do not dominate each
+	// other never interfere.
+	return false
+}
+
+// dominates reports whether definition position a dominates b.
+func (d *destructor) dominates(a, b defPos) bool {
+	if a.block == b.block {
+		return a.index < b.index
+	}
+	return d.dom.Dominates(a.block, b.block)
+}
+
+// liveAfter returns the set of values live immediately after the given
+// definition position.
+func (d *destructor) liveAfter(pos defPos) map[value.Value]bool {
+	if set, ok := d.liveSets[pos]; ok {
+		return set
+	}
+	var live []value.Value
+	switch {
+	case pos.index < 0:
+		live = d.live.LiveIn(pos.block)
+	case pos.index >= len(pos.block.Insts):
+		live = d.live.LiveOut(pos.block)
+	default:
+		live = d.live.LiveAt(pos.block, pos.index+1)
+	}
+	set := make(map[value.Value]bool)
+	for _, v := range live {
+		set[v] = true
+	}
+	d.liveSets[pos] = set
+	return set
+}
+
+// ### [ Helper functions ] ####################################################
+
+// splitPhiEdges splits the edges to basic blocks with phi instructions from
+// basic blocks with multiple successors, by inserting a new basic block on each
+// such edge.
+func splitPhiEdges(f *ir.Function) error {
+	hasPhi := func(block *ir.BasicBlock) bool {
+		if len(block.Insts) == 0 {
+			return false
+		}
+		_, ok := block.Insts[0].(*ir.InstPhi)
+		return ok
+	}
+	for _, block := range append([]*ir.BasicBlock(nil), f.Blocks...) {
+		succs := analysis.Succs(block)
+		if len(succs) < 2 {
+			continue
+		}
+		// Split edges in reverse order, as new basic blocks are inserted
+		// immediately after the predecessor.
+		done := make(map[*ir.BasicBlock]bool)
+		for i := len(succs) - 1; i >= 0; i-- {
+			succ := succs[i]
+			if done[succ] || !hasPhi(succ) {
+				continue
+			}
+			done[succ] = true
+			if _, err := irutil.SplitEdge(block, succ); err != nil {
+				return errors.WithStack(err)
+			}
+		}
+	}
+	return nil
+}
+
+// phiPreds returns the incoming basic blocks of the given phi instructions, in
+// order of occurrence.
+func phiPreds(phis []*ir.InstPhi) []*ir.BasicBlock {
+	var preds []*ir.BasicBlock
+	seen := make(map[*ir.BasicBlock]bool)
+	for _, phi := range phis {
+		for _, inc := range phi.Incs {
+			if !seen[inc.Pred] {
+				seen[inc.Pred] = true
+				preds = append(preds, inc.Pred)
+			}
+		}
+	}
+	return preds
+}
+
+// isSource reports whether the given variable is the source of any of the
+// copies.
+func isSource(copies []*Copy, v *Var) bool {
+	for _, c := range copies {
+		if c.Src == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+)
+
+func TestOutOfSSA(t *testing.T) {
+	const input = `
+define i32 @swap(i32 %n) {
+entry:
+	br label %loop
+
+loop:
+	%a = phi i32 [ 0, %entry ], [ %b, %loop ]
+	%b = phi i32 [ 1, %entry ], [ %a, %loop ]
+	%i = phi i32 [ 0, %entry ], [ %j, %loop ]
+	%j = add i32 %i, 1
+	%c = icmp slt i32 %j, %n
+	br i1 %c, label %loop, label %exit
+
+exit:
+	ret i32 %a
+}
+
+define i32 @lost(i32 %n) {
+entry:
+	br label %loop
+
+loop:
+	%x = phi i32 [ 0, %entry ], [ %y, %loop ]
+	%y = add i32 %x, 1
+	%c = icmp slt i32 %y, %n
+	br i1 %c, label %loop, label %exit
+
+exit:
+	ret i32 %x
+}
+`
+	golden := []struct {
+		coalesce bool
+		// Variables of the swap function.
+		swapVars string
+		// Copies of the swap function.
+		swap string
+		// Copies of the lost-copy function.
+		lost string
+	}{
+		{
+			coalesce: false,
+			swapVars: "%v0=%n %v1=%a %v2=%b %v3=%i %v4=%j %v5=%c %v6=",
+			swap:     "entry: %v1 = 0, %v2 = 1, %v3 = 0; loop.loop_crit_edge: %v3 = %v4, %v6 = %v1, %v1 = %v2, %v2 = %v6",
+			lost:     "entry: %v1 = 0; loop.loop_crit_edge: %v1 = %v2",
+		},
+		{
+			coalesce: true,
+			swapVars: "%v0=%n %v1=%a %v2=%b %v3=%i,%j %v4=%c %v5=",
+			swap:     "entry: %v1 = 0, %v2 = 1, %v3 = 0; loop.loop_crit_edge: %v5 = %v1, %v1 = %v2, %v2 = %v5",
+			lost:     "entry: %v1 = 0; loop.loop_crit_edge: %v1 = %v2",
+		},
+	}
+	for _, g := range golden {
+		m, err := asm.ParseString("outofssa.ll", input)
+		if err != nil {
+			t.Fatalf("unable to parse module; %v", err)
+		}
+		swap, err := OutOfSSA(m.Funcs[0], g.coalesce)
+		if err != nil {
+			t.Errorf("coalesce=%v: unable to translate swap out of SSA form; %v", g.coalesce, err)
+			continue
+		}
+		var vars []string
+		for _, v := range swap.Vars {
+			var idents []string
+			for _, x := range v.Values {
+				idents = append(idents, x.Ident())
+			}
+			vars = append(vars, fmt.Sprintf("%s=%s", v.Ident(), strings.Join(idents, ",")))
+		}
+		if got := strings.Join(vars, " "); got != g.swapVars {
+			t.Errorf("coalesce=%v: variables of swap mismatch; expected %q, got %q", g.coalesce, g.swapVars, got)
+		}
+		if got := copiesString(m.Funcs[0], swap); got != g.swap {
+			t.Errorf("coalesce=%v: copies of swap mismatch; expected %q, got %q", g.coalesce, g.swap, got)
+		}
+		lost, err := OutOfSSA(m.Funcs[1], g.coalesce)
+		if err != nil {
+			t.Errorf("coalesce=%v: unable to translate lost out of SSA form; %v", g.coalesce, err)
+			continue
+		}
+		if got := copiesString(m.Funcs[1], lost); got != g.lost {
+			t.Errorf("coalesce=%v: copies of lost mismatch; expected %q, got %q", g.coalesce, g.lost, got)
+		}
+		if lost.VarOf(m.Funcs[1].Params[0]) == nil {
+			t.Errorf("coalesce=%v: expected variable of parameter %%n", g.coalesce)
+		}
+	}
+	// Check translated function.
+	m, err := asm.ParseString("outofssa.ll", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	if _, err := OutOfSSA(m.Funcs[0], true); err != nil {
+		t.Fatalf("unable to translate swap out of SSA form; %v", err)
+	}
+	const want = `define i32 @swap(i32 %n) {
+entry:
+	br label %loop
+
+loop:
+	%j = add i32 %i, 1
+	%c = icmp slt i32 %j, %n
+	br i1 %c, label %loop.loop_crit_edge, label %exit
+
+loop.loop_crit_edge:
+	br label %loop
+
+exit:
+	ret i32 %a
+}`
+	if got := m.Funcs[0].Def(); got != want {
+		t.Errorf("function mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+// copiesString returns a string representation of the copies of the given
+// function.
+func copiesString(f *ir.Function, n *NonSSA) string {
+	var ss []string
+	for _, block := range f.Blocks {
+		if len(n.Copies[block]) == 0 {
+			continue
+		}
+		var cs []string
+		for _, c := range n.Copies[block] {
+			cs = append(cs, c.String())
+		}
+		ss = append(ss, fmt.Sprintf("%s: %s", block.Name(), strings.Join(cs, ", ")))
+	}
+	return strings.Join(ss, "; ")
+}
@@ -0,0 +1,141 @@
+package transform
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/llir/llvm/ir"
+	"github.com/llir/llvm/ir/debuginfo"
+	"github.com/llir/llvm/ir/irutil"
+)
+
+// === [ Pass registry ] =======================================================
+
+// Pass is a named transformation of LLVM IR modules.
+type Pass struct {
+	// Pass name (e.g. "globaldce"); used to select the pass in a pipeline.
+	Name string
+	// One-line description of the pass.
+	Desc string
+	// Run transforms the given module, and reports whether the module was
+	// changed.
+	Run func(m *ir.Module) (bool, error)
+}
+
+var (
+	// passesMu protects passes.
+	passesMu sync.RWMutex
+	// passes maps from pass name to registered pass.
+	passes = make(map[string]*Pass)
+)
+
+// Register makes the given pass available by name (e.g. to the llopt tool).
+// Passes are typically registered in the init function of the package defining
+// them. Register panics if a pass with the same name is already registered, or
+// if the pass has no name or Run function.
+func Register(pass *Pass) {
+	if len(pass.Name) == 0 {
+		panic(fmt.Errorf("unable to register pass; missing pass name"))
+	}
+	if pass.Run == nil {
+		panic(fmt.Errorf("unable to register pass %q; missing Run function", pass.Name))
+	}
+	passesMu.Lock()
+	defer passesMu.Unlock()
+	if _, ok := passes[pass.Name]; ok {
+		panic(fmt.Errorf("pass %q already registered", pass.Name))
+	}
+	passes[pass.Name] = pass
+}
+
+// LookupPass returns the registered pass with the given name. The boolean
+// return value indicates success.
+func LookupPass(name string) (*Pass, bool) {
+	passesMu.RLock()
+	defer passesMu.RUnlock()
+	pass, ok := passes[name]
+	return pass, ok
+}
+
+// Passes returns the registered passes, sorted by name.
+func Passes() []*Pass {
+	passesMu.RLock()
+	defer passesMu.RUnlock()
+	var ps []*Pass
+	for _, pass := range passes {
+		ps = append(ps, pass)
+	}
+	sort.Slice(ps, func(i, j int) bool {
+		return ps[i].Name < ps[j].Name
+	})
+	return ps
+}
+
+// --- [ Builtin passes ] ------------------------------------------------------
+
+func init() {
+	Register(&Pass{
+		Name: "globaldce",
+		Desc: "remove unused global variables, functions, aliases and IFuncs",
+		Run: func(m *ir.Module) (bool, error) {
+			return GlobalDCE(m), nil
+		},
+	})
+	Register(&Pass{
+		Name: "internalize",
+		Desc: "give definitions other than @main internal linkage",
+		Run: func(m *ir.Module) (bool, error) {
+			return Internalize(m, []string{"main"}), nil
+		},
+	})
+	Register(&Pass{
+		Name: "mergefunc",
+		Desc: "merge structurally equal functions",
+		Run: func(m *ir.Module) (bool, error) {
+			return MergeFunctions(m), nil
+		},
+	})
+	Register(&Pass{
+		Name: "strip-debug",
+		Desc: "remove debug information",
+		Run: func(m *ir.Module) (bool, error) {
+			return debuginfo.Strip(m), nil
+		},
+	})
+	Register(&Pass{
+		Name: "strip-dead-metadata",
+		Desc: "remove unreachable metadata definitions",
+		Run: func(m *ir.Module) (bool, error) {
+			return irutil.StripUnreachableMetadata(m) > 0, nil
+		},
+	})
+	Register(&Pass{
+		Name: "dedup-metadata",
+		Desc: "merge identical metadata definitions",
+		Run: func(m *ir.Module) (bool, error) {
+			return irutil.DedupMetadata(m) > 0, nil
+		},
+	})
+	Register(&Pass{
+		Name: "renumber-metadata",
+		Desc: "assign consecutive IDs to metadata definitions",
+		Run: func(m *ir.Module) (bool, error) {
+			irutil.RenumberMetadata(m)
+			return true, nil
+		},
+	})
+	Register(&Pass{
+		Name: "break-crit-edges",
+		Desc: "split critical control flow edges",
+		Run: func(m *ir.Module) (bool, error) {
+			changed := false
+			for _, f := range m.Funcs {
+				if len(f.Blocks) > 0 && irutil.SplitCriticalEdges(f) > 0 {
+					changed = true
+				}
+			}
+			return changed, nil
+		},
+	})
+}
@@ -0,0 +1,69 @@
+package transform
+
+import (
+	"testing"
+
+	"github.com/llir/llvm/asm"
+	"github.com/llir/llvm/ir"
+)
+
+func TestRegister(t *testing.T) {
+	// Register a custom pass which removes function declarations.
+	Register(&Pass{
+		Name: "test-strip-decls",
+		Desc: "remove function declarations",
+		Run: func(m *ir.Module) (bool, error) {
+			var funcs []*ir.Function
+			for _, f := range m.Funcs {
+				if len(f.Blocks) > 0 {
+					funcs = append(funcs, f)
+				}
+			}
+			changed := len(funcs) != len(m.Funcs)
+			m.Funcs = funcs
+			return changed, nil
+		},
+	})
+	const input = `
+declare void @decl()
+
+define void @f() {
+	ret void
+}
+`
+	const want = `define void @f() {
+; <label>:0
+	ret void
+}
+`
+	m, err := asm.ParseString("<input>", input)
+	if err != nil {
+		t.Fatalf("unable to parse module; %v", err)
+	}
+	for _, name := range []string{"test-strip-decls", "globaldce"} {
+		pass, ok := LookupPass(name)
+		if !ok {
+			t.Fatalf("unable to locate pass %q", name)
+		}
+		if _, err := pass.Run(m); err != nil {
+			t.Fatalf("pass %q failed; %v", name, err)
+		}
+	}
+	if got := m.String(); got != want {
+		t.Errorf("module mismatch; expected:\n%s\ngot:\n%s", want, got)
+	}
+	// Check that passes are listed in order.
+	ps := Passes()
+	for i := 1; i < len(ps); i++ {
+		if ps[i-1].Name >= ps[i].Name {
+			t.Errorf("passes not sorted by name; %q listed before %q", ps[i-1].Name, ps[i].Name)
+		}
+	}
+	// Check that duplicate pass names are rejected.
+	defer func() {
+		if e := recover(); e == nil {
+			t.Errorf("expected panic on duplicate registration of pass %q", "globaldce")
+		}
+	}()
+	Register(&Pass{Name: "globaldce", Run: func(m *ir.Module) (bool, error) { return false, nil }})
+}
@@ -15,6 +15,7 @@ var (
 	// Basic types.
 	Void     = &VoidType{}     // void
 	MMX      = &MMXType{}      // x86_mmx
+	AMX      = &AMXType{}      // x86_amx
 	Label    = &LabelType{}    // label
 	Token    = &TokenType{}    // token
 	Metadata = &MetadataType{} // metadata
@@ -27,6 +28,7 @@ var (
 	I128 = &IntType{BitSize: 128} // i128
 	// Floating-point types.
 	Half     = &FloatType{Kind: FloatKindHalf}     // half
+	BFloat   = &FloatType{Kind: FloatKindBFloat}   // bfloat
 	Float    = &FloatType{Kind: FloatKindFloat}    // float
 	Double   = &FloatType{Kind: FloatKindDouble}   // double
 	X86FP80  = &FloatType{Kind: FloatKindX86FP80}  // x86_fp80
@@ -43,6 +45,18 @@ var (
 
 // Convenience functions.
 
+// IsInt reports whether the given type is an integer type.
+func IsInt(t Type) bool {
+	_, ok := t.(*IntType)
+	return ok
+}
+
+// IsFloat reports whether the given type is a floating-point type.
+func IsFloat(t Type) bool {
+	_, ok := t.(*FloatType)
+	return ok
+}
+
 // IsPointer reports whether the given type is a pointer type.
 func IsPointer(t Type) bool {
 	_, ok := t.(*PointerType)
@@ -63,6 +77,7 @@ func Equal(t, u Type) bool {
 //    *types.IntType        // https://godoc.org/github.com/llir/llvm/ir/types#IntType
 //    *types.FloatType      // https://godoc.org/github.com/llir/llvm/ir/types#FloatType
 //    *types.MMXType        // https://godoc.org/github.com/llir/llvm/ir/types#MMXType
+//    *types.AMXType        // https://godoc.org/github.com/llir/llvm/ir/types#AMXType
 //    *types.PointerType    // https://godoc.org/github.com/llir/llvm/ir/types#PointerType
 //    *types.VectorType     // https://godoc.org/github.com/llir/llvm/ir/types#VectorType
 //    *types.LabelType      // https://godoc.org/github.com/llir/llvm/ir/types#LabelType
@@ -313,6 +328,9 @@ const (
 	FloatKindX86FP80 // x86_fp80
 	// 128-bit floating point type (IBM extended double).
 	FloatKindPPCFP128 // ppc_fp128
+	// 16-bit floating-point type (brain floating-point; 8-bit exponent, 7-bit
+	// mantissa).
+	FloatKindBFloat // bfloat
 )
 
 // --- [ MMX types ] -----------------------------------------------------------
@@ -355,6 +373,47 @@ func (t *MMXType) SetName(name string) {
 	t.TypeName = name
 }
 
+// --- [ AMX types ] -----------------------------------------------------------
+
+// AMXType is an LLVM IR AMX type, which represents a value held in an x86 AMX
+// tile register.
+type AMXType struct {
+	// Type name; or empty if not present.
+	TypeName string
+}
+
+// Equal reports whether t and u are of equal type.
+func (t *AMXType) Equal(u Type) bool {
+	if _, ok := u.(*AMXType); ok {
+		return true
+	}
+	return false
+}
+
+// String returns the string representation of the AMX type.
+func (t *AMXType) String() string {
+	if len(t.TypeName) > 0 {
+		return enc.Local(t.TypeName)
+	}
+	return t.Def()
+}
+
+// Def returns the LLVM syntax representation of the definition of the type.
+func (t *AMXType) Def() string {
+	// 'x86_amx'
+	return "x86_amx"
+}
+
+// Name returns the type name of the type.
+func (t *AMXType) Name() string {
+	return t.TypeName
+}
+
+// SetName sets the type name of the type.
+func (t *AMXType) SetName(name string) {
+	t.TypeName = name
+}
+
 // --- [ Pointer types ] -------------------------------------------------------
 
 // PointerType is an LLVM IR pointer type.
@@ -426,7 +485,10 @@ func (a AddrSpace) String() string {
 type VectorType struct {
 	// Type name; or empty if not present.
 	TypeName string
-	// Vector length.
+	// Scalable vector type; the total number of elements is a runtime multiple
+	// (vscale) of Len.
+	Scalable bool
+	// Vector length; or minimum vector length of scalable vectors.
 	Len uint64
 	// Element type.
 	ElemType Type
@@ -441,9 +503,22 @@ func NewVector(len uint64, elemType Type) *VectorType {
 	}
 }
 
+// NewScalableVector returns a new scalable vector type based on the given
+// minimum vector length and element type.
+func NewScalableVector(len uint64, elemType Type) *VectorType {
+	return &VectorType{
+		Scalable: true,
+		Len:      len,
+		ElemType: elemType,
+	}
+}
+
 // Equal reports whether t and u are of equal type.
 func (t *VectorType) Equal(u Type) bool {
 	if u, ok := u.(*VectorType); ok {
+		if t.Scalable != u.Scalable {
+			return false
+		}
 		if t.Len != u.Len {
 			return false
 		}
@@ -463,6 +538,13 @@ func (t *VectorType) String() string {
 // Def returns the LLVM syntax representation of the definition of the type.
 func (t *VectorType) Def() string {
 	// '<' Len=UintLit 'x' Elem=Type '>'
+	//
+	// Scalable vector type.
+	//
+	//    '<' 'vscale' 'x' Len=UintLit 'x' Elem=Type '>'
+	if t.Scalable {
+		return fmt.Sprintf("<vscale x %d x %v>", t.Len, t.ElemType)
+	}
 	return fmt.Sprintf("<%d x %v>", t.Len, t.ElemType)
 }
 
@@ -47,6 +47,41 @@ func TestIntTypeEqual(t *testing.T) {
 	}
 }
 
+func TestVectorTypeEqual(t *testing.T) {
+	golden := []struct {
+		t    *VectorType
+		u    *VectorType
+		want bool
+	}{
+		{
+			t:    NewVector(4, I32),
+			u:    &VectorType{Len: 4, ElemType: I32},
+			want: true,
+		},
+		{
+			t:    NewVector(4, I32),
+			u:    NewVector(8, I32),
+			want: false,
+		},
+		{
+			t:    NewScalableVector(4, I32),
+			u:    &VectorType{Scalable: true, Len: 4, ElemType: I32},
+			want: true,
+		},
+		{
+			t:    NewScalableVector(4, I32),
+			u:    NewVector(4, I32),
+			want: false,
+		},
+	}
+	for _, g := range golden {
+		got := g.t.Equal(g.u)
+		if g.want != got {
+			t.Errorf("vector equality mismatch between `%s` and `%s`; expected %t, got %t", g.t.Def(), g.u.Def(), g.want, got)
+		}
+	}
+}
+
 func TestStructTypeEqual(t *testing.T) {
 	// Identified (named) struct types are uniqued by type names, not by
 	// structural identity.
@@ -123,6 +158,7 @@ var (
 	_ Type = (*IntType)(nil)
 	_ Type = (*FloatType)(nil)
 	_ Type = (*MMXType)(nil)
+	_ Type = (*AMXType)(nil)
 	_ Type = (*PointerType)(nil)
 	_ Type = (*VectorType)(nil)
 	_ Type = (*LabelType)(nil)
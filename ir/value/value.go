@@ -44,3 +44,38 @@ type Named interface {
 	// SetName sets the name of the value.
 	SetName(name string)
 }
+
+// User is an LLVM IR value, instruction or terminator which uses other values
+// as operands.
+//
+// A User has one of the following underlying types.
+//
+//    ir.Instruction   // https://godoc.org/github.com/llir/llvm/ir#Instruction
+//    ir.Terminator    // https://godoc.org/github.com/llir/llvm/ir#Terminator
+type User interface {
+	// Operands returns a mutable list of operands of the user. Basic block
+	// operands (e.g. branch targets) and optional operands which are not present
+	// are omitted.
+	Operands() []*Use
+}
+
+// Use is a mutable operand slot of a user.
+type Use struct {
+	// Get returns the operand value.
+	Get func() Value
+	// Set replaces the operand value. Set panics if the value is not of the
+	// type of the operand slot (e.g. a non-constant switch case comparand).
+	Set func(v Value)
+}
+
+// NewUse returns a new operand slot of the given value field.
+func NewUse(p *Value) *Use {
+	return &Use{
+		Get: func() Value {
+			return *p
+		},
+		Set: func(v Value) {
+			*p = v
+		},
+	}
+}